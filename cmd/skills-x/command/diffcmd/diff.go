@@ -0,0 +1,129 @@
+// Package diffcmd implements the diff command for skills-x
+package diffcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/skilldiff"
+	"github.com/spf13/cobra"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+	colorRed    = "\033[31m"
+	colorGray   = "\033[90m"
+	colorBold   = "\033[1m"
+)
+
+var flagTarget string
+
+var loadSkillDiff = tui.LoadSkillDiff
+
+// NewCommand creates the diff command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <skill_name>",
+		Short: i18n.T("cmd_diff_short"),
+		Long:  i18n.T("cmd_diff_long"),
+		Args:  cobra.ExactArgs(1),
+		RunE:  runDiff,
+	}
+
+	cmd.Flags().StringVarP(&flagTarget, "target", "t", "", i18n.T("cmd_diff_flag_target"))
+
+	return cmd
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	targetDir := flagTarget
+	if targetDir == "" {
+		// Default: ~/.claude/skills, same as update
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("cannot determine home directory: %w", err)
+		}
+		targetDir = filepath.Join(home, ".claude", "skills")
+	}
+
+	d, err := loadSkillDiff(targetDir, args[0])
+	if err != nil {
+		return err
+	}
+
+	printDiff(d)
+	return nil
+}
+
+// printDiff writes the commit log, file list and unified diffs to stdout
+func printDiff(d *tui.SkillDiff) {
+	local := d.LocalCommit
+	if local == "" {
+		local = "?"
+	}
+	fmt.Printf("%s%s%s  %s%s → %s%s  %s%s%s\n\n",
+		colorBold, d.Name, colorReset,
+		colorCyan, local, d.RemoteCommit, colorReset,
+		colorGray, d.Repo, colorReset)
+
+	if d.LogErr != nil {
+		fmt.Printf("%s%s%s\n\n", colorYellow, i18n.Tf("diff_log_unavailable", d.LogErr), colorReset)
+	} else if len(d.Log) > 0 {
+		fmt.Println(i18n.T("diff_commits"))
+		for _, l := range d.Log {
+			fmt.Printf("  %s\n", l)
+		}
+		fmt.Println()
+	}
+
+	if len(d.Changes) == 0 {
+		fmt.Printf("%s%s%s\n", colorGreen, i18n.T("diff_no_changes"), colorReset)
+		return
+	}
+
+	fmt.Println(i18n.T("diff_files"))
+	for _, c := range d.Changes {
+		fmt.Printf("  %s%s %s%s\n", statusColor(c.Status), c.Status.Symbol(), c.Path, colorReset)
+	}
+
+	for _, c := range d.Changes {
+		fmt.Println()
+		if c.Binary {
+			fmt.Printf("%s%s%s\n", colorGray, i18n.Tf("diff_binary", c.Path), colorReset)
+			continue
+		}
+		for _, l := range strings.Split(strings.TrimSuffix(c.Diff, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"):
+				fmt.Printf("%s%s%s\n", colorBold, l, colorReset)
+			case strings.HasPrefix(l, "@@"):
+				fmt.Printf("%s%s%s\n", colorCyan, l, colorReset)
+			case strings.HasPrefix(l, "+"):
+				fmt.Printf("%s%s%s\n", colorGreen, l, colorReset)
+			case strings.HasPrefix(l, "-"):
+				fmt.Printf("%s%s%s\n", colorRed, l, colorReset)
+			default:
+				fmt.Println(l)
+			}
+		}
+	}
+}
+
+// statusColor picks the file-list color for a change status
+func statusColor(s skilldiff.Status) string {
+	switch s {
+	case skilldiff.Added:
+		return colorGreen
+	case skilldiff.Removed:
+		return colorRed
+	default:
+		return colorYellow
+	}
+}
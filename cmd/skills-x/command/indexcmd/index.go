@@ -0,0 +1,135 @@
+// Package indexcmd implements the index command, which lists a project's
+// installed skills in its AGENTS.md or CLAUDE.md
+package indexcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/pkg/products"
+	"github.com/castle-x/skills-x/pkg/skillindex"
+	"github.com/spf13/cobra"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorGray   = "\033[90m"
+)
+
+var (
+	flagFiles []string
+	flagDir   string
+)
+
+// NewCommand creates the index command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: i18n.T("cmd_index_short"),
+		Long:  i18n.T("cmd_index_long"),
+		Args:  cobra.NoArgs,
+		RunE:  runIndex,
+	}
+
+	cmd.Flags().StringArrayVarP(&flagFiles, "file", "f", []string{skillindex.DefaultFile}, i18n.T("cmd_index_flag_file"))
+	cmd.Flags().StringVarP(&flagDir, "dir", "d", "", i18n.T("cmd_index_flag_dir"))
+
+	return cmd
+}
+
+func runIndex(cmd *cobra.Command, args []string) error {
+	projectDir := flagDir
+	if projectDir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		projectDir = cwd
+	}
+
+	entries, warnings, err := skillindex.Collect(projectDir)
+	if err != nil {
+		return err
+	}
+	printWarnings(warnings)
+	if len(entries) == 0 {
+		fmt.Printf("%s%s%s\n", colorGray, i18n.T("index_no_skills"), colorReset)
+	}
+	for _, file := range flagFiles {
+		changed, err := skillindex.Update(indexPath(projectDir, file), entries)
+		if err != nil {
+			return err
+		}
+		if changed {
+			fmt.Printf("%s✓ %s%s\n", colorGreen, i18n.Tf("index_updated", file, len(entries)), colorReset)
+		} else {
+			fmt.Printf("%s%s%s\n", colorGray, i18n.Tf("index_unchanged", file, len(entries)), colorReset)
+		}
+	}
+	return nil
+}
+
+// AutoRefresh refreshes the files named by SKILLS_X_INDEX in the projects
+// the given skills directories belong to. Commands that change installed
+// skills call it with their targets when they are done; it only prints when
+// a file changed. Global skills directories and directories of no known
+// product have no project and are skipped.
+func AutoRefresh(targetDirs ...string) {
+	seen := make(map[string]bool)
+	for _, dir := range targetDirs {
+		p := products.ForDir(dir)
+		if p == nil {
+			continue
+		}
+		projectDir, ok := p.ProjectRoot(dir)
+		if !ok || seen[projectDir] {
+			continue
+		}
+		seen[projectDir] = true
+		AutoRefreshProject(projectDir)
+	}
+}
+
+// AutoRefreshProject refreshes the files named by SKILLS_X_INDEX in
+// projectDir
+func AutoRefreshProject(projectDir string) {
+	files := skillindex.AutoFiles()
+	if len(files) == 0 {
+		return
+	}
+	entries, warnings, err := skillindex.Collect(projectDir)
+	if err == nil {
+		printWarnings(warnings)
+		for _, file := range files {
+			var changed bool
+			if changed, err = skillindex.Update(indexPath(projectDir, file), entries); err != nil {
+				break
+			}
+			if changed {
+				fmt.Printf("%s%s%s\n", colorGray, i18n.Tf("index_updated", file, len(entries)), colorReset)
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s⚠ %s: %v%s\n", colorYellow, i18n.T("index_refresh_failed"), err, colorReset)
+	}
+}
+
+// printWarnings reports the skills Collect left out
+func printWarnings(warnings []string) {
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "%s⚠ %s: %s%s\n", colorYellow, i18n.T("index_skill_skipped"), w, colorReset)
+	}
+}
+
+// indexPath resolves a --file value against the project directory
+func indexPath(projectDir string, file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(projectDir, file)
+}
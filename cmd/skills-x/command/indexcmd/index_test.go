@@ -0,0 +1,38 @@
+package indexcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/skillindex"
+)
+
+func TestAutoRefresh_UsesTargetProject(t *testing.T) {
+	t.Setenv(skillindex.AutoEnv, "AGENTS.md")
+	project := t.TempDir()
+	skillDir := filepath.Join(project, ".claude", "skills", "pdf")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: pdf\ndescription: Read PDFs\n---\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A directory of no product has no project to refresh
+	other := t.TempDir()
+	AutoRefresh(other)
+	if _, err := os.Stat(filepath.Join(other, "AGENTS.md")); !os.IsNotExist(err) {
+		t.Fatalf("AGENTS.md written outside a project: %v", err)
+	}
+
+	AutoRefresh(filepath.Join(project, ".claude", "skills"))
+	data, err := os.ReadFile(filepath.Join(project, "AGENTS.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "- **pdf** (`.claude/skills/pdf`): Read PDFs") {
+		t.Errorf("AGENTS.md =\n%s", data)
+	}
+}
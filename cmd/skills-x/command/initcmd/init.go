@@ -4,16 +4,27 @@ package initcmd
 import (
 	"bufio"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
+	"github.com/castle-x/skills-x/cmd/skills-x/command/indexcmd"
 	"github.com/castle-x/skills-x/cmd/skills-x/errmsg"
 	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/cmd/skills-x/targets"
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/adapter"
 	"github.com/castle-x/skills-x/pkg/discover"
+	"github.com/castle-x/skills-x/pkg/fsutil"
 	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/lockfile"
+	"github.com/castle-x/skills-x/pkg/products"
 	"github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/store"
+	"github.com/castle-x/skills-x/pkg/versioncheck"
+	"github.com/castle-x/skills-x/pkg/workpool"
 	"github.com/spf13/cobra"
 )
 
@@ -33,12 +44,17 @@ var (
 	flagTarget  string
 	flagForce   bool
 	flagRefresh bool
+	flagJobs    int
+	flagLink    string
+	flagTargets targets.Flags
+
+	linkMode store.Mode
 )
 
 // NewCommand creates the init command
 func NewCommand() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "init [skill_name]",
+		Use:   "init [skill_name[@ref]]",
 		Short: i18n.T("cmd_init_short"),
 		Long:  i18n.T("cmd_init_long"),
 		RunE:  runInit,
@@ -48,28 +64,38 @@ func NewCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&flagTarget, "target", "t", "", i18n.T("cmd_init_flag_target"))
 	cmd.Flags().BoolVarP(&flagForce, "force", "f", false, i18n.T("cmd_init_flag_force"))
 	cmd.Flags().BoolVar(&flagRefresh, "refresh", false, i18n.T("cmd_init_flag_refresh"))
+	cmd.Flags().IntVarP(&flagJobs, "jobs", "j", workpool.DefaultWorkers, i18n.T("cmd_init_flag_jobs"))
+	cmd.Flags().StringVar(&flagLink, "link", string(store.DefaultMode()), i18n.T("cmd_init_flag_link"))
+	flagTargets.Register(cmd)
 
 	return cmd
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
-	// Determine target directory
-	targetDir := flagTarget
-	if targetDir == "" {
-		// Default to current directory
-		cwd, err := os.Getwd()
-		if err != nil {
-			return err
-		}
-		targetDir = cwd
+	var err error
+	if linkMode, err = store.ParseMode(flagLink); err != nil {
+		return err
 	}
 
-	// Create target directory if not exists
-	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return errmsg.TargetDirCreateError(targetDir)
+	// Determine target directories; default to the current directory
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	ts, err := flagTargets.Resolve(flagTarget, cwd, cwd)
+	if err != nil {
+		return err
 	}
+	// Keep the project's skills index current (SKILLS_X_INDEX)
+	defer indexcmd.AutoRefresh(targets.Dirs(ts)...)
 
-	fmt.Printf("%s%s%s\n", colorCyan, i18n.Tf("init_target_dir", targetDir), colorReset)
+	// Create target directories if not exist
+	for _, t := range ts {
+		if err := os.MkdirAll(t.Dir, 0755); err != nil {
+			return errmsg.TargetDirCreateError(t.Dir)
+		}
+		fmt.Printf("%s%s%s\n", colorCyan, i18n.Tf("init_target_dir", t.Dir), colorReset)
+	}
 
 	// Show refresh warning if enabled
 	if flagRefresh {
@@ -86,18 +112,62 @@ func runInit(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "⚠ %s\n", w)
 	}
 
+	// Installs are staged and swapped in; replaced skills are kept as
+	// backups for "skills-x rollback", and Ctrl+C restores them
+	tx := installtx.New()
+	defer tx.AbortOnInterrupt()()
+
 	if flagAll {
-		return initAll(reg, targetDir)
+		clones := gitutil.NewCloneSet()
+		for i, t := range ts {
+			if len(ts) > 1 {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf("%s%s%s\n", colorBold, targets.Label(t), colorReset)
+			}
+			initAll(tx, reg, t, clones)
+		}
+		tx.Commit()
+		return nil
 	}
 
 	if len(args) == 0 {
 		return errmsg.MissingArgument("skill_name")
 	}
 
-	return initRegistrySkill(reg, args[0], targetDir)
+	name, ref := parseSkillArg(args[0])
+	if err := initRegistrySkill(tx, reg, name, ref, ts); err != nil {
+		return err
+	}
+	tx.Commit()
+	return nil
 }
 
-func initRegistrySkill(reg *registry.Registry, name string, targetDir string) error {
+// parseSkillArg splits "name@ref" into the skill name and an optional
+// tag/commit pin.
+func parseSkillArg(arg string) (string, string) {
+	name, ref, _ := strings.Cut(arg, "@")
+	return name, ref
+}
+
+// warnDeprecated prints a warning, and the replacement if any, for a
+// deprecated skill
+func warnDeprecated(skill *registry.Skill) {
+	if !skill.Deprecated {
+		return
+	}
+	msg := i18n.Tf("init_deprecated", skill.Name)
+	if skill.ReplacedBy != "" {
+		msg += " " + i18n.Tf("init_replaced_by", skill.ReplacedBy)
+	}
+	fmt.Printf("%s⚠ %s%s\n", colorYellow, msg, colorReset)
+}
+
+// initRegistrySkill installs one skill. ref overrides any ref pinned in the
+// registry; empty means use the registry pin, or the branch tip if none.
+// The skill is fetched once and installed into every target.
+func initRegistrySkill(tx *installtx.Tx, reg *registry.Registry, name string, ref string, ts []products.Target) error {
 	// Find skill in registry
 	matches := reg.FindSkillsWithConflict(name)
 
@@ -133,14 +203,40 @@ func initRegistrySkill(reg *registry.Registry, name string, targetDir string) er
 		source = matches[0].Source
 	}
 
+	if !strings.EqualFold(skill.Name, name) {
+		fmt.Printf("%s%s%s\n", colorGray, i18n.Tf("init_alias_resolved", name, skill.Name), colorReset)
+	}
+	if !skill.IsCompatible() {
+		if !flagForce {
+			return fmt.Errorf("%s", i18n.Tf("init_requires_version", skill.Name, skill.MinVersion, versioncheck.Running))
+		}
+		fmt.Printf("%s⚠ %s%s\n", colorYellow, i18n.Tf("init_requires_version", skill.Name, skill.MinVersion, versioncheck.Running), colorReset)
+	}
+	warnDeprecated(skill)
+	if ts = supportedTargets(skill, ts); len(ts) == 0 {
+		return fmt.Errorf("%s", i18n.Tf("init_no_supported_product", skill.Name))
+	}
+
 	// Clone the repository
 	fmt.Printf("%s%s %s...%s\n", colorGray, i18n.T("init_cloning"), source.GetRepoShortName(), colorReset)
 
 	var result *gitutil.CloneResult
 	var err error
 
-	// For large repos (marked with skip_fetch), use sparse checkout
-	if source.SkipFetch && skill.Path != "" {
+	if ref == "" {
+		ref = source.SkillRef(skill)
+	}
+
+	if source.UsesArchive() {
+		// Download only the skill's directory from the source archive
+		archiveRef := source.ArchiveRef(ref)
+		result, err = gitutil.FetchArchive(source.ArchiveURL(archiveRef), source.Repo, archiveRef, skill.FetchPaths(), flagRefresh)
+	} else if ref != "" {
+		// Pinned to a tag or commit: fetch exactly that ref
+		fmt.Printf("%s%s%s\n", colorGray, i18n.Tf("init_pinned_ref", ref), colorReset)
+		result, err = gitutil.CloneRepoAtRef(source.GetGitURL(), source.Repo, ref)
+	} else if source.SkipFetch && skill.Path != "" {
+		// For large repos (marked with skip_fetch), use sparse checkout
 		// Use sparse checkout for large repos - only fetch the specific skill path
 		result, err = gitutil.SparseCloneRepo(source.GetGitURL(), source.Repo, source.Branch, []string{skill.Path})
 	} else {
@@ -170,6 +266,36 @@ func initRegistrySkill(reg *registry.Registry, name string, targetDir string) er
 		return fmt.Errorf("%s: %s", i18n.T("init_skill_path_not_found"), skill.Name)
 	}
 
+	for _, t := range ts {
+		if len(ts) > 1 {
+			fmt.Printf("%s%s%s\n", colorBold, targets.Label(t), colorReset)
+		}
+		if err := installInto(tx, t.Dir, skill, source, ref, result.TempDir, skillPath); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("  %s%s%s\n", colorGray, i18n.Tf("init_from_source", source.Repo), colorReset)
+
+	return nil
+}
+
+// supportedTargets drops the targets of products the skill's registry
+// products list excludes, warning about each
+func supportedTargets(skill *registry.Skill, ts []products.Target) []products.Target {
+	var kept []products.Target
+	for _, t := range ts {
+		if t.Product != nil && !skill.SupportsProduct(t.Product.Name) {
+			fmt.Printf("%s⚠ %s%s\n", colorYellow, i18n.Tf("init_product_unsupported", skill.Name, t.Product.Name), colorReset)
+			continue
+		}
+		kept = append(kept, t)
+	}
+	return kept
+}
+
+// installInto copies a fetched skill into targetDir, asking before it
+// overwrites an existing install unless --force is set
+func installInto(tx *installtx.Tx, targetDir string, skill *registry.Skill, source *registry.Source, ref string, cloneDir string, skillPath string) error {
 	dstPath := filepath.Join(targetDir, skill.Name)
 
 	// Check if already exists
@@ -185,126 +311,225 @@ func initRegistrySkill(reg *registry.Registry, name string, targetDir string) er
 		fmt.Printf("%s%s%s\n", colorYellow, i18n.Tf("init_downloading", skill.Name), colorReset)
 	}
 
-	// Copy skill to target
-	if err := copyDir(skillPath, dstPath); err != nil {
+	if err := installSkill(tx, skillPath, dstPath); err != nil {
 		return errmsg.CopyFailed(skill.Name)
 	}
 
-	fmt.Printf("%s✓ %s%s\n", colorGreen, i18n.Tf("init_success", skill.Name), colorReset)
-	fmt.Printf("  %s%s%s\n", colorGray, i18n.Tf("init_from_source", source.Repo), colorReset)
+	recordInstall(targetDir, skill, source, ref, cloneDir, skillPath)
+	if err := adapter.Apply(targetDir, skill.Name); err != nil {
+		fmt.Printf("%s  ⚠ %s: %v%s\n", colorYellow, i18n.Tf("adapter_failed", skill.Name), err, colorReset)
+	}
 
+	fmt.Printf("%s✓ %s%s\n", colorGreen, i18n.Tf("init_success", skill.Name), colorReset)
 	return nil
 }
 
-func initAll(reg *registry.Registry, targetDir string) error {
-	sources := reg.GetAllSources()
+// installSkill copies the skill at skillPath to dstPath, or links it to the
+// shared store. The copy is staged and only replaces an existing install
+// once complete; the old version becomes the rollback backup.
+func installSkill(tx *installtx.Tx, skillPath string, dstPath string) error {
+	return tx.Install(dstPath, func(stageDir string) error {
+		if err := fsutil.CopyDir(skillPath, stageDir); err != nil {
+			return err
+		}
+		_, err := store.Share(stageDir, dstPath, linkMode)
+		return err
+	})
+}
 
+// initAll installs every registry skill meant for t's product into t.
+// clones is shared between targets so each repository is fetched once.
+func initAll(tx *installtx.Tx, reg *registry.Registry, t products.Target, clones *gitutil.CloneSet) {
+	targetDir := t.Dir
+	product := ""
+	if t.Product != nil {
+		product = t.Product.Name
+	}
+	jobs, unsupported := planInitAll(reg, product)
+	if unsupported > 0 {
+		fmt.Printf("%s⚠ %s%s\n", colorYellow, i18n.Tf("init_all_product_skipped", unsupported, product), colorReset)
+	}
+
+	var mu sync.Mutex // guards the counters and keeps output lines whole
 	count := 0
 	skipped := 0
 	errors := 0
-
-	for _, source := range sources {
-		fmt.Printf("\n%s📦 %s%s\n", colorBold, source.Repo, colorReset)
-
-		// For large repos, use sparse checkout for each skill
-		if source.SkipFetch {
-			// Install each skill with sparse checkout
-			for _, skill := range source.Skills {
-				if skill.Path == "" {
-					fmt.Printf("%s  ⚠ %s: %s%s\n", colorYellow, skill.Name, i18n.T("init_skill_path_not_found"), colorReset)
-					errors++
-					continue
-				}
-
-				result, err := gitutil.SparseCloneRepo(source.GetGitURL(), source.Repo, source.Branch, []string{skill.Path})
-				if err != nil {
-					fmt.Printf("%s  ⚠ %s: %v%s\n", colorYellow, skill.Name, err, colorReset)
-					errors++
-					continue
-				}
-
-				skillPath := filepath.Join(result.TempDir, skill.Path)
-				dstPath := filepath.Join(targetDir, skill.Name)
-
-				if dirExists(dstPath) && !flagForce {
-					fmt.Printf("%s  - %s%s\n", colorGray, i18n.Tf("init_skipped", skill.Name), colorReset)
-					skipped++
-					continue
-				}
-
-				if !dirExists(skillPath) {
-					fmt.Printf("%s  ⚠ %s: %s%s\n", colorYellow, skill.Name, i18n.T("init_skill_path_not_found"), colorReset)
-					errors++
-					continue
-				}
-
-				if err := copyDir(skillPath, dstPath); err != nil {
-					fmt.Printf("%s  ✗ %s: %v%s\n", colorRed, skill.Name, err, colorReset)
-					errors++
-					continue
-				}
-
-				fmt.Printf("%s  ✓ %s%s\n", colorGreen, skill.Name, colorReset)
-				count++
-			}
-			continue
+	report := func(format string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Printf(format, args...)
+	}
+
+	fmt.Printf("%s%s%s\n\n", colorGray, i18n.Tf("init_all_parallel", len(jobs), flagJobs), colorReset)
+
+	// Jobs are interleaved across sources, so the first wave clones
+	// different repositories in parallel; later skills of a repository
+	// reuse its checkout through the clone set.
+	workpool.Run(len(jobs), flagJobs, func(i int) {
+		job := jobs[i]
+		skill := job.skill
+		dstPath := filepath.Join(targetDir, skill.Name)
+
+		if dirExists(dstPath) && !flagForce {
+			report("%s  - %s%s\n", colorGray, i18n.Tf("init_skipped", skill.Name), colorReset)
+			mu.Lock()
+			skipped++
+			mu.Unlock()
+			return
 		}
 
-		// Clone repository for normal repos
-		result, err := gitutil.CloneRepoWithRefresh(source.GetGitURL(), source.Repo, source.Branch, flagRefresh)
+		repoDir, skillPath, err := job.fetch(clones)
+		if err == nil {
+			err = installSkill(tx, skillPath, dstPath)
+		}
 		if err != nil {
-			fmt.Printf("%s  ⚠ %s: %v%s\n", colorYellow, i18n.T("init_clone_failed"), err, colorReset)
+			report("%s  ✗ %s (%s): %v%s\n", colorRed, skill.Name, job.source.Repo, err, colorReset)
+			mu.Lock()
 			errors++
-			continue
+			mu.Unlock()
+			return
+		}
+		recordInstall(targetDir, &skill, job.source, job.ref, repoDir, skillPath)
+		if err := adapter.Apply(targetDir, skill.Name); err != nil {
+			report("%s  ⚠ %s: %v%s\n", colorYellow, i18n.Tf("adapter_failed", skill.Name), err, colorReset)
 		}
 
-		// Install each skill
-		for _, skill := range source.Skills {
-			var skillPath string
-			if skill.Path != "" {
-				skillPath = filepath.Join(result.TempDir, skill.Path)
-			} else {
-				discovered, _ := findSkillInRepo(result.TempDir, skill.Name)
-				if discovered != nil {
-					skillPath = discovered.Path
-				}
-			}
+		report("%s  ✓ %s%s %s%s%s\n", colorGreen, skill.Name, colorReset, colorGray, job.source.Repo, colorReset)
+		mu.Lock()
+		count++
+		mu.Unlock()
+	})
 
-			if skillPath == "" || !dirExists(skillPath) {
-				fmt.Printf("%s  ⚠ %s: %s%s\n", colorYellow, skill.Name, i18n.T("init_skill_path_not_found"), colorReset)
-				errors++
-				continue
-			}
+	fmt.Printf("\n%s%s%s\n", colorGreen, i18n.Tf("init_all_success", count), colorReset)
+	if skipped > 0 {
+		fmt.Printf("%s%s%s\n", colorYellow, i18n.Tf("init_all_skipped", skipped), colorReset)
+	}
+	if errors > 0 {
+		fmt.Printf("%s%s%s\n", colorRed, i18n.Tf("init_all_errors", errors), colorReset)
+	}
+}
 
-			dstPath := filepath.Join(targetDir, skill.Name)
+// initJob is one skill to install by init --all
+type initJob struct {
+	source *registry.Source
+	skill  registry.Skill
+	ref    string // pinned tag or commit ("" for the branch tip)
+}
 
-			// Check if exists
-			if dirExists(dstPath) && !flagForce {
-				fmt.Printf("%s  - %s%s\n", colorGray, i18n.Tf("init_skipped", skill.Name), colorReset)
-				skipped++
+// planInitAll lists every registry skill, taking one skill from each source
+// in turn so that consecutive jobs need different repositories. Deprecated
+// skills and skills that need a newer skills-x are left out, and so are
+// skills not meant for product ("" for any); those are counted.
+func planInitAll(reg *registry.Registry, product string) ([]initJob, int) {
+	var perSource [][]initJob
+	unsupported := 0
+	for _, source := range reg.GetAllSources() {
+		var jobs []initJob
+		for _, skill := range source.Skills {
+			if skill.Deprecated || !skill.IsCompatible() {
 				continue
 			}
-
-			if err := copyDir(skillPath, dstPath); err != nil {
-				fmt.Printf("%s  ✗ %s: %v%s\n", colorRed, skill.Name, err, colorReset)
-				errors++
+			if !skill.SupportsProduct(product) {
+				unsupported++
 				continue
 			}
+			jobs = append(jobs, initJob{source: source, skill: skill, ref: source.SkillRef(&skill)})
+		}
+		perSource = append(perSource, jobs)
+	}
 
-			fmt.Printf("%s  ✓ %s%s\n", colorGreen, skill.Name, colorReset)
-			count++
+	var jobs []initJob
+	for round := 0; ; round++ {
+		added := false
+		for _, sj := range perSource {
+			if round < len(sj) {
+				jobs = append(jobs, sj[round])
+				added = true
+			}
+		}
+		if !added {
+			return jobs, unsupported
 		}
 	}
+}
 
-	fmt.Printf("\n%s%s%s\n", colorGreen, i18n.Tf("init_all_success", count), colorReset)
-	if skipped > 0 {
-		fmt.Printf("%s%s%s\n", colorYellow, i18n.Tf("init_all_skipped", skipped), colorReset)
+// fetch checks out the job's repository and locates the skill in it. Large
+// repositories (skip_fetch) get a sparse checkout of just the skill; pinned
+// skills get a checkout of their ref.
+func (job initJob) fetch(clones *gitutil.CloneSet) (repoDir string, skillPath string, err error) {
+	source := job.source
+	skill := job.skill
+
+	var result *gitutil.CloneResult
+	switch {
+	case source.UsesArchive():
+		archiveRef := source.ArchiveRef(job.ref)
+		result, err = clones.FetchArchive(source.ArchiveURL(archiveRef), source.Repo, archiveRef, skill.FetchPaths(), flagRefresh)
+	case source.SkipFetch && skill.Path == "":
+		return "", "", fmt.Errorf("%s", i18n.T("init_skill_path_not_found"))
+	case job.ref != "":
+		result, err = clones.CloneRepoAtRef(source.GetGitURL(), source.Repo, job.ref)
+	case source.SkipFetch:
+		result, err = clones.SparseCloneRepo(source.GetGitURL(), source.Repo, source.Branch, []string{skill.Path})
+	default:
+		result, err = clones.CloneRepoWithRefresh(source.GetGitURL(), source.Repo, source.Branch, flagRefresh)
 	}
-	if errors > 0 {
-		fmt.Printf("%s%s%s\n", colorRed, i18n.Tf("init_all_errors", errors), colorReset)
+	if err != nil {
+		return "", "", err
 	}
 
-	return nil
+	if skill.Path != "" {
+		skillPath = filepath.Join(result.TempDir, skill.Path)
+	} else if discovered, _ := findSkillInRepo(result.TempDir, skill.Name); discovered != nil {
+		skillPath = discovered.Path
+	}
+	if skillPath == "" || !dirExists(skillPath) {
+		return "", "", fmt.Errorf("%s", i18n.T("init_skill_path_not_found"))
+	}
+	return result.TempDir, skillPath, nil
+}
+
+// recordInstall writes the skill's .skills-x-meta.json and pins it in the
+// target directory's skills-x.lock. Failures are reported as warnings: the
+// skill itself was installed fine.
+func recordInstall(targetDir string, skill *registry.Skill, source *registry.Source, ref string, cloneDir string, skillPath string) {
+	// Archives served without their commit are pinned by URL instead
+	commit, archive, pinErr := gitutil.GetLockPin(cloneDir)
+
+	relPath, err := filepath.Rel(cloneDir, skillPath)
+	if err != nil || relPath == "." {
+		relPath = ""
+	}
+
+	shortCommit, _ := gitutil.GetRepoHeadCommit(cloneDir)
+	treeHash, _ := gitutil.GetTreeHash(cloneDir, relPath)
+	_ = tui.WriteSkillMeta(filepath.Join(targetDir, skill.Name), tui.SkillMeta{
+		Skill:       skill.Name,
+		Source:      source.Name,
+		Repo:        source.Repo,
+		Commit:      shortCommit,
+		Ref:         ref,
+		TreeHash:    treeHash,
+		ResolvedRef: gitutil.ArchiveRef(cloneDir),
+	})
+
+	if pinErr != nil {
+		fmt.Printf("%s  ⚠ %s: %v%s\n", colorYellow, i18n.T("lock_write_failed"), pinErr, colorReset)
+		return
+	}
+	entry := lockfile.Entry{
+		Name:    skill.Name,
+		Source:  source.Name,
+		Repo:    source.Repo,
+		Path:    filepath.ToSlash(relPath),
+		Branch:  source.Branch,
+		Ref:     ref,
+		Commit:  commit,
+		Archive: archive,
+	}
+	if err := lockfile.Record(targetDir, entry); err != nil {
+		fmt.Printf("%s  ⚠ %s: %v%s\n", colorYellow, i18n.T("lock_write_failed"), err, colorReset)
+	}
 }
 
 // findSkillInRepo searches for a skill by name in common locations
@@ -392,78 +617,3 @@ func promptChoice(max int) int {
 
 	return choice - 1
 }
-
-// copyDir copies a directory from source to destination
-// Follows symlinks to copy actual content
-func copyDir(srcPath string, dstPath string) error {
-	// Remove existing directory
-	os.RemoveAll(dstPath)
-
-	return filepath.WalkDir(srcPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		// Calculate relative path
-		relPath, err := filepath.Rel(srcPath, path)
-		if err != nil {
-			return err
-		}
-
-		targetPath := filepath.Join(dstPath, relPath)
-
-		// Check if it's a symlink
-		if d.Type()&os.ModeSymlink != 0 {
-			// Resolve symlink to get actual path
-			realPath, err := filepath.EvalSymlinks(path)
-			if err != nil {
-				return nil // Skip broken symlinks
-			}
-
-			info, err := os.Stat(realPath)
-			if err != nil {
-				return nil // Skip if can't stat
-			}
-
-			if info.IsDir() {
-				// Recursively copy symlinked directory
-				return copyDir(realPath, targetPath)
-			}
-			// Copy symlinked file
-			return copyFile(realPath, targetPath)
-		}
-
-		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0755)
-		}
-
-		// Copy file
-		return copyFile(path, targetPath)
-	})
-}
-
-// copyFile copies a single file
-func copyFile(srcPath string, dstPath string) error {
-	// Ensure parent directory exists
-	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
-		return err
-	}
-
-	// Open source file
-	srcFile, err := os.Open(srcPath)
-	if err != nil {
-		return err
-	}
-	defer srcFile.Close()
-
-	// Create destination file
-	dstFile, err := os.Create(dstPath)
-	if err != nil {
-		return err
-	}
-	defer dstFile.Close()
-
-	// Copy content
-	_, err = io.Copy(dstFile, srcFile)
-	return err
-}
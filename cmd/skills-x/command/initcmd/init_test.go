@@ -1,6 +1,15 @@
 package initcmd
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/products"
+	"github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/versioncheck"
+)
 
 func TestNewCommandDoesNotExposeIncludeXFlag(t *testing.T) {
 	cmd := NewCommand()
@@ -8,3 +17,115 @@ func TestNewCommandDoesNotExposeIncludeXFlag(t *testing.T) {
 		t.Fatalf("include-x flag should be removed")
 	}
 }
+
+func TestPlanInitAll_InterleavesSources(t *testing.T) {
+	reg := &registry.Registry{Sources: map[string]*registry.Source{
+		"a": {Name: "a", Repo: "github.com/x/a", Skills: []registry.Skill{{Name: "a1"}, {Name: "a2"}, {Name: "a3"}}},
+		"b": {Name: "b", Repo: "github.com/x/b", Skills: []registry.Skill{{Name: "b1"}}},
+	}}
+
+	jobs, _ := planInitAll(reg, "")
+	var names []string
+	for _, j := range jobs {
+		names = append(names, j.skill.Name)
+	}
+	if len(names) != 4 {
+		t.Fatalf("jobs = %v; want 4", names)
+	}
+	// The first two jobs need different repositories
+	if jobs[0].source.Repo == jobs[1].source.Repo {
+		t.Fatalf("first jobs share a repository: %v", names)
+	}
+}
+
+func TestPlanInitAll_SkipsDeprecatedSkills(t *testing.T) {
+	orig := versioncheck.Running
+	defer func() { versioncheck.Running = orig }()
+	versioncheck.Running = "1.0.0"
+
+	reg := &registry.Registry{Sources: map[string]*registry.Source{
+		"a": {Name: "a", Repo: "github.com/x/a", Skills: []registry.Skill{
+			{Name: "keep"},
+			{Name: "old", Deprecated: true, ReplacedBy: "keep"},
+			{Name: "future", MinVersion: "999.0.0"},
+		}},
+	}}
+
+	jobs, _ := planInitAll(reg, "")
+	if len(jobs) != 1 || jobs[0].skill.Name != "keep" {
+		t.Fatalf("jobs = %+v; want only keep", jobs)
+	}
+}
+
+func TestInstallSkill_StagesAndKeepsBackup(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("# v2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(t.TempDir(), "pdf")
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dst, "SKILL.md"), []byte("# v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed copy leaves the installed version alone
+	tx := installtx.New()
+	if err := installSkill(tx, filepath.Join(src, "missing"), dst); err == nil {
+		t.Fatal("installSkill from a missing directory should fail")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dst, "SKILL.md")); string(data) != "# v1\n" {
+		t.Fatalf("SKILL.md = %q after a failed install; want v1", data)
+	}
+
+	if err := installSkill(tx, src, dst); err != nil {
+		t.Fatal(err)
+	}
+	tx.Commit()
+	if data, _ := os.ReadFile(filepath.Join(dst, "SKILL.md")); string(data) != "# v2\n" {
+		t.Fatalf("SKILL.md = %q; want v2", data)
+	}
+	if data, _ := os.ReadFile(filepath.Join(installtx.BackupPath(dst), "SKILL.md")); string(data) != "# v1\n" {
+		t.Fatalf("backup SKILL.md = %q; want v1", data)
+	}
+}
+
+func TestPlanInitAll_SkipsSkillsForOtherProducts(t *testing.T) {
+	reg := &registry.Registry{Sources: map[string]*registry.Source{
+		"a": {Name: "a", Repo: "github.com/x/a", Skills: []registry.Skill{
+			{Name: "any"},
+			{Name: "cursor-only", Products: []string{"cursor"}},
+		}},
+	}}
+
+	jobs, unsupported := planInitAll(reg, "Claude Code")
+	if len(jobs) != 1 || jobs[0].skill.Name != "any" || unsupported != 1 {
+		t.Fatalf("jobs = %+v, unsupported = %d; want only any, one skipped", jobs, unsupported)
+	}
+	if jobs, unsupported = planInitAll(reg, "Cursor"); len(jobs) != 2 || unsupported != 0 {
+		t.Fatalf("jobs = %+v, unsupported = %d; want both for Cursor", jobs, unsupported)
+	}
+}
+
+func TestSupportedTargets_SkipsExcludedProducts(t *testing.T) {
+	claude := products.GetProductByName("claude-code")
+	cursor := products.GetProductByName("cursor")
+	if claude == nil || cursor == nil {
+		t.Fatal("built-in products missing")
+	}
+	ts := []products.Target{
+		{Product: claude, Scope: products.ScopeProject, Dir: filepath.Join(t.TempDir(), ".claude", "skills")},
+		{Product: cursor, Scope: products.ScopeProject, Dir: filepath.Join(t.TempDir(), ".cursor", "skills")},
+	}
+	skill := &registry.Skill{Name: "cursor-only", Products: []string{"cursor"}}
+
+	got := supportedTargets(skill, ts)
+	if len(got) != 1 || got[0].Product != cursor {
+		t.Fatalf("supportedTargets = %+v; want only the Cursor target", got)
+	}
+	if got := supportedTargets(&registry.Skill{Name: "any"}, ts); len(got) != 2 {
+		t.Fatalf("supportedTargets = %+v; want both targets for an unrestricted skill", got)
+	}
+}
@@ -0,0 +1,249 @@
+// Package installcmd implements the install command, which restores the
+// skills pinned in skills-x.lock
+package installcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/command/indexcmd"
+	"github.com/castle-x/skills-x/cmd/skills-x/errmsg"
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/adapter"
+	"github.com/castle-x/skills-x/pkg/fsutil"
+	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/lockfile"
+	"github.com/castle-x/skills-x/pkg/products"
+	"github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+// ANSI colors
+const (
+	colorReset  = "\033[0m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+	colorRed    = "\033[31m"
+	colorGray   = "\033[90m"
+)
+
+var (
+	flagTarget string
+	flagForce  bool
+	flagLink   string
+
+	linkMode store.Mode
+)
+
+var (
+	cloneRepoAtCommit = gitutil.CloneRepoAtCommit
+	fetchArchive      = gitutil.FetchArchive
+)
+
+// NewCommand creates the install command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: i18n.T("cmd_install_short"),
+		Long:  i18n.T("cmd_install_long"),
+		Args:  cobra.NoArgs,
+		RunE:  runInstall,
+	}
+
+	cmd.Flags().StringVarP(&flagTarget, "target", "t", "", i18n.T("cmd_install_flag_target"))
+	cmd.Flags().BoolVarP(&flagForce, "force", "f", false, i18n.T("cmd_install_flag_force"))
+	cmd.Flags().StringVar(&flagLink, "link", string(store.DefaultMode()), i18n.T("cmd_install_flag_link"))
+
+	return cmd
+}
+
+func runInstall(cmd *cobra.Command, args []string) error {
+	var err error
+	if linkMode, err = store.ParseMode(flagLink); err != nil {
+		return err
+	}
+
+	dirs, err := lockDirs()
+	if err != nil {
+		return err
+	}
+	// Keep the project's skills index current (SKILLS_X_INDEX)
+	defer indexcmd.AutoRefresh(dirs...)
+
+	// Each restore replaces the installed copy only once the new one is
+	// complete; the replaced version is kept for "skills-x rollback"
+	tx := installtx.New()
+	defer tx.AbortOnInterrupt()()
+
+	restored := 0
+	upToDate := 0
+	failed := 0
+
+	for i, targetDir := range dirs {
+		lf, err := lockfile.Load(targetDir)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s%s%s\n\n", colorCyan, i18n.Tf("install_from_lock", lockfile.FilePath(targetDir)), colorReset)
+
+		if len(lf.Skills) == 0 {
+			fmt.Println(i18n.T("install_lock_empty"))
+			continue
+		}
+
+		for _, entry := range lf.Skills {
+			skipped, err := restoreEntry(tx, entry, targetDir)
+			switch {
+			case err != nil:
+				fmt.Printf("%s  ✗ %s: %v%s\n", colorRed, entry.Name, err, colorReset)
+				failed++
+			case skipped:
+				fmt.Printf("%s  - %s%s\n", colorGray, i18n.Tf("install_up_to_date", entry.Name, pinLabel(entry)), colorReset)
+				upToDate++
+			default:
+				fmt.Printf("%s  ✓ %s%s\n", colorGreen, i18n.Tf("install_restored", entry.Name, pinLabel(entry)), colorReset)
+				restored++
+			}
+		}
+	}
+	tx.Commit()
+
+	fmt.Printf("\n%s%s%s\n", colorGreen, i18n.Tf("install_summary", restored, upToDate), colorReset)
+	if failed > 0 {
+		return fmt.Errorf("%s", i18n.Tf("install_failed_count", failed))
+	}
+	return nil
+}
+
+// lockDirs returns the directories to restore: --target, or else every
+// project skills directory of the current project holding a skills-x.lock
+// (the lockfile lives next to the skills it pins), or else the current
+// directory itself
+func lockDirs() ([]string, error) {
+	if flagTarget != "" {
+		if !lockfile.Exists(flagTarget) {
+			return nil, errmsg.LockfileNotFound(lockfile.FilePath(flagTarget))
+		}
+		return []string{flagTarget}, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, p := range products.All() {
+		dir := p.Dir(products.ScopeProject, cwd)
+		if !seen[dir] && lockfile.Exists(dir) {
+			dirs = append(dirs, dir)
+		}
+		seen[dir] = true
+	}
+	if len(dirs) > 0 {
+		return dirs, nil
+	}
+	if !lockfile.Exists(cwd) {
+		return nil, errmsg.LockfileNotFound(lockfile.FilePath(cwd))
+	}
+	return []string{cwd}, nil
+}
+
+// restoreEntry installs one lockfile entry at its pinned commit through tx.
+// Returns skipped=true when the installed copy already matches the pin.
+func restoreEntry(tx *installtx.Tx, entry lockfile.Entry, targetDir string) (bool, error) {
+	if entry.Repo == "" || (entry.Commit == "" && entry.Archive == "") {
+		return false, fmt.Errorf("%s", i18n.T("install_entry_incomplete"))
+	}
+
+	dstPath := filepath.Join(targetDir, entry.Name)
+	if !flagForce && entry.Commit != "" && fsutil.DirExists(dstPath) {
+		if meta, err := tui.ReadSkillMeta(dstPath); err == nil && meta.Commit != "" && strings.HasPrefix(entry.Commit, meta.Commit) {
+			return true, nil
+		}
+	}
+
+	var result *gitutil.CloneResult
+	var err error
+	if entry.Commit != "" {
+		source := &registry.Source{Name: entry.Source, Repo: entry.Repo, Branch: entry.Branch}
+		result, err = cloneRepoAtCommit(source.GetGitURL(), entry.Repo, entry.Commit)
+	} else {
+		// Pinned by archive URL only: there is no commit to compare the
+		// installed copy against, so it is downloaded again
+		var paths []string
+		if entry.Path != "" {
+			paths = []string{entry.Path}
+		}
+		result, err = fetchArchive(entry.Archive, entry.Repo, entry.Ref, paths, true)
+	}
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", i18n.T("init_clone_failed"), err)
+	}
+
+	skillPath := filepath.Join(result.TempDir, filepath.FromSlash(entry.Path))
+	if !fsutil.FileExists(filepath.Join(skillPath, "SKILL.md")) {
+		return false, fmt.Errorf("%s: %s", i18n.T("init_skill_path_not_found"), entry.Path)
+	}
+
+	commit, err := gitutil.GetRepoHeadCommit(result.TempDir)
+	if err != nil {
+		commit = shortCommit(entry.Commit)
+	}
+	treeHash, _ := gitutil.GetTreeHash(result.TempDir, entry.Path)
+	err = tx.Install(dstPath, func(stageDir string) error {
+		if err := fsutil.CopyDir(skillPath, stageDir); err != nil {
+			return err
+		}
+		if _, err := store.Share(stageDir, dstPath, linkMode); err != nil {
+			return err
+		}
+		return tui.WriteSkillMeta(stageDir, tui.SkillMeta{
+			Skill:       entry.Name,
+			Source:      entry.Source,
+			Repo:        entry.Repo,
+			Commit:      commit,
+			Ref:         entry.Ref,
+			TreeHash:    treeHash,
+			ResolvedRef: gitutil.ArchiveRef(result.TempDir),
+		})
+	})
+	if err != nil {
+		return false, errmsg.CopyFailed(entry.Name)
+	}
+	if err := adapter.Apply(targetDir, entry.Name); err != nil {
+		fmt.Printf("%s  ⚠ %s: %v%s\n", colorYellow, i18n.Tf("adapter_failed", entry.Name), err, colorReset)
+	}
+
+	return false, nil
+}
+
+// pinLabel is what an entry is pinned to, for display: its commit, or its
+// archive ref when the commit is unknown
+func pinLabel(entry lockfile.Entry) string {
+	switch {
+	case entry.Commit != "":
+		return shortCommit(entry.Commit)
+	case entry.Ref != "":
+		return entry.Ref
+	}
+	return entry.Archive
+}
+
+// shortCommit abbreviates a commit SHA for display
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
@@ -0,0 +1,160 @@
+package installcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/lockfile"
+)
+
+func TestRestoreEntry_InstallsPinnedCommitOnce(t *testing.T) {
+	cloneDir := t.TempDir()
+	skillSrc := filepath.Join(cloneDir, "skills", "pdf")
+	if err := os.MkdirAll(skillSrc, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(skillSrc, "SKILL.md"), []byte("# pdf\n"), 0644); err != nil {
+		t.Fatalf("write SKILL.md: %v", err)
+	}
+
+	commit := strings.Repeat("c", 40)
+	origClone, origForce := cloneRepoAtCommit, flagForce
+	defer func() { cloneRepoAtCommit, flagForce = origClone, origForce }()
+	flagForce = false
+
+	calls := 0
+	cloneRepoAtCommit = func(gitURL string, repoName string, gotCommit string) (*gitutil.CloneResult, error) {
+		calls++
+		if gotCommit != commit {
+			t.Fatalf("clone requested commit %q; want %q", gotCommit, commit)
+		}
+		return &gitutil.CloneResult{TempDir: cloneDir, Repo: repoName}, nil
+	}
+
+	targetDir := t.TempDir()
+	entry := lockfile.Entry{
+		Name:   "pdf",
+		Source: "anthropic",
+		Repo:   "github.com/anthropics/skills",
+		Path:   "skills/pdf",
+		Commit: commit,
+	}
+
+	skipped, err := restoreEntry(installtx.New(), entry, targetDir)
+	if err != nil {
+		t.Fatalf("restoreEntry: %v", err)
+	}
+	if skipped {
+		t.Fatal("first restore should not be skipped")
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, "pdf", "SKILL.md")); err != nil {
+		t.Fatalf("SKILL.md not installed: %v", err)
+	}
+	meta, err := tui.ReadSkillMeta(filepath.Join(targetDir, "pdf"))
+	if err != nil {
+		t.Fatalf("read meta: %v", err)
+	}
+	if !strings.HasPrefix(commit, meta.Commit) {
+		t.Fatalf("meta commit %q is not a prefix of %q", meta.Commit, commit)
+	}
+
+	skipped, err = restoreEntry(installtx.New(), entry, targetDir)
+	if err != nil {
+		t.Fatalf("second restoreEntry: %v", err)
+	}
+	if !skipped {
+		t.Fatal("second restore should be skipped as up to date")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 clone, got %d", calls)
+	}
+
+	// A forced restore replaces the copy through a transaction, keeping
+	// the old one for rollback
+	flagForce = true
+	if _, err := restoreEntry(installtx.New(), entry, targetDir); err != nil {
+		t.Fatalf("forced restoreEntry: %v", err)
+	}
+	if !installtx.HasBackup(filepath.Join(targetDir, "pdf")) {
+		t.Fatal("forced restore should keep the replaced copy as a backup")
+	}
+}
+
+func TestRestoreEntry_ArchiveWithoutCommit(t *testing.T) {
+	archiveDir := t.TempDir()
+	skillSrc := filepath.Join(archiveDir, "skills", "pdf")
+	if err := os.MkdirAll(skillSrc, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(skillSrc, "SKILL.md"), []byte("# pdf\n"), 0644); err != nil {
+		t.Fatalf("write SKILL.md: %v", err)
+	}
+
+	const url = "https://example.com/skills/archive/v1.tar.gz"
+	origClone, origFetch := cloneRepoAtCommit, fetchArchive
+	defer func() { cloneRepoAtCommit, fetchArchive = origClone, origFetch }()
+	cloneRepoAtCommit = func(string, string, string) (*gitutil.CloneResult, error) {
+		t.Fatal("an archive entry should not be cloned")
+		return nil, nil
+	}
+	fetchArchive = func(archive string, repoName string, ref string, paths []string, refresh bool) (*gitutil.CloneResult, error) {
+		if archive != url || len(paths) != 1 || paths[0] != "skills/pdf" {
+			t.Fatalf("fetchArchive(%q, %v); want %q for skills/pdf", archive, paths, url)
+		}
+		return &gitutil.CloneResult{TempDir: archiveDir, Repo: repoName}, nil
+	}
+
+	targetDir := t.TempDir()
+	entry := lockfile.Entry{
+		Name:    "pdf",
+		Source:  "mirror",
+		Repo:    "example.com/skills",
+		Path:    "skills/pdf",
+		Ref:     "v1",
+		Archive: url,
+	}
+	if _, err := restoreEntry(installtx.New(), entry, targetDir); err != nil {
+		t.Fatalf("restoreEntry: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(targetDir, "pdf", "SKILL.md")); err != nil {
+		t.Fatalf("SKILL.md not installed: %v", err)
+	}
+	if got := pinLabel(entry); got != "v1" {
+		t.Fatalf("pinLabel = %q; want v1", got)
+	}
+}
+
+func TestLockDirs_FindsProjectSkillsDirs(t *testing.T) {
+	project := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	origTarget := flagTarget
+	defer func() { flagTarget = origTarget }()
+	flagTarget = ""
+
+	if _, err := lockDirs(); err == nil {
+		t.Fatal("lockDirs should fail without any lockfile")
+	}
+
+	entry := lockfile.Entry{Name: "pdf", Repo: "github.com/anthropics/skills", Commit: strings.Repeat("c", 40)}
+	for _, dir := range []string{".claude/skills", ".cursor/skills"} {
+		if err := lockfile.Record(filepath.Join(project, dir), entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dirs, err := lockDirs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirs) != 2 || !strings.HasSuffix(dirs[0], filepath.Join(".claude", "skills")) || !strings.HasSuffix(dirs[1], filepath.Join(".cursor", "skills")) {
+		t.Fatalf("lockDirs = %v; want the Claude Code and Cursor skills directories", dirs)
+	}
+}
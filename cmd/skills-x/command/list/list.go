@@ -10,6 +10,7 @@ import (
 	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
 	"github.com/castle-x/skills-x/pkg/discover"
 	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/products"
 	"github.com/castle-x/skills-x/pkg/registry"
 	"github.com/spf13/cobra"
 )
@@ -29,6 +30,7 @@ const (
 var (
 	flagVerbose bool
 	flagFetch   bool // changed: default to NOT fetch, use --fetch to enable
+	flagProduct string
 )
 
 // NewCommand creates the list command
@@ -43,6 +45,7 @@ func NewCommand() *cobra.Command {
 
 	cmd.Flags().BoolVarP(&flagVerbose, "verbose", "v", false, i18n.T("cmd_list_flag_verbose"))
 	cmd.Flags().BoolVar(&flagFetch, "fetch", false, i18n.T("cmd_list_flag_fetch"))
+	cmd.Flags().StringVar(&flagProduct, "product", "", i18n.T("cmd_list_flag_product"))
 
 	return cmd
 }
@@ -59,9 +62,17 @@ type skillDisplay struct {
 	Description string
 	Version     string
 	FromRepo    bool // true if dynamically fetched from repo
+	Deprecated  bool
+	ReplacedBy  string
+	MinVersion  string // set only when the running skills-x is too old
+	Aliases     []string
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if flagProduct != "" && !knownProduct(flagProduct) {
+		return fmt.Errorf("%s", i18n.Tf("list_unknown_product", flagProduct))
+	}
+
 	// Load registry
 	reg, warnings, err := registry.LoadWithUser()
 	if err != nil {
@@ -149,12 +160,15 @@ func getSkillsFromRegistry(source *registry.Source) []skillDisplay {
 	lang := i18n.GetLanguage()
 	skills := make([]skillDisplay, 0, len(source.Skills))
 	for _, s := range source.Skills {
-		skills = append(skills, skillDisplay{
+		if !s.SupportsProduct(flagProduct) {
+			continue
+		}
+		skills = append(skills, annotate(skillDisplay{
 			Name:        s.Name,
 			Description: s.GetDescription(lang),
 			Version:     s.Version,
 			FromRepo:    false,
-		})
+		}, &s))
 	}
 
 	// Sort by name
@@ -188,15 +202,26 @@ func fetchSkillsFromRepo(source *registry.Source) ([]skillDisplay, error) {
 		return nil, err
 	}
 
-	// Convert to display format
+	// Convert to display format, keeping registry metadata for known skills
+	known := make(map[string]*registry.Skill, len(source.Skills))
+	for i := range source.Skills {
+		known[source.Skills[i].Name] = &source.Skills[i]
+	}
 	skills := make([]skillDisplay, 0, len(discovered))
 	for _, d := range discovered {
-		skills = append(skills, skillDisplay{
+		display := skillDisplay{
 			Name:        d.Name,
 			Description: d.Description,
 			Version:     d.Version,
 			FromRepo:    true,
-		})
+		}
+		if s, ok := known[d.Name]; ok {
+			if !s.SupportsProduct(flagProduct) {
+				continue
+			}
+			display = annotate(display, s)
+		}
+		skills = append(skills, display)
 	}
 
 	// Sort by name
@@ -214,8 +239,14 @@ func printSourceHeader(source *registry.Source) {
 		license = fmt.Sprintf(" %s(%s)%s", colorGray, source.License, colorReset)
 	}
 
-	fmt.Printf("%s📦 %s%s%s%s\n",
-		colorBold, colorCyan, source.Repo, colorReset, license)
+	// Show where the source came from unless it is the official registry
+	origin := ""
+	if source.Origin != "" && source.Origin != registry.OfficialRemote {
+		origin = fmt.Sprintf(" %s[%s]%s", colorYellow, source.Origin, colorReset)
+	}
+
+	fmt.Printf("%s📦 %s%s%s%s%s\n",
+		colorBold, colorCyan, source.Repo, colorReset, license, origin)
 }
 
 // printSkill prints a skill entry
@@ -236,8 +267,46 @@ func printSkill(skill skillDisplay) {
 		desc = desc[:maxDescLen-3] + "..."
 	}
 
-	fmt.Printf("   %s%-35s%s%s %s%s%s\n",
+	// Deprecation and version requirement markers
+	marks := ""
+	if skill.Deprecated {
+		mark := i18n.T("list_deprecated")
+		if skill.ReplacedBy != "" {
+			mark += " → " + skill.ReplacedBy
+		}
+		marks += fmt.Sprintf(" %s[%s]%s", colorYellow, mark, colorReset)
+	}
+	if skill.MinVersion != "" {
+		marks += fmt.Sprintf(" %s[%s]%s", colorYellow, i18n.Tf("list_requires_version", skill.MinVersion), colorReset)
+	}
+
+	fmt.Printf("   %s%-35s%s%s %s%s%s%s\n",
 		colorCyan, skill.Name, colorReset,
 		version,
-		colorGray, desc, colorReset)
+		colorGray, desc, colorReset, marks)
+
+	if flagVerbose && len(skill.Aliases) > 0 {
+		fmt.Printf("   %s%-35s %s: %s%s\n", colorGray, "", i18n.T("list_aliases"), strings.Join(skill.Aliases, ", "), colorReset)
+	}
+}
+
+// annotate copies the registry metadata list shows onto a display entry
+func annotate(display skillDisplay, s *registry.Skill) skillDisplay {
+	display.Deprecated = s.Deprecated
+	display.ReplacedBy = s.ReplacedBy
+	display.Aliases = s.Aliases
+	if !s.IsCompatible() {
+		display.MinVersion = s.MinVersion
+	}
+	return display
+}
+
+// knownProduct reports whether name is one of the supported products
+func knownProduct(name string) bool {
+	for _, p := range products.All() {
+		if registry.SameProduct(p.Name, name) {
+			return true
+		}
+	}
+	return false
 }
@@ -20,14 +20,14 @@ func newAddCommand() *cobra.Command {
 	var allFlag bool
 
 	cmd := &cobra.Command{
-		Use:   "add <owner/repo[/skill]> | <local-path> [skill-path]",
+		Use:   "add <owner/repo[/skill]> | <repo-url> | <local-path> [skill-path]",
 		Short: i18n.T("cmd_registry_add_short"),
 		Long:  i18n.T("cmd_registry_add_long"),
 		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Legacy 2-arg mode.
 			if len(args) == 2 {
-				return runAddSingle(args[0], args[1], descFlag, descZhFlag, forceFlag)
+				return runAddSingle(canonicalRepo(args[0]), args[1], descFlag, descZhFlag, forceFlag)
 			}
 
 			parsed := skillvalidator.ParseInput(args[0])
@@ -151,7 +151,7 @@ func runAddFind(repo, skillHint, descFlag, descZhFlag string, force bool) error
 		}
 
 		// Retry with the manual path as an explicit path.
-		return runAddSingle("github.com/"+repoShortName(repo), manualPath, descFlag, descZhFlag, force)
+		return runAddSingle(repo, manualPath, descFlag, descZhFlag, force)
 	}
 
 	printDiscoveredSkill(ds)
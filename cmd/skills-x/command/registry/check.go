@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/pkg/githost"
 	"github.com/castle-x/skills-x/pkg/registry"
 	"github.com/castle-x/skills-x/pkg/skillvalidator"
 	"github.com/spf13/cobra"
@@ -12,14 +13,14 @@ import (
 
 func newCheckCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "check <owner/repo[/skill]> | <local-path> [skill-path]",
+		Use:   "check <owner/repo[/skill]> | <repo-url> | <local-path> [skill-path]",
 		Short: i18n.T("cmd_registry_check_short"),
 		Long:  i18n.T("cmd_registry_check_long"),
 		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Legacy 2-arg mode: check github.com/owner/repo skills/x
 			if len(args) == 2 {
-				return runCheckSingle(args[0], args[1])
+				return runCheckSingle(canonicalRepo(args[0]), args[1])
 			}
 
 			parsed := skillvalidator.ParseInput(args[0])
@@ -165,8 +166,17 @@ func printDiscoveredSkill(ds *skillvalidator.DiscoveredSkill) {
 	}
 }
 
+// canonicalRepo writes a repository URL the way registry entries do,
+// leaving local paths alone
+func canonicalRepo(repo string) string {
+	if loc, ok := githost.Parse(repo); ok {
+		return loc.Canonical()
+	}
+	return repo
+}
+
 func repoShortName(repo string) string {
-	return strings.TrimPrefix(repo, "github.com/")
+	return (&registry.Source{Repo: repo}).GetRepoShortName()
 }
 
 func loadBuiltinNames() map[string][]string {
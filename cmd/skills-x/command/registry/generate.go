@@ -0,0 +1,190 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/pkg/githost"
+	pkgregistry "github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/skilldiff"
+	"github.com/castle-x/skills-x/pkg/skillvalidator"
+	"github.com/spf13/cobra"
+)
+
+// defaultGenerateFile is the official registry, relative to a checkout
+const defaultGenerateFile = "pkg/registry/registry.yaml"
+
+var (
+	flagGenerateFile       string
+	flagGenerateSource     string
+	flagGenerateWrite      bool
+	flagGenerateRefreshAll bool
+)
+
+// discoverSkills scans a repository for skills; a variable for tests
+var discoverSkills = skillvalidator.Discover
+
+// newGenerateCommand returns "registry generate", which turns the skills
+// of a repository into registry entries
+func newGenerateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate [owner/repo | repo-url]",
+		Short: i18n.T("cmd_registry_generate_short"),
+		Long:  i18n.T("cmd_registry_generate_long"),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if flagGenerateRefreshAll {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		RunE: runGenerate,
+	}
+	cmd.Flags().StringVarP(&flagGenerateFile, "file", "f", defaultGenerateFile, i18n.T("flag_registry_generate_file"))
+	cmd.Flags().StringVar(&flagGenerateSource, "source", "", i18n.T("flag_registry_generate_source"))
+	cmd.Flags().BoolVarP(&flagGenerateWrite, "write", "w", false, i18n.T("flag_registry_generate_write"))
+	cmd.Flags().BoolVar(&flagGenerateRefreshAll, "refresh-all", false, i18n.T("flag_registry_generate_refresh_all"))
+	return cmd
+}
+
+// runGenerate merges discovered skills into the registry file. Without
+// --write it prints the change as a unified diff (a patch for the file)
+// and leaves the file alone; the summary goes to stderr either way.
+func runGenerate(_ *cobra.Command, args []string) error {
+	original, err := os.ReadFile(flagGenerateFile)
+	if err != nil && !(os.IsNotExist(err) && !flagGenerateRefreshAll) {
+		return err
+	}
+
+	// Tags are inferred from the file being edited, or from the built-in
+	// registry when that has nothing to learn from
+	tagSource, _ := pkgregistry.Parse(original)
+	if tagSource == nil || tagSource.TotalSkillCount() == 0 {
+		tagSource, _ = pkgregistry.Load()
+	}
+
+	type job struct{ key, repo string }
+	var jobs []job
+	if flagGenerateRefreshAll {
+		reg, err := pkgregistry.Parse(original)
+		if err != nil {
+			return fmt.Errorf("%s: %w", i18n.T("registry_update_parse_error"), err)
+		}
+		keys := make([]string, 0, len(reg.Sources))
+		for key := range reg.Sources {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			src := reg.Sources[key]
+			if _, ok := githost.Parse(src.Repo); !ok || src.SkipFetch {
+				fmt.Fprintf(os.Stderr, "- %s\n", i18n.Tf("registry_generate_skipped", key))
+				continue
+			}
+			jobs = append(jobs, job{key, src.Repo})
+		}
+	} else {
+		parsed := skillvalidator.ParseInput(args[0])
+		if parsed.Kind == skillvalidator.InputKindLocal {
+			return fmt.Errorf("%s", i18n.Tf("registry_generate_remote_only", args[0]))
+		}
+		key := flagGenerateSource
+		if key == "" {
+			key = pkgregistry.SourceKeyForRepo(parsed.Repo)
+		}
+		jobs = append(jobs, job{key, parsed.Repo})
+	}
+
+	data := original
+	failed := 0
+	for _, j := range jobs {
+		fmt.Fprintf(os.Stderr, "%s %s ...\n", i18n.T("registry_scanning"), repoShortName(j.repo))
+		skills, err := discoverForRegistry(j.repo)
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "✗ %s: %v\n", j.key, err)
+			continue
+		}
+		merged, report, err := pkgregistry.MergeGenerated(data, j.key, j.repo, skills, tagSource)
+		if err != nil {
+			return err
+		}
+		data = merged
+		printGenerateReport(report)
+	}
+
+	if flagGenerateWrite {
+		if string(data) != string(original) {
+			if err := os.WriteFile(flagGenerateFile, data, 0644); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "✓ %s\n", i18n.Tf("registry_generate_written", flagGenerateFile))
+			if _, err := os.Stat(flagGenerateFile + pkgregistry.SignatureSuffix); err == nil {
+				fmt.Fprintf(os.Stderr, "  %s\n", i18n.T("registry_generate_resign_hint"))
+			}
+		}
+	} else {
+		fmt.Print(skilldiff.Unified("a/"+flagGenerateFile, "b/"+flagGenerateFile,
+			splitLines(string(original)), splitLines(string(data)), 3))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%s", i18n.Tf("registry_generate_failed_count", failed))
+	}
+	return nil
+}
+
+// discoverForRegistry scans repo and keeps the skills that can be listed:
+// valid ones, once per name
+func discoverForRegistry(repo string) ([]pkgregistry.GeneratedSkill, error) {
+	found, err := discoverSkills(repo)
+	if err != nil {
+		return nil, err
+	}
+	var skills []pkgregistry.GeneratedSkill
+	seen := make(map[string]bool)
+	for _, ds := range found {
+		if !ds.Valid {
+			fmt.Fprintf(os.Stderr, "  ⚠ %s: %s\n", ds.Path, strings.Join(ds.Errors, "; "))
+			continue
+		}
+		if seen[ds.Name] {
+			continue
+		}
+		seen[ds.Name] = true
+		skills = append(skills, pkgregistry.GeneratedSkill{
+			Name:        ds.Name,
+			Path:        strings.ReplaceAll(ds.Path, "\\", "/"),
+			Description: ds.Description,
+			License:     ds.License,
+		})
+	}
+	return skills, nil
+}
+
+func printGenerateReport(r pkgregistry.MergeReport) {
+	if r.Unsupported != "" {
+		fmt.Fprintf(os.Stderr, "⚠ %s: %s\n", r.Source, r.Unsupported)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "✓ %s\n", i18n.Tf("registry_generate_summary", r.Source, len(r.Added), len(r.Moved), len(r.Missing)))
+	for _, name := range r.Added {
+		fmt.Fprintf(os.Stderr, "  + %s\n", name)
+	}
+	for _, name := range r.Moved {
+		fmt.Fprintf(os.Stderr, "  ~ %s\n", name)
+	}
+	for _, name := range r.Missing {
+		fmt.Fprintf(os.Stderr, "  ? %s %s\n", name, i18n.T("registry_generate_missing"))
+	}
+}
+
+// splitLines splits text into lines without their trailing newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,62 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pkgregistry "github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/skillvalidator"
+)
+
+func TestRunGenerate_RefreshAllWrites(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	file := filepath.Join(t.TempDir(), "registry.yaml")
+	original := "# keep me\nteam:\n  repo: github.com/team/skills\n  skills:\n    - name: a\n      path: skills/a\n      description: A\n\nlocal:\n  repo: /srv/skills\n"
+	if err := os.WriteFile(file, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var scanned []string
+	origDiscover := discoverSkills
+	origFile, origWrite, origAll := flagGenerateFile, flagGenerateWrite, flagGenerateRefreshAll
+	defer func() {
+		discoverSkills = origDiscover
+		flagGenerateFile, flagGenerateWrite, flagGenerateRefreshAll = origFile, origWrite, origAll
+	}()
+	discoverSkills = func(repo string) ([]skillvalidator.DiscoveredSkill, error) {
+		scanned = append(scanned, repo)
+		return []skillvalidator.DiscoveredSkill{
+			{Name: "a", Path: "skills/a", Description: "A", Valid: true},
+			{Name: "b", Path: "skills/b", Description: "B", Valid: true},
+			{Name: "broken", Path: "skills/broken", Errors: []string{"missing description"}},
+		}, nil
+	}
+	flagGenerateFile, flagGenerateWrite, flagGenerateRefreshAll = file, true, true
+
+	if err := runGenerate(nil, nil); err != nil {
+		t.Fatalf("runGenerate: %v", err)
+	}
+	if len(scanned) != 1 || scanned[0] != "github.com/team/skills" {
+		t.Errorf("scanned %v; want only the GitHub source", scanned)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# keep me\n") {
+		t.Errorf("comment lost:\n%s", data)
+	}
+	reg, err := pkgregistry.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, sk := range reg.GetSource("team").Skills {
+		names = append(names, sk.Name)
+	}
+	if strings.Join(names, ",") != "a,b" {
+		t.Errorf("team skills = %v; want a,b", names)
+	}
+}
@@ -0,0 +1,231 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/pkg/gitutil"
+	pkgregistry "github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/skillvalidator"
+	"github.com/castle-x/skills-x/pkg/userregistry"
+	"github.com/spf13/cobra"
+)
+
+// embeddedLintTarget names the registry built into the binary in findings
+const embeddedLintTarget = "<embedded>"
+
+var (
+	flagLintDeep bool
+	flagLintJSON bool
+)
+
+// lintFinding is a finding together with the file it was found in
+type lintFinding struct {
+	File string `json:"file"`
+	pkgregistry.Finding
+}
+
+type lintTarget struct {
+	name string
+	data []byte
+}
+
+// newLintCommand returns "registry lint", which checks registry files for
+// mistakes before they are published
+func newLintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [registry.yaml...]",
+		Short: i18n.T("cmd_registry_lint_short"),
+		Long:  i18n.T("cmd_registry_lint_long"),
+		RunE:  runLint,
+	}
+	cmd.Flags().BoolVar(&flagLintDeep, "deep", false, i18n.T("flag_registry_lint_deep"))
+	cmd.Flags().BoolVar(&flagLintJSON, "json", false, i18n.T("flag_registry_lint_json"))
+	return cmd
+}
+
+// runLint prints one finding per line ("file:line: severity [rule] message")
+// or a JSON array with --json. The summary goes to stderr so stdout stays
+// machine-readable; the command fails when any finding is an error.
+func runLint(_ *cobra.Command, args []string) error {
+	targets, err := lintTargets(args)
+	if err != nil {
+		return err
+	}
+
+	var findings []lintFinding
+	for _, target := range targets {
+		found := pkgregistry.Lint(target.data)
+		if flagLintDeep && pkgregistry.LintErrors(found) == 0 {
+			reg, _ := pkgregistry.Parse(target.data)
+			found = append(found, deepLint(target.name, reg)...)
+		}
+		for _, f := range found {
+			findings = append(findings, lintFinding{File: target.name, Finding: f})
+		}
+	}
+
+	if flagLintJSON {
+		if findings == nil {
+			findings = []lintFinding{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(findings); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range findings {
+			fmt.Println(formatFinding(f))
+		}
+	}
+
+	errs := 0
+	for _, f := range findings {
+		if f.Severity == pkgregistry.SeverityError {
+			errs++
+		}
+	}
+	fmt.Fprintln(os.Stderr, i18n.Tf("registry_lint_summary", len(targets), errs, len(findings)-errs))
+	if errs > 0 {
+		return fmt.Errorf("%s", i18n.Tf("registry_lint_failed", errs))
+	}
+	return nil
+}
+
+// lintTargets reads the files named on the command line. Without arguments
+// it checks the embedded registry, every cached remote registry and the
+// user registry.
+func lintTargets(args []string) ([]lintTarget, error) {
+	var targets []lintTarget
+	if len(args) > 0 {
+		for _, path := range args {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, lintTarget{path, data})
+		}
+		return targets, nil
+	}
+
+	data, err := pkgregistry.EmbeddedData()
+	if err != nil {
+		return nil, err
+	}
+	targets = append(targets, lintTarget{embeddedLintTarget, data})
+
+	remotes, err := pkgregistry.LoadRemotes()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", i18n.T("registry_remote_load_error"), err)
+	}
+	paths := []string{}
+	for _, remote := range remotes {
+		if path, err := remote.CachePath(); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	paths = append(paths, userregistry.FilePath())
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, lintTarget{path, data})
+	}
+	return targets, nil
+}
+
+func formatFinding(f lintFinding) string {
+	location := f.File
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	subject := f.Source
+	if f.Skill != "" {
+		subject += "/" + f.Skill
+	}
+	if subject != "" {
+		subject += ": "
+	}
+	return fmt.Sprintf("%s: %s [%s] %s%s", location, f.Severity, f.Rule, subject, f.Message)
+}
+
+// deepLint fetches every source the way installs do and checks that each
+// skill path exists and passes skillvalidator.Validate. Skills without a
+// path are discovered at install time and skipped.
+func deepLint(name string, reg *pkgregistry.Registry) []pkgregistry.Finding {
+	var findings []pkgregistry.Finding
+	add := func(severity, rule, source, skill, format string, args ...interface{}) {
+		findings = append(findings, pkgregistry.Finding{
+			Severity: severity, Rule: rule, Source: source, Skill: skill,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	keys := make([]string, 0, len(reg.Sources))
+	for key := range reg.Sources {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	clones := gitutil.NewCloneSet()
+	for _, key := range keys {
+		source := reg.Sources[key]
+		if !flagLintJSON {
+			fmt.Fprintln(os.Stderr, i18n.Tf("registry_lint_fetching", name, key))
+		}
+		for i := range source.Skills {
+			skill := &source.Skills[i]
+			if skill.Path == "" {
+				continue
+			}
+			result, err := fetchForLint(clones, source, skill)
+			if err != nil {
+				add(pkgregistry.SeverityError, "fetch", key, skill.Name, "%v", err)
+				continue
+			}
+			dir := filepath.Join(result.TempDir, filepath.FromSlash(skill.Path))
+			if _, err := os.Stat(dir); err != nil {
+				add(pkgregistry.SeverityError, "missing-path", key, skill.Name,
+					"path %q does not exist in %s", skill.Path, source.Repo)
+				continue
+			}
+			res, err := skillvalidator.Validate(skillvalidator.ValidateRequest{Repo: dir})
+			if err != nil {
+				add(pkgregistry.SeverityError, "invalid-skill", key, skill.Name, "%v", err)
+				continue
+			}
+			for _, e := range res.Errors {
+				add(pkgregistry.SeverityError, "invalid-skill", key, skill.Name, "%s", e)
+			}
+			if res.SkillName != "" && res.SkillName != skill.Name {
+				add(pkgregistry.SeverityWarning, "name-mismatch", key, skill.Name,
+					"SKILL.md is named %q", res.SkillName)
+			}
+		}
+	}
+	return findings
+}
+
+// fetchForLint checks out a skill's source like an install would
+func fetchForLint(clones *gitutil.CloneSet, source *pkgregistry.Source, skill *pkgregistry.Skill) (*gitutil.CloneResult, error) {
+	ref := source.SkillRef(skill)
+	switch {
+	case source.UsesArchive():
+		archiveRef := source.ArchiveRef(ref)
+		return clones.FetchArchive(source.ArchiveURL(archiveRef), source.Repo, archiveRef, skill.FetchPaths(), false)
+	case ref != "":
+		return clones.CloneRepoAtRef(source.GetGitURL(), source.Repo, ref)
+	case source.SkipFetch:
+		return clones.SparseCloneRepo(source.GetGitURL(), source.Repo, source.Branch, []string{skill.Path})
+	}
+	return clones.CloneRepoWithRefresh(source.GetGitURL(), source.Repo, source.Branch, false)
+}
@@ -19,6 +19,11 @@ func NewCommand() *cobra.Command {
 	cmd.AddCommand(newListCommand())
 	cmd.AddCommand(newRemoveCommand())
 	cmd.AddCommand(newUpdateCommand())
+	cmd.AddCommand(newRemoteCommand())
+	cmd.AddCommand(newKeygenCommand())
+	cmd.AddCommand(newSignCommand())
+	cmd.AddCommand(newLintCommand())
+	cmd.AddCommand(newGenerateCommand())
 
 	return cmd
 }
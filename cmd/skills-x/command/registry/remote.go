@@ -0,0 +1,109 @@
+package registry
+
+import (
+	"fmt"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	pkgregistry "github.com/castle-x/skills-x/pkg/registry"
+	"github.com/spf13/cobra"
+)
+
+var (
+	flagRemotePriority int
+	flagRemoteDisabled bool
+	flagRemoteKeys     []string
+)
+
+// newRemoteCommand returns "registry remote", which manages the remote
+// registries merged into the built-in one.
+func newRemoteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remote",
+		Short: i18n.T("cmd_registry_remote_short"),
+		Long:  i18n.T("cmd_registry_remote_long"),
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <name> <url>",
+		Short: i18n.T("cmd_registry_remote_add_short"),
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			remote := pkgregistry.Remote{
+				Name:     args[0],
+				URL:      args[1],
+				Priority: flagRemotePriority,
+				Enabled:  !flagRemoteDisabled,
+				Keys:     flagRemoteKeys,
+			}
+			if err := pkgregistry.AddRemote(remote); err != nil {
+				return fmt.Errorf("%s: %w", i18n.T("registry_remote_add_failed"), err)
+			}
+			fmt.Printf("✓ %s\n", i18n.Tf("registry_remote_add_success", remote.Name))
+			fmt.Printf("  %s\n", i18n.T("registry_remote_update_hint"))
+			return nil
+		},
+	}
+	addCmd.Flags().IntVar(&flagRemotePriority, "priority", 0, i18n.T("flag_registry_remote_priority"))
+	addCmd.Flags().BoolVar(&flagRemoteDisabled, "disabled", false, i18n.T("flag_registry_remote_disabled"))
+	addCmd.Flags().StringArrayVar(&flagRemoteKeys, "key", nil, i18n.T("flag_registry_remote_key"))
+
+	removeCmd := &cobra.Command{
+		Use:     "remove <name>",
+		Aliases: []string{"rm"},
+		Short:   i18n.T("cmd_registry_remote_remove_short"),
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := pkgregistry.RemoveRemote(args[0]); err != nil {
+				return fmt.Errorf("%s: %w", i18n.T("registry_remote_remove_failed"), err)
+			}
+			fmt.Printf("✓ %s\n", i18n.Tf("registry_remote_remove_success", args[0]))
+			return nil
+		},
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: i18n.T("cmd_registry_remote_list_short"),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			remotes, err := pkgregistry.LoadRemotes()
+			if err != nil {
+				return fmt.Errorf("%s: %w", i18n.T("registry_remote_load_error"), err)
+			}
+			fmt.Printf("%s (%d)\n", i18n.T("registry_remote_list_header"), len(remotes))
+			fmt.Println("────────────────────────────────────────────────")
+			for _, r := range remotes {
+				state := i18n.T("registry_remote_state_enabled")
+				if !r.Enabled {
+					state = i18n.T("registry_remote_state_disabled")
+				}
+				if keys, err := r.TrustedKeys(); err == nil && len(keys) > 0 {
+					state += ", " + i18n.Tf("registry_remote_signed", len(keys))
+				}
+				fmt.Printf("  %-16s  %s: %-4d  %s\n", r.Name, i18n.T("registry_remote_priority"), r.Priority, state)
+				fmt.Printf("  %-16s  %s\n", "", r.URL)
+			}
+			return nil
+		},
+	}
+
+	cmd.AddCommand(addCmd, removeCmd, listCmd,
+		newRemoteToggleCommand("enable", true), newRemoteToggleCommand("disable", false))
+	return cmd
+}
+
+// newRemoteToggleCommand returns "remote enable" or "remote disable"
+func newRemoteToggleCommand(use string, enabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <name>",
+		Short: i18n.T("cmd_registry_remote_" + use + "_short"),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := pkgregistry.SetRemoteEnabled(args[0], enabled); err != nil {
+				return fmt.Errorf("%s: %w", i18n.T("registry_remote_save_failed"), err)
+			}
+			fmt.Printf("✓ %s\n", i18n.Tf("registry_remote_"+use+"_success", args[0]))
+			return nil
+		},
+	}
+}
@@ -1,19 +1,23 @@
 package registry
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/gitauth"
 	pkgregistry "github.com/castle-x/skills-x/pkg/registry"
 	"github.com/spf13/cobra"
 )
 
-const remoteRegistryURL = "https://raw.githubusercontent.com/castle-x/skills-x/main/pkg/registry/registry.yaml"
+var httpClient = &http.Client{Timeout: 30 * time.Second}
 
 func newUpdateCommand() *cobra.Command {
 	return &cobra.Command{
@@ -25,44 +29,270 @@ func newUpdateCommand() *cobra.Command {
 	}
 }
 
+// runUpdate refreshes the cache of every enabled remote and prints what
+// changed. A failing remote does not stop the others; the command fails at
+// the end if any did.
 func runUpdate(_ *cobra.Command, _ []string) error {
-	cachePath, err := pkgregistry.CachedRegistryPath()
+	remotes, err := pkgregistry.LoadRemotes()
 	if err != nil {
-		return fmt.Errorf("%s: %w", i18n.T("registry_update_path_error"), err)
+		return fmt.Errorf("%s: %w", i18n.T("registry_remote_load_error"), err)
 	}
 
-	fmt.Printf("%s\n", i18n.T("registry_update_fetching"))
+	failed := 0
+	var added []string
+	for _, remote := range remotes {
+		if !remote.Enabled {
+			continue
+		}
+		fmt.Printf("%s\n", i18n.Tf("registry_update_fetching", remote.Name, remote.URL))
+		res, err := updateRemote(remote)
+		if err != nil {
+			failed++
+			fmt.Printf("✗ %s: %v\n", remote.Name, err)
+			continue
+		}
+		if res.notModified {
+			fmt.Printf("✓ %s\n", i18n.Tf("registry_update_not_modified", remote.Name))
+			continue
+		}
+		fmt.Printf("✓ %s\n", i18n.Tf("registry_update_success", res.count, res.cachePath))
+		printDiff(res.diff)
+		added = append(added, newSkillNames(remote, res.diff)...)
+	}
+
+	// The TUI shows a "new" badge on these until they have been seen
+	_ = tui.AddNewSkills(added)
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Get(remoteRegistryURL)
+	if failed > 0 {
+		return fmt.Errorf("%s", i18n.Tf("registry_update_failed_count", failed))
+	}
+	return nil
+}
+
+// updateResult is the outcome of refreshing one remote
+type updateResult struct {
+	notModified bool // the server answered 304; the cache was kept
+	count       int
+	cachePath   string
+	diff        pkgregistry.Diff // previous cache (or embedded registry) → new
+}
+
+// updateRemote downloads one remote's registry and stores it in its cache
+// path. Registries of signed remotes are verified first; on failure the
+// previous cache is kept. The request is conditional on the validators of
+// the previous download, so an unchanged registry costs one empty response;
+// a cache that no longer verifies is downloaded again instead.
+func updateRemote(remote pkgregistry.Remote) (updateResult, error) {
+	var res updateResult
+	cachePath, err := remote.CachePath()
 	if err != nil {
-		return fmt.Errorf("%s: %w", i18n.T("registry_update_fetch_error"), err)
+		return res, fmt.Errorf("%s: %w", i18n.T("registry_update_path_error"), err)
 	}
-	defer resp.Body.Close()
+	res.cachePath = cachePath
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("%s: HTTP %d", i18n.T("registry_update_fetch_error"), resp.StatusCode)
+	data, validators, err := fetchRegistry(remote.URL, remote.LoadValidators())
+	if errors.Is(err, errNotModified) {
+		if remote.VerifyCache() == nil {
+			res.notModified = true
+			return res, nil
+		}
+		// The cache no longer verifies (tampered with, or the trusted keys
+		// changed): drop its validators and download the registry again
+		_ = remote.SaveValidators(pkgregistry.CacheValidators{})
+		data, validators, err = fetchRegistry(remote.URL, pkgregistry.CacheValidators{})
+	}
+	if err != nil {
+		return res, fmt.Errorf("%s: %w", i18n.T("registry_update_fetch_error"), err)
 	}
 
-	data, err := io.ReadAll(resp.Body)
+	keys, err := remote.TrustedKeys()
 	if err != nil {
-		return fmt.Errorf("%s: %w", i18n.T("registry_update_fetch_error"), err)
+		return res, err
+	}
+	var sig []byte
+	if len(keys) > 0 {
+		sig, err = download(remote.URL + pkgregistry.SignatureSuffix)
+		if err != nil && !errors.Is(err, errNotFound) {
+			return res, fmt.Errorf("%s: %w", i18n.T("registry_update_fetch_error"), err)
+		}
+		if err := remote.Verify(data, sig); err != nil {
+			return res, fmt.Errorf("%s: %w", i18n.T("registry_update_verify_error"), err)
+		}
 	}
 
 	// Validate it parses correctly before saving
 	reg, err := pkgregistry.Parse(data)
 	if err != nil {
-		return fmt.Errorf("%s: %w", i18n.T("registry_update_parse_error"), err)
+		return res, fmt.Errorf("%s: %w", i18n.T("registry_update_parse_error"), err)
+	}
+
+	// What the remote contributed until now, for the diff
+	previous, err := remote.LoadCached()
+	if err != nil {
+		return res, err
 	}
 
 	if err := os.MkdirAll(filepath.Dir(cachePath), 0o755); err != nil {
-		return fmt.Errorf("%s: %w", i18n.T("registry_update_save_error"), err)
+		return res, fmt.Errorf("%s: %w", i18n.T("registry_update_save_error"), err)
+	}
+
+	// The signature goes first: if the registry write fails, the old cache
+	// no longer verifies and is ignored instead of being trusted.
+	sigPath := cachePath + pkgregistry.SignatureSuffix
+	if sig != nil {
+		err = writeFileAtomic(sigPath, sig)
+	} else if err = os.Remove(sigPath); os.IsNotExist(err) {
+		err = nil
+	}
+	if err == nil {
+		err = writeFileAtomic(cachePath, data)
+	}
+	if err != nil {
+		return res, fmt.Errorf("%s: %w", i18n.T("registry_update_save_error"), err)
+	}
+	// Losing the validators only costs a full download next time
+	_ = remote.SaveValidators(validators)
+
+	res.count = reg.TotalSkillCount()
+	res.diff = pkgregistry.DiffRegistries(previous, reg)
+	return res, nil
+}
+
+// printDiff lists the sources and skills an update added, removed or changed
+func printDiff(d pkgregistry.Diff) {
+	if d.Empty() {
+		fmt.Printf("  %s\n", i18n.T("registry_diff_none"))
+		return
+	}
+	for _, src := range d.AddedSources {
+		fmt.Printf("  + %s\n", i18n.Tf("registry_diff_source", src))
+	}
+	for _, src := range d.RemovedSources {
+		fmt.Printf("  - %s\n", i18n.Tf("registry_diff_source", src))
+	}
+	for _, c := range d.ChangedSources {
+		fmt.Printf("  ~ %s: %s\n", i18n.Tf("registry_diff_source", c.Source), formatFields(c.Fields))
+	}
+	for _, sk := range d.AddedSkills {
+		fmt.Printf("  + %s (%s)\n", sk.Name, sk.Source)
+	}
+	for _, sk := range d.RemovedSkills {
+		fmt.Printf("  - %s (%s)\n", sk.Name, sk.Source)
+	}
+	for _, c := range d.ChangedSkills {
+		fmt.Printf("  ~ %s (%s): %s\n", c.Name, c.Source, formatFields(c.Fields))
+	}
+	fmt.Printf("  %s\n", i18n.Tf("registry_diff_summary", len(d.AddedSkills), len(d.RemovedSkills), len(d.ChangedSkills)))
+}
+
+// formatFields renders field changes as `path "a" → "b", tags "x" → "x,y"`
+func formatFields(fields []pkgregistry.FieldChange) string {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, fmt.Sprintf("%s %q → %q", f.Field, truncate(f.Old), truncate(f.New)))
 	}
+	return strings.Join(parts, ", ")
+}
+
+// truncate shortens long values such as descriptions to keep lines readable
+func truncate(s string) string {
+	const max = 40
+	if r := []rune(s); len(r) > max {
+		return string(r[:max-3]) + "..."
+	}
+	return s
+}
+
+// newSkillNames returns the TUI names (source/skill) of the skills an
+// update added. Sources of remotes other than the official one are named
+// "<remote>:<source>" once loaded.
+func newSkillNames(remote pkgregistry.Remote, d pkgregistry.Diff) []string {
+	prefix := ""
+	if remote.Name != pkgregistry.OfficialRemote {
+		prefix = remote.Name + ":"
+	}
+	names := make([]string, 0, len(d.AddedSkills))
+	for _, sk := range d.AddedSkills {
+		names = append(names, prefix+sk.Source+"/"+sk.Name)
+	}
+	return names
+}
+
+var (
+	errNotFound    = errors.New("not found")
+	errNotModified = errors.New("not modified")
+)
 
-	if err := os.WriteFile(cachePath, data, 0o644); err != nil {
-		return fmt.Errorf("%s: %w", i18n.T("registry_update_save_error"), err)
+// fetchRegistry downloads a registry, sending the validators of the cached
+// copy and any credentials configured for its host. It returns
+// errNotModified when the server says the cache is current, and the
+// validators of the new response otherwise.
+func fetchRegistry(url string, cached pkgregistry.CacheValidators) ([]byte, pkgregistry.CacheValidators, error) {
+	var validators pkgregistry.CacheValidators
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, validators, err
+	}
+	req = gitauth.Authorize(req, gitauth.Lookup(url))
+	if cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
 	}
+	if cached.LastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.LastModified)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, validators, err
+	}
+	defer resp.Body.Close()
 
-	fmt.Printf("✓ %s\n", i18n.Tf("registry_update_success", reg.TotalSkillCount(), cachePath))
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, validators, errNotModified
+	default:
+		return nil, validators, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, validators, err
+	}
+	validators.ETag = resp.Header.Get("ETag")
+	validators.LastModified = resp.Header.Get("Last-Modified")
+	return data, validators, nil
+}
+
+// download fetches url with its host's credentials, returning errNotFound
+// for a 404
+func download(url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(gitauth.Authorize(req, gitauth.Lookup(url)))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%s: %w", url, errNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// writeFileAtomic replaces path through a temporary file and a rename
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
 	return nil
 }
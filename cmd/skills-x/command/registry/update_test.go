@@ -0,0 +1,190 @@
+package registry
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	pkgregistry "github.com/castle-x/skills-x/pkg/registry"
+)
+
+func TestRunUpdate_VerifiesSignature(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	pub, priv, err := pkgregistry.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := pkgregistry.ParsePrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	good := []byte("team:\n  repo: github.com/team/skills\n")
+	evil := []byte("team:\n  repo: github.com/evil/skills\n")
+	body, sig := good, pkgregistry.Sign(good, key)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/registry.yaml":
+			w.Write(body)
+		case "/registry.yaml.sig":
+			w.Write(sig)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	if err := pkgregistry.SetRemoteEnabled(pkgregistry.OfficialRemote, false); err != nil {
+		t.Fatal(err)
+	}
+	team := pkgregistry.Remote{Name: "team", URL: srv.URL + "/registry.yaml", Enabled: true, Keys: []string{pub}}
+	if err := pkgregistry.AddRemote(team); err != nil {
+		t.Fatal(err)
+	}
+	cachePath, _ := team.CachePath()
+
+	if err := runUpdate(nil, nil); err != nil {
+		t.Fatalf("signed update failed: %v", err)
+	}
+	if data, _ := os.ReadFile(cachePath); string(data) != string(good) {
+		t.Fatalf("cache = %q; want the signed registry", data)
+	}
+
+	// A mirror serving a different registry with the old signature is rejected
+	body = evil
+	if err := runUpdate(nil, nil); err == nil {
+		t.Fatal("update with a bad signature should fail")
+	}
+	if data, _ := os.ReadFile(cachePath); string(data) != string(good) {
+		t.Fatalf("cache = %q; a bad signature must keep the previous cache", data)
+	}
+
+	// So is one without any signature
+	sig = nil
+	if err := runUpdate(nil, nil); err == nil {
+		t.Fatal("update without a signature should fail")
+	}
+	if data, _ := os.ReadFile(cachePath); string(data) != string(good) {
+		t.Fatalf("cache = %q; a missing signature must keep the previous cache", data)
+	}
+}
+
+func TestRunUpdate_ConditionalFetchAndNewSkills(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	body := "team:\n  repo: github.com/team/skills\n  skills:\n    - name: first\n      path: skills/first\n"
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		etag := fmt.Sprintf("%q", fmt.Sprint(len(body)))
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	if err := pkgregistry.SetRemoteEnabled(pkgregistry.OfficialRemote, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := pkgregistry.AddRemote(pkgregistry.Remote{Name: "team", URL: srv.URL, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runUpdate(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := tui.LoadNewSkills(); !got["team:team/first"] || len(got) != 1 {
+		t.Fatalf("new skills = %v; want team:team/first", got)
+	}
+
+	// Unchanged registry: the ETag is sent back and nothing is rewritten
+	if err := runUpdate(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || notModified != 1 {
+		t.Fatalf("requests = %d, 304s = %d; want the second fetch to be conditional", requests, notModified)
+	}
+
+	if err := tui.MarkSkillsSeen(map[string]bool{"team:team/first": true}); err != nil {
+		t.Fatal(err)
+	}
+	body += "    - name: second\n      path: skills/second\n"
+	if err := runUpdate(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := tui.LoadNewSkills(); !got["team:team/second"] || len(got) != 1 {
+		t.Fatalf("new skills = %v; want only team:team/second", got)
+	}
+}
+
+func TestRunUpdate_RefetchesUnverifiedCacheOnNotModified(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	pub, priv, err := pkgregistry.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := pkgregistry.ParsePrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	good := []byte("team:\n  repo: github.com/team/skills\n")
+	var fetches, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/registry.yaml":
+			fetches++
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Write(good)
+		case "/registry.yaml.sig":
+			w.Write(pkgregistry.Sign(good, key))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	if err := pkgregistry.SetRemoteEnabled(pkgregistry.OfficialRemote, false); err != nil {
+		t.Fatal(err)
+	}
+	team := pkgregistry.Remote{Name: "team", URL: srv.URL + "/registry.yaml", Enabled: true, Keys: []string{pub}}
+	if err := pkgregistry.AddRemote(team); err != nil {
+		t.Fatal(err)
+	}
+	if err := runUpdate(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The cache is edited on disk: the server still answers 304 to its
+	// validators, but the cache must not be kept
+	cachePath, _ := team.CachePath()
+	if err := os.WriteFile(cachePath, []byte("team:\n  repo: github.com/evil/skills\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runUpdate(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if fetches != 3 || notModified != 1 {
+		t.Fatalf("fetches = %d, 304s = %d; want a full download after the 304", fetches, notModified)
+	}
+	if data, _ := os.ReadFile(cachePath); string(data) != string(good) {
+		t.Fatalf("cache = %q; want the signed registry downloaded again", data)
+	}
+	if err := team.VerifyCache(); err != nil {
+		t.Fatalf("VerifyCache: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+// Package rollbackcmd implements the rollback command for skills-x
+package rollbackcmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/command/indexcmd"
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/adapter"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/spf13/cobra"
+)
+
+const (
+	colorReset = "\033[0m"
+	colorGreen = "\033[32m"
+	colorGray  = "\033[90m"
+)
+
+var flagTarget string
+
+// NewCommand creates the rollback command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback <skill_name>",
+		Short: i18n.T("cmd_rollback_short"),
+		Long:  i18n.T("cmd_rollback_long"),
+		Args:  cobra.ExactArgs(1),
+		RunE:  runRollback,
+	}
+
+	cmd.Flags().StringVarP(&flagTarget, "target", "t", "", i18n.T("cmd_rollback_flag_target"))
+
+	return cmd
+}
+
+func runRollback(cmd *cobra.Command, args []string) error {
+	targetDir := flagTarget
+	if targetDir == "" {
+		// Default: ~/.claude/skills, same as update
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("cannot determine home directory: %w", err)
+		}
+		targetDir = filepath.Join(home, ".claude", "skills")
+	}
+	// Keep the project's skills index current (SKILLS_X_INDEX)
+	defer indexcmd.AutoRefresh(targetDir)
+
+	name := args[0]
+	skillDir := filepath.Join(targetDir, name)
+	from := commitOf(skillDir)
+
+	if err := installtx.Rollback(skillDir); err != nil {
+		if errors.Is(err, installtx.ErrNoBackup) {
+			return fmt.Errorf("%s", i18n.Tf("rollback_no_backup", name, targetDir))
+		}
+		return err
+	}
+
+	if err := adapter.Apply(targetDir, name); err != nil {
+		fmt.Printf("⚠ %s: %v\n", i18n.Tf("adapter_failed", name), err)
+	}
+
+	fmt.Printf("%s✓%s %s\n", colorGreen, colorReset, i18n.Tf("rollback_done", name, from, commitOf(skillDir)))
+	fmt.Printf("%s%s%s\n", colorGray, i18n.Tf("rollback_undo_hint", name), colorReset)
+	return nil
+}
+
+// commitOf returns the installed commit of a skill directory, or "-"
+func commitOf(skillDir string) string {
+	meta, err := tui.ReadSkillMeta(skillDir)
+	if err != nil || meta.Commit == "" {
+		return "-"
+	}
+	return meta.Commit
+}
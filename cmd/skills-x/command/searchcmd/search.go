@@ -0,0 +1,139 @@
+// Package searchcmd implements the search command
+package searchcmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/search"
+	"github.com/spf13/cobra"
+)
+
+// ANSI colors
+const (
+	colorReset  = "\033[0m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+	colorGray   = "\033[90m"
+	colorBold   = "\033[1m"
+)
+
+var (
+	flagLimit int
+	flagFetch bool
+)
+
+// NewCommand creates the search command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: i18n.T("cmd_search_short"),
+		Long:  i18n.T("cmd_search_long"),
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runSearch,
+	}
+
+	cmd.Flags().IntVarP(&flagLimit, "limit", "n", 10, i18n.T("cmd_search_flag_limit"))
+	cmd.Flags().BoolVar(&flagFetch, "fetch", false, i18n.T("cmd_search_flag_fetch"))
+
+	return cmd
+}
+
+func runSearch(_ *cobra.Command, args []string) error {
+	reg, warnings, err := registry.LoadWithUser()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", w)
+	}
+
+	if flagFetch {
+		fetchUncached(reg)
+	}
+	ix, err := search.Open(reg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ %s: %v\n", i18n.T("search_index_save_failed"), err)
+	}
+
+	query := strings.Join(args, " ")
+	results := ix.Search(query, flagLimit)
+	if len(results) == 0 {
+		fmt.Println(i18n.Tf("search_no_results", query))
+	}
+	for i, r := range results {
+		printResult(i+1, r)
+	}
+
+	if missing := len(ix.Unindexed()); missing > 0 && !flagFetch {
+		fmt.Printf("%s%s%s\n", colorGray, i18n.Tf("search_unindexed_hint", missing), colorReset)
+	}
+	return nil
+}
+
+func printResult(rank int, r search.Result) {
+	doc := r.Doc
+	fmt.Printf("%2d. %s%s%s%s/%s%s  %s%.2f%s\n", rank,
+		colorGray, doc.Source, colorReset,
+		colorBold, doc.Name, colorReset,
+		colorGray, r.Score, colorReset)
+	desc := doc.Description
+	if i18n.GetLanguage() == "zh" && doc.DescriptionZh != "" {
+		desc = doc.DescriptionZh
+	}
+	if desc != "" {
+		fmt.Printf("    %s\n", desc)
+	}
+	if r.Snippet != "" {
+		fmt.Printf("    %s%s%s\n", colorGray, r.HighlightSnippet(func(word string) string {
+			return colorReset + colorYellow + word + colorReset + colorGray
+		}), colorReset)
+	}
+	fmt.Println()
+}
+
+// fetchUncached fills the git cache for sources with skills that are not
+// cached yet, the way installs fetch them
+func fetchUncached(reg *registry.Registry) {
+	ix, _ := search.Update(reg, search.Load())
+	sources := make(map[string]bool)
+	for _, doc := range ix.Unindexed() {
+		sources[doc.Source] = true
+	}
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	clones := gitutil.NewCloneSet()
+	for _, name := range names {
+		src := reg.Sources[name]
+		fmt.Fprintf(os.Stderr, "%s\n", i18n.Tf("search_fetching", src.GetRepoShortName()))
+		for i := range src.Skills {
+			skill := &src.Skills[i]
+			ref := src.SkillRef(skill)
+			var err error
+			switch {
+			case src.UsesArchive():
+				archiveRef := src.ArchiveRef(ref)
+				_, err = clones.FetchArchive(src.ArchiveURL(archiveRef), src.Repo, archiveRef, skill.FetchPaths(), false)
+			case ref != "":
+				_, err = clones.CloneRepoAtRef(src.GetGitURL(), src.Repo, ref)
+			case src.SkipFetch && skill.Path != "":
+				_, err = clones.SparseCloneRepo(src.GetGitURL(), src.Repo, src.Branch, []string{skill.Path})
+			default:
+				_, err = clones.CloneRepoWithRefresh(src.GetGitURL(), src.Repo, src.Branch, false)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ %s: %v\n", name, err)
+				break
+			}
+		}
+	}
+}
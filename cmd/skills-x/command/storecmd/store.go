@@ -0,0 +1,85 @@
+// Package storecmd implements the store command, which manages the shared
+// content-addressed skill store
+package storecmd
+
+import (
+	"fmt"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+const (
+	colorReset = "\033[0m"
+	colorGreen = "\033[32m"
+	colorGray  = "\033[90m"
+)
+
+var flagDryRun bool
+
+// NewCommand creates the store command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store",
+		Short: i18n.T("cmd_store_short"),
+		Long:  i18n.T("cmd_store_long"),
+	}
+
+	gc := &cobra.Command{
+		Use:   "gc",
+		Short: i18n.T("cmd_store_gc_short"),
+		Long:  i18n.T("cmd_store_gc_long"),
+		Args:  cobra.NoArgs,
+		RunE:  runGC,
+	}
+	gc.Flags().BoolVar(&flagDryRun, "dry-run", false, i18n.T("cmd_store_gc_flag_dry_run"))
+	cmd.AddCommand(gc)
+
+	return cmd
+}
+
+func runGC(cmd *cobra.Command, args []string) error {
+	fmt.Printf("%s%s%s\n", colorGray, i18n.Tf("store_dir", store.Dir()), colorReset)
+
+	removed, err := store.Prune(flagDryRun)
+	var freed int64
+	for _, e := range removed {
+		fmt.Printf("%s  - %s%s\n", colorGray, e.Path, colorReset)
+		freed += e.Size
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(removed) == 0:
+		fmt.Printf("%s%s%s\n", colorGreen, i18n.T("store_gc_nothing"), colorReset)
+	case flagDryRun:
+		fmt.Println(i18n.Tf("store_gc_would_remove", len(removed), formatBytes(freed)))
+	default:
+		fmt.Printf("%s✓ %s%s\n", colorGreen, i18n.Tf("store_gc_removed", len(removed), formatBytes(freed)), colorReset)
+	}
+
+	if entries, err := store.List(); err == nil && len(entries) > 0 {
+		inUse := 0
+		for _, e := range entries {
+			if len(e.Users) > 0 {
+				inUse++
+			}
+		}
+		fmt.Printf("%s%s%s\n", colorGray, i18n.Tf("store_gc_in_use", inUse), colorReset)
+	}
+	return nil
+}
+
+// formatBytes renders a size as B, KB or MB
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
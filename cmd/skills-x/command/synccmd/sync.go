@@ -0,0 +1,538 @@
+// Package synccmd implements the sync command, which reconciles a project
+// against its skills.yaml manifest
+package synccmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/command/indexcmd"
+	"github.com/castle-x/skills-x/cmd/skills-x/errmsg"
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/adapter"
+	"github.com/castle-x/skills-x/pkg/discover"
+	"github.com/castle-x/skills-x/pkg/fsutil"
+	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/lockfile"
+	"github.com/castle-x/skills-x/pkg/manifest"
+	"github.com/castle-x/skills-x/pkg/products"
+	"github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/skilldiff"
+	"github.com/castle-x/skills-x/pkg/skillmerge"
+	"github.com/castle-x/skills-x/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+// ANSI colors
+const (
+	colorReset  = "\033[0m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+	colorRed    = "\033[31m"
+	colorGray   = "\033[90m"
+	colorBold   = "\033[1m"
+)
+
+var (
+	flagFile   string
+	flagDryRun bool
+	flagPrune  bool
+	flagForce  bool
+	flagLink   string
+	flagPolicy string
+
+	linkMode store.Mode
+	policy   skillmerge.Policy
+)
+
+// Package-level function vars so tests can stub network access
+var (
+	loadRegistry         = registry.LoadWithUser
+	cloneRepoWithRefresh = gitutil.CloneRepoWithRefresh
+	sparseCloneRepo      = gitutil.SparseCloneRepo
+	cloneRepoAtCommit    = gitutil.CloneRepoAtCommit
+	cloneRepoAtRef       = gitutil.CloneRepoAtRef
+	fetchArchive         = gitutil.FetchArchive
+)
+
+// NewCommand creates the sync command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: i18n.T("cmd_sync_short"),
+		Long:  i18n.T("cmd_sync_long"),
+		Args:  cobra.NoArgs,
+		RunE:  runSync,
+	}
+
+	cmd.Flags().StringVar(&flagFile, "file", manifest.FileName, i18n.T("cmd_sync_flag_file"))
+	cmd.Flags().BoolVar(&flagDryRun, "dry-run", false, i18n.T("cmd_sync_flag_dry_run"))
+	cmd.Flags().BoolVar(&flagPrune, "prune", true, i18n.T("cmd_sync_flag_prune"))
+	cmd.Flags().BoolVarP(&flagForce, "force", "f", false, i18n.T("cmd_sync_flag_force"))
+	cmd.Flags().StringVar(&flagLink, "link", string(store.DefaultMode()), i18n.T("cmd_sync_flag_link"))
+	cmd.Flags().StringVar(&flagPolicy, "on-conflict", string(skillmerge.Refuse), i18n.T("cmd_sync_flag_on_conflict"))
+
+	return cmd
+}
+
+// actionKind describes what sync will do with one skill in one product directory
+type actionKind int
+
+const (
+	actionOK actionKind = iota
+	actionInstall
+	actionUpdate
+	actionRemove
+	actionSkipUnmanaged
+)
+
+// action is one planned change
+type action struct {
+	kind     actionKind
+	product  string
+	dir      string // Product skills directory inside the project
+	name     string
+	resolved *resolvedSkill // nil for removals
+}
+
+// resolvedSkill is a manifest entry resolved against the registry and fetched
+type resolvedSkill struct {
+	skill       *registry.Skill
+	source      *registry.Source
+	cloneDir    string
+	skillPath   string
+	relPath     string // Skill path relative to cloneDir ("" for the repo root)
+	ref         string // Pinned commit or tag ("" when tracking the branch tip)
+	resolvedRef string // Ref the archive was downloaded at (archive transport only)
+	commit      string // Full commit SHA of the fetched content ("" when unknown)
+	archive     string // Archive URL, pinned instead when the archive did not record its commit
+	treeHash    string // Tree hash of the skill directory at commit
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	manifestPath, err := filepath.Abs(flagFile)
+	if err != nil {
+		return err
+	}
+	if linkMode, err = store.ParseMode(flagLink); err != nil {
+		return err
+	}
+	if policy, err = skillmerge.ParsePolicy(flagPolicy); err != nil {
+		return err
+	}
+	if !fsutil.FileExists(manifestPath) {
+		return errmsg.ManifestNotFound(manifestPath)
+	}
+
+	m, err := manifest.Load(manifestPath)
+	if err != nil {
+		return errmsg.ManifestInvalid(manifestPath, err)
+	}
+	projectRoot := filepath.Dir(manifestPath)
+	// Keep the project's skills index current (SKILLS_X_INDEX)
+	if !flagDryRun {
+		defer indexcmd.AutoRefreshProject(projectRoot)
+	}
+
+	fmt.Printf("%s%s%s\n", colorCyan, i18n.Tf("sync_from_manifest", manifestPath), colorReset)
+	if flagDryRun {
+		fmt.Printf("%s%s%s\n", colorYellow, i18n.T("sync_dry_run"), colorReset)
+	}
+	fmt.Println()
+
+	reg, warnings, err := loadRegistry()
+	if err != nil {
+		return fmt.Errorf("failed to load registry: %w", err)
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", w)
+	}
+
+	// Resolve and fetch every declared skill once, independent of products
+	resolved := make(map[string]*resolvedSkill)
+	failed := 0
+	for _, s := range m.Skills {
+		fmt.Printf("%s%s %s...%s\n", colorGray, i18n.T("sync_resolving"), s.Name, colorReset)
+		rs, err := resolveSkill(reg, s)
+		if err != nil {
+			fmt.Printf("%s  ✗ %s: %v%s\n", colorRed, s.Name, err, colorReset)
+			failed++
+			continue
+		}
+		resolved[strings.ToLower(s.Name)] = rs
+	}
+
+	actions, err := plan(m, projectRoot, resolved)
+	if err != nil {
+		return err
+	}
+
+	tx := installtx.New()
+	defer tx.AbortOnInterrupt()()
+
+	counts := make(map[actionKind]int)
+	currentProduct := ""
+	for _, a := range actions {
+		if a.product != currentProduct {
+			currentProduct = a.product
+			rel, _ := filepath.Rel(projectRoot, a.dir)
+			fmt.Printf("\n%s📦 %s%s %s(%s)%s\n", colorBold, a.product, colorReset, colorGray, rel, colorReset)
+		}
+
+		if !flagDryRun {
+			if err := apply(tx, a); err != nil {
+				fmt.Printf("%s  ✗ %s: %v%s\n", colorRed, a.name, err, colorReset)
+				failed++
+				continue
+			}
+		}
+		printAction(a)
+		counts[a.kind]++
+	}
+	tx.Commit()
+
+	fmt.Printf("\n%s%s%s\n", colorGreen,
+		i18n.Tf("sync_summary", counts[actionInstall], counts[actionUpdate], counts[actionRemove], counts[actionOK]),
+		colorReset)
+	if counts[actionSkipUnmanaged] > 0 {
+		fmt.Printf("%s%s%s\n", colorYellow, i18n.Tf("sync_unmanaged_count", counts[actionSkipUnmanaged]), colorReset)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s", i18n.Tf("sync_failed_count", failed))
+	}
+	return nil
+}
+
+// resolveSkill finds a manifest entry in the registry and fetches its content
+func resolveSkill(reg *registry.Registry, s manifest.Skill) (*resolvedSkill, error) {
+	matches := reg.FindSkillsWithConflict(s.Name)
+
+	var skill *registry.Skill
+	var source *registry.Source
+	for _, match := range matches {
+		if s.Source != "" && !strings.EqualFold(match.Source.Name, s.Source) {
+			continue
+		}
+		if skill != nil {
+			return nil, fmt.Errorf("%s", i18n.Tf("sync_ambiguous", s.Name))
+		}
+		skill, source = match.Skill, match.Source
+	}
+	if skill == nil {
+		if s.Source != "" {
+			return nil, fmt.Errorf("%s", i18n.Tf("sync_not_in_source", s.Source))
+		}
+		return nil, fmt.Errorf("%s", i18n.T("err_skill_not_found"))
+	}
+
+	// A per-skill branch overrides the source default without touching the registry
+	if s.Branch != "" {
+		src := *source
+		src.Branch = s.Branch
+		source = &src
+	}
+
+	ref := s.Commit
+	if ref == "" && s.Branch == "" {
+		ref = source.SkillRef(skill)
+	}
+
+	var result *gitutil.CloneResult
+	var err error
+	switch {
+	case source.UsesArchive():
+		archiveRef := source.ArchiveRef(ref)
+		result, err = fetchArchive(source.ArchiveURL(archiveRef), source.Repo, archiveRef, skill.FetchPaths(), true)
+	case s.Commit != "":
+		result, err = cloneRepoAtCommit(source.GetGitURL(), source.Repo, s.Commit)
+	case ref != "":
+		result, err = cloneRepoAtRef(source.GetGitURL(), source.Repo, ref)
+	case source.SkipFetch && skill.Path != "":
+		result, err = sparseCloneRepo(source.GetGitURL(), source.Repo, source.Branch, []string{skill.Path})
+	default:
+		result, err = cloneRepoWithRefresh(source.GetGitURL(), source.Repo, source.Branch, true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", i18n.T("init_clone_failed"), err)
+	}
+
+	var skillPath string
+	if skill.Path != "" {
+		skillPath = filepath.Join(result.TempDir, skill.Path)
+	} else if discovered, _ := discover.FindSkillInRepo(result.TempDir, skill.Name); discovered != nil {
+		skillPath = discovered.Path
+	}
+	if skillPath == "" || !fsutil.DirExists(skillPath) {
+		return nil, fmt.Errorf("%s", i18n.T("init_skill_path_not_found"))
+	}
+
+	commit, archive, err := gitutil.GetLockPin(result.TempDir)
+	if err != nil {
+		return nil, err
+	}
+
+	relPath, err := filepath.Rel(result.TempDir, skillPath)
+	if err != nil || relPath == "." {
+		relPath = ""
+	}
+	treeHash, _ := gitutil.GetTreeHash(result.TempDir, relPath)
+
+	return &resolvedSkill{
+		skill:       skill,
+		source:      source,
+		cloneDir:    result.TempDir,
+		skillPath:   skillPath,
+		relPath:     filepath.ToSlash(relPath),
+		ref:         ref,
+		resolvedRef: gitutil.ArchiveRef(result.TempDir),
+		commit:      commit,
+		archive:     archive,
+		treeHash:    treeHash,
+	}, nil
+}
+
+// productDir is one project skills directory and the manifest entries
+// meant for it. Products that share a directory, or that the manifest names
+// in different spellings ("claude-code", "Claude Code"), are planned once.
+type productDir struct {
+	dir      string
+	products []string
+	skills   []manifest.Skill
+	listed   map[string]bool
+}
+
+// groupByDir resolves the manifest's products and groups their skills by
+// project skills directory, in the order the manifest first names them
+func groupByDir(m *manifest.Manifest, projectRoot string) []*productDir {
+	var groups []*productDir
+	byDir := make(map[string]*productDir)
+	group := func(name string) *productDir {
+		product := products.GetProductByName(name)
+		if product == nil {
+			return nil
+		}
+		dir := product.Dir(products.ScopeProject, projectRoot)
+		g := byDir[dir]
+		if g == nil {
+			g = &productDir{dir: dir, listed: make(map[string]bool)}
+			byDir[dir] = g
+			groups = append(groups, g)
+		}
+		if !slices.Contains(g.products, product.Name) {
+			g.products = append(g.products, product.Name)
+		}
+		return g
+	}
+
+	for _, name := range m.AllProducts() {
+		group(name)
+	}
+	for _, s := range m.Skills {
+		for _, name := range m.ProductsFor(s) {
+			g := group(name)
+			if g == nil || g.listed[strings.ToLower(s.Name)] {
+				continue
+			}
+			g.listed[strings.ToLower(s.Name)] = true
+			g.skills = append(g.skills, s)
+		}
+	}
+	return groups
+}
+
+// plan compares every product directory with the manifest and returns the
+// actions needed, grouped by directory. Skills that failed to resolve are
+// left untouched rather than being treated as removed.
+func plan(m *manifest.Manifest, projectRoot string, resolved map[string]*resolvedSkill) ([]action, error) {
+	var actions []action
+	for _, g := range groupByDir(m, projectRoot) {
+		label := strings.Join(g.products, ", ")
+		for _, s := range g.skills {
+			rs := resolved[strings.ToLower(s.Name)]
+			if rs == nil {
+				continue
+			}
+			actions = append(actions, action{
+				kind:     compare(filepath.Join(g.dir, rs.skill.Name), rs),
+				product:  label,
+				dir:      g.dir,
+				name:     rs.skill.Name,
+				resolved: rs,
+			})
+		}
+
+		if !flagPrune {
+			continue
+		}
+		entries, err := os.ReadDir(g.dir)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() || g.listed[strings.ToLower(e.Name())] {
+				continue
+			}
+			// Only skills installed by skills-x (those with meta) are removed;
+			// hand-written skills in the same directory are left alone.
+			if _, err := tui.ReadSkillMeta(filepath.Join(g.dir, e.Name())); err != nil {
+				continue
+			}
+			actions = append(actions, action{
+				kind:    actionRemove,
+				product: label,
+				dir:     g.dir,
+				name:    e.Name(),
+			})
+		}
+	}
+
+	return actions, nil
+}
+
+// compare decides whether an installed copy matches the resolved skill
+func compare(dstPath string, rs *resolvedSkill) actionKind {
+	if !fsutil.DirExists(dstPath) {
+		return actionInstall
+	}
+	meta, err := tui.ReadSkillMeta(dstPath)
+	if err != nil {
+		if flagForce {
+			return actionUpdate
+		}
+		return actionSkipUnmanaged
+	}
+	if !strings.EqualFold(meta.Source, rs.source.Name) {
+		return actionUpdate
+	}
+	// Prefer the skill's tree hash so unrelated upstream commits are not drift
+	if meta.TreeHash != "" && rs.treeHash != "" {
+		if meta.TreeHash != rs.treeHash {
+			return actionUpdate
+		}
+		return actionOK
+	}
+	if meta.Commit == "" || !strings.HasPrefix(rs.commit, meta.Commit) {
+		return actionUpdate
+	}
+	return actionOK
+}
+
+// apply performs one planned action. Installs and updates are staged and
+// swapped in, and removed skills are kept as backups, so an interrupted sync
+// is undone and "skills-x rollback" can bring back what a sync replaced.
+func apply(tx *installtx.Tx, a action) error {
+	dstPath := filepath.Join(a.dir, a.name)
+
+	switch a.kind {
+	case actionInstall, actionUpdate:
+		if err := os.MkdirAll(a.dir, 0755); err != nil {
+			return errmsg.TargetDirCreateError(a.dir)
+		}
+		rs := a.resolved
+
+		commit, err := gitutil.GetRepoHeadCommit(rs.cloneDir)
+		if err != nil {
+			commit = rs.commit
+		}
+		// Files edited since install are handled by --on-conflict; the
+		// merge base is the file at the commit that was installed.
+		opts := skillmerge.Options{Policy: policy}
+		if meta, err := tui.ReadSkillMeta(dstPath); err == nil {
+			opts.Baseline = meta.Files
+			opts.Base = func(p string) ([]byte, error) {
+				return gitutil.ReadFileAtCommit(rs.cloneDir, meta.Commit, filepath.Join(rs.relPath, p))
+			}
+		}
+		err = tx.Install(dstPath, func(stageDir string) error {
+			if _, err := skillmerge.Apply(dstPath, rs.skillPath, stageDir, opts); err != nil {
+				return err
+			}
+			if _, err := store.Share(stageDir, dstPath, linkMode); err != nil {
+				return err
+			}
+			// Hashes describe the upstream files, so files kept or merged
+			// locally still count as local modifications next time.
+			files, _ := skilldiff.HashFiles(rs.skillPath)
+			return tui.WriteSkillMeta(stageDir, tui.SkillMeta{
+				Skill:       rs.skill.Name,
+				Source:      rs.source.Name,
+				Repo:        rs.source.Repo,
+				Commit:      commit,
+				Ref:         rs.ref,
+				TreeHash:    rs.treeHash,
+				ResolvedRef: rs.resolvedRef,
+				Files:       files,
+			})
+		})
+		if errors.Is(err, skillmerge.ErrLocalChanges) {
+			return fmt.Errorf("%s", i18n.T("sync_local_changes"))
+		}
+		if err != nil {
+			return errmsg.CopyFailed(a.name)
+		}
+
+		if err := lockfile.Record(a.dir, lockfile.Entry{
+			Name:    rs.skill.Name,
+			Source:  rs.source.Name,
+			Repo:    rs.source.Repo,
+			Path:    rs.relPath,
+			Branch:  rs.source.Branch,
+			Ref:     rs.ref,
+			Commit:  rs.commit,
+			Archive: rs.archive,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "%s⚠ %s: %v%s\n", colorYellow, i18n.T("lock_write_failed"), err, colorReset)
+		}
+	case actionRemove:
+		if err := tx.Remove(dstPath); err != nil {
+			return err
+		}
+		if err := lockfile.Forget(a.dir, a.name); err != nil {
+			fmt.Fprintf(os.Stderr, "%s⚠ %s: %v%s\n", colorYellow, i18n.T("lock_write_failed"), err, colorReset)
+		}
+	}
+	if err := adapter.Apply(a.dir, a.name); err != nil {
+		fmt.Fprintf(os.Stderr, "%s⚠ %s: %v%s\n", colorYellow, i18n.Tf("adapter_failed", a.name), err, colorReset)
+	}
+	return nil
+}
+
+// printAction prints one line describing an action
+func printAction(a action) {
+	switch a.kind {
+	case actionInstall:
+		fmt.Printf("%s  + %s%s\n", colorGreen, i18n.Tf("sync_installed", a.name, a.resolved.label()), colorReset)
+	case actionUpdate:
+		fmt.Printf("%s  ↑ %s%s\n", colorCyan, i18n.Tf("sync_updated", a.name, a.resolved.label()), colorReset)
+	case actionRemove:
+		fmt.Printf("%s  - %s%s\n", colorYellow, i18n.Tf("sync_removed", a.name), colorReset)
+	case actionSkipUnmanaged:
+		fmt.Printf("%s  ! %s%s\n", colorYellow, i18n.Tf("sync_unmanaged", a.name), colorReset)
+	default:
+		fmt.Printf("%s  = %s%s\n", colorGray, i18n.Tf("sync_up_to_date", a.name), colorReset)
+	}
+}
+
+// label is what the skill was fetched at, for display: its commit, or the
+// archive ref when the archive did not record one
+func (rs *resolvedSkill) label() string {
+	if rs.commit == "" {
+		return rs.resolvedRef
+	}
+	return shortCommit(rs.commit)
+}
+
+// shortCommit abbreviates a commit SHA for display
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
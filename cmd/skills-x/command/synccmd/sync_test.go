@@ -0,0 +1,286 @@
+package synccmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/lockfile"
+	"github.com/castle-x/skills-x/pkg/manifest"
+	"github.com/castle-x/skills-x/pkg/registry"
+)
+
+// initSourceRepo creates a one-commit git repository holding skills/pdf.
+func initSourceRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+	skillDir := filepath.Join(dir, "skills", "pdf")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("---\nname: pdf\n---\n# pdf\n"), 0644); err != nil {
+		t.Fatalf("write SKILL.md: %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func stubNetwork(t *testing.T, repoDir string) {
+	t.Helper()
+	origLoad, origClone := loadRegistry, cloneRepoWithRefresh
+	origFile, origDry, origPrune, origForce, origPolicy := flagFile, flagDryRun, flagPrune, flagForce, flagPolicy
+	t.Cleanup(func() {
+		loadRegistry, cloneRepoWithRefresh = origLoad, origClone
+		flagFile, flagDryRun, flagPrune, flagForce, flagPolicy = origFile, origDry, origPrune, origForce, origPolicy
+	})
+
+	loadRegistry = func() (*registry.Registry, []string, error) {
+		return &registry.Registry{Sources: map[string]*registry.Source{
+			"anthropic": {
+				Name:   "anthropic",
+				Repo:   "github.com/anthropics/skills",
+				Skills: []registry.Skill{{Name: "pdf", Path: "skills/pdf"}},
+			},
+		}}, nil, nil
+	}
+	cloneRepoWithRefresh = func(gitURL string, repoName string, branch string, refresh bool) (*gitutil.CloneResult, error) {
+		return &gitutil.CloneResult{TempDir: repoDir, Repo: repoName}, nil
+	}
+	flagDryRun, flagPrune, flagForce, flagPolicy = false, true, false, ""
+}
+
+// commitFile writes a file into the source repository and commits it
+func commitFile(t *testing.T, repoDir string, name string, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(repoDir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "update"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestRunSync_InstallsPrunesAndIsIdempotent(t *testing.T) {
+	repoDir := initSourceRepo(t)
+	stubNetwork(t, repoDir)
+
+	project := t.TempDir()
+	flagFile = filepath.Join(project, "skills.yaml")
+	if err := os.WriteFile(flagFile, []byte("products: [Claude Code]\nskills: [pdf]\n"), 0644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	skillsDir := filepath.Join(project, ".claude", "skills")
+	// A previously synced skill that is no longer listed
+	stale := filepath.Join(skillsDir, "old")
+	if err := os.MkdirAll(stale, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := tui.WriteSkillMeta(stale, tui.SkillMeta{Skill: "old", Source: "anthropic", Commit: "abc1234"}); err != nil {
+		t.Fatalf("write meta: %v", err)
+	}
+	// A hand-written skill must survive pruning
+	mine := filepath.Join(skillsDir, "mine")
+	if err := os.MkdirAll(mine, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	if err := runSync(nil, nil); err != nil {
+		t.Fatalf("runSync: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(skillsDir, "pdf", "SKILL.md")); err != nil {
+		t.Fatalf("pdf not installed: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("stale managed skill should be removed, stat err = %v", err)
+	}
+	if !installtx.HasBackup(stale) {
+		t.Fatal("removed skill should be kept as a backup for rollback")
+	}
+	if _, err := os.Stat(mine); err != nil {
+		t.Fatalf("unmanaged skill should be kept: %v", err)
+	}
+
+	lf, err := lockfile.Load(skillsDir)
+	if err != nil {
+		t.Fatalf("load lockfile: %v", err)
+	}
+	if lf.Get("pdf") == nil {
+		t.Fatal("pdf should be recorded in the lockfile")
+	}
+
+	// Second run finds nothing to do
+	m, _ := tui.ReadSkillMeta(filepath.Join(skillsDir, "pdf"))
+	if err := runSync(nil, nil); err != nil {
+		t.Fatalf("second runSync: %v", err)
+	}
+	m2, _ := tui.ReadSkillMeta(filepath.Join(skillsDir, "pdf"))
+	if m == nil || m2 == nil || m.InstalledAt != m2.InstalledAt {
+		t.Fatal("up-to-date skill should not be reinstalled")
+	}
+}
+
+func TestRunSync_DryRunChangesNothing(t *testing.T) {
+	repoDir := initSourceRepo(t)
+	stubNetwork(t, repoDir)
+	flagDryRun = true
+
+	project := t.TempDir()
+	flagFile = filepath.Join(project, "skills.yaml")
+	if err := os.WriteFile(flagFile, []byte("products: [Cursor]\nskills: [pdf]\n"), 0644); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+
+	if err := runSync(nil, nil); err != nil {
+		t.Fatalf("runSync: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(project, ".cursor")); !os.IsNotExist(err) {
+		t.Fatalf("dry run should not create product directories, stat err = %v", err)
+	}
+}
+
+func TestPlan_OncePerDirectory(t *testing.T) {
+	stubNetwork(t, t.TempDir())
+	project := t.TempDir()
+	skillsDir := filepath.Join(project, ".claude", "skills")
+	stale := filepath.Join(skillsDir, "old")
+	if err := os.MkdirAll(stale, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := tui.WriteSkillMeta(stale, tui.SkillMeta{Skill: "old", Source: "anthropic", Commit: "abc1234"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Three spellings of one product
+	m := &manifest.Manifest{
+		Products: []string{"claude-code", "Claude Code"},
+		Skills:   []manifest.Skill{{Name: "pdf"}, {Name: "old", Products: []string{"claude_code"}}},
+	}
+	rs := &resolvedSkill{skill: &registry.Skill{Name: "pdf"}, source: &registry.Source{Name: "anthropic"}}
+	actions, err := plan(m, project, map[string]*resolvedSkill{"pdf": rs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 || actions[0].name != "pdf" || actions[0].kind != actionInstall || actions[0].dir != skillsDir {
+		t.Fatalf("actions = %+v; want one install of pdf and old kept (listed under another spelling)", actions)
+	}
+}
+
+func TestRunSync_LocalChanges(t *testing.T) {
+	repoDir := initSourceRepo(t)
+	stubNetwork(t, repoDir)
+
+	project := t.TempDir()
+	flagFile = filepath.Join(project, "skills.yaml")
+	if err := os.WriteFile(flagFile, []byte("products: [Claude Code]\nskills: [pdf]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runSync(nil, nil); err != nil {
+		t.Fatalf("runSync: %v", err)
+	}
+
+	installed := filepath.Join(project, ".claude", "skills", "pdf", "SKILL.md")
+	if err := os.WriteFile(installed, []byte("# my notes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, repoDir, "skills/pdf/SKILL.md", "---\nname: pdf\n---\n# pdf v2\n")
+
+	// Refused by default: the edit survives and the sync reports a failure
+	if err := runSync(nil, nil); err == nil {
+		t.Fatal("runSync should fail on a locally modified skill")
+	}
+	if data, _ := os.ReadFile(installed); string(data) != "# my notes\n" {
+		t.Fatalf("local edit was overwritten: %q", data)
+	}
+
+	flagPolicy = "take-upstream"
+	if err := runSync(nil, nil); err != nil {
+		t.Fatalf("runSync --on-conflict=take-upstream: %v", err)
+	}
+	if data, _ := os.ReadFile(installed); string(data) != "---\nname: pdf\n---\n# pdf v2\n" {
+		t.Fatalf("SKILL.md = %q; want the upstream version", data)
+	}
+	if !installtx.HasBackup(filepath.Dir(installed)) {
+		t.Fatal("the replaced version should be kept for rollback")
+	}
+}
+
+func TestRunSync_ArchiveWithoutCommit(t *testing.T) {
+	stubNetwork(t, t.TempDir())
+	const url = "https://example.com/skills/archive/main.tar.gz"
+
+	// An archive checkout whose fetch record has no commit
+	checkout := filepath.Join(t.TempDir(), "checkout")
+	if err := os.MkdirAll(filepath.Join(checkout, "skills", "pdf"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(checkout, "skills", "pdf", "SKILL.md"), []byte("# pdf\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(checkout+".fetch.json", []byte(`{"url": "`+url+`", "repo": "example.com/skills", "ref": "main"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	origFetch := fetchArchive
+	t.Cleanup(func() { fetchArchive = origFetch })
+	fetchArchive = func(archive string, repoName string, ref string, paths []string, refresh bool) (*gitutil.CloneResult, error) {
+		return &gitutil.CloneResult{TempDir: checkout, Repo: repoName}, nil
+	}
+	loadRegistry = func() (*registry.Registry, []string, error) {
+		return &registry.Registry{Sources: map[string]*registry.Source{
+			"mirror": {
+				Name:      "mirror",
+				Repo:      "example.com/skills",
+				Transport: registry.TransportArchive,
+				Archive:   "https://example.com/skills/archive/{ref}.tar.gz",
+				Skills:    []registry.Skill{{Name: "pdf", Path: "skills/pdf"}},
+			},
+		}}, nil, nil
+	}
+
+	project := t.TempDir()
+	flagFile = filepath.Join(project, "skills.yaml")
+	if err := os.WriteFile(flagFile, []byte("products: [Claude Code]\nskills: [pdf]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runSync(nil, nil); err != nil {
+		t.Fatalf("runSync: %v", err)
+	}
+
+	skillsDir := filepath.Join(project, ".claude", "skills")
+	lf, err := lockfile.Load(skillsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := lf.Get("pdf")
+	if entry == nil || entry.Commit != "" || entry.Archive != url {
+		t.Fatalf("lock entry = %+v; want pdf pinned to %s", entry, url)
+	}
+}
@@ -0,0 +1,102 @@
+// Package uninstallcmd implements the uninstall command for skills-x
+package uninstallcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/command/indexcmd"
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/cmd/skills-x/targets"
+	"github.com/castle-x/skills-x/pkg/adapter"
+	"github.com/castle-x/skills-x/pkg/fsutil"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/lockfile"
+	"github.com/spf13/cobra"
+)
+
+const (
+	colorReset  = "\033[0m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorRed    = "\033[31m"
+	colorGray   = "\033[90m"
+	colorBold   = "\033[1m"
+)
+
+var (
+	flagTarget  string
+	flagTargets targets.Flags
+)
+
+// NewCommand creates the uninstall command
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "uninstall <skill_name...>",
+		Short: i18n.T("cmd_uninstall_short"),
+		Long:  i18n.T("cmd_uninstall_long"),
+		Args:  cobra.MinimumNArgs(1),
+		RunE:  runUninstall,
+	}
+
+	cmd.Flags().StringVarP(&flagTarget, "target", "t", "", i18n.T("cmd_uninstall_flag_target"))
+	flagTargets.Register(cmd)
+
+	return cmd
+}
+
+func runUninstall(cmd *cobra.Command, args []string) error {
+	// Default: ~/.claude/skills, same as update
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	cwd, _ := os.Getwd()
+	ts, err := flagTargets.Resolve(flagTarget, cwd, filepath.Join(home, ".claude", "skills"))
+	if err != nil {
+		return err
+	}
+	// Keep the project's skills index current (SKILLS_X_INDEX)
+	defer indexcmd.AutoRefresh(targets.Dirs(ts)...)
+
+	// Removed skills are kept as backups, so "skills-x rollback" can bring
+	// each one back
+	tx := installtx.New()
+	removed, failed := 0, 0
+	for _, t := range ts {
+		if len(ts) > 1 {
+			fmt.Printf("%s%s%s\n", colorBold, targets.Label(t), colorReset)
+		}
+		for _, name := range args {
+			skillDir := filepath.Join(t.Dir, name)
+			if !fsutil.DirExists(skillDir) {
+				fmt.Printf("%s  - %s%s\n", colorGray, i18n.Tf("uninstall_not_installed", name), colorReset)
+				continue
+			}
+			if err := tx.Remove(skillDir); err != nil {
+				fmt.Printf("%s  ✗ %s: %v%s\n", colorRed, name, err, colorReset)
+				failed++
+				continue
+			}
+			if err := lockfile.Forget(t.Dir, name); err != nil {
+				fmt.Printf("%s  ⚠ %s: %v%s\n", colorYellow, i18n.T("lock_write_failed"), err, colorReset)
+			}
+			if err := adapter.Apply(t.Dir, name); err != nil {
+				fmt.Printf("%s  ⚠ %s: %v%s\n", colorYellow, i18n.Tf("adapter_failed", name), err, colorReset)
+			}
+			fmt.Printf("%s  ✓ %s%s\n", colorGreen, i18n.Tf("uninstall_removed", name), colorReset)
+			removed++
+		}
+	}
+	tx.Commit()
+
+	fmt.Println()
+	if removed > 0 {
+		fmt.Printf("%s%s%s\n", colorGray, i18n.T("uninstall_rollback_hint"), colorReset)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s", i18n.Tf("uninstall_failed", failed))
+	}
+	return nil
+}
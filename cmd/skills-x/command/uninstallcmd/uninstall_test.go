@@ -0,0 +1,62 @@
+package uninstallcmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/targets"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/lockfile"
+)
+
+func TestRunUninstall_EveryTarget(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	project := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	dirs := []string{
+		filepath.Join(project, ".claude", "skills"),
+		filepath.Join(project, ".cursor", "skills"),
+	}
+	for _, dir := range dirs {
+		if err := os.MkdirAll(filepath.Join(dir, "pdf"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "pdf", "SKILL.md"), []byte("# pdf\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := lockfile.Record(dir, lockfile.Entry{Name: "pdf", Source: "anthropic", Repo: "github.com/anthropics/skills", Commit: "abc"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	flagTarget = ""
+	flagTargets = targets.Flags{Products: []string{"claude-code", "cursor"}}
+	t.Cleanup(func() { flagTargets = targets.Flags{} })
+
+	if err := runUninstall(nil, []string{"pdf", "docx"}); err != nil {
+		t.Fatalf("runUninstall returned error: %v", err)
+	}
+
+	for _, dir := range dirs {
+		if _, err := os.Stat(filepath.Join(dir, "pdf")); !os.IsNotExist(err) {
+			t.Errorf("%s: pdf still installed", dir)
+		}
+		if _, err := os.Stat(installtx.BackupPath(filepath.Join(dir, "pdf"))); err != nil {
+			t.Errorf("%s: no backup kept for rollback: %v", dir, err)
+		}
+		lf, err := lockfile.Load(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lf.Get("pdf") != nil {
+			t.Errorf("%s: pdf still in skills-x.lock", dir)
+		}
+	}
+}
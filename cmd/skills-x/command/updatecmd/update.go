@@ -2,16 +2,25 @@
 package updatecmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/castle-x/skills-x/cmd/skills-x/command/indexcmd"
 	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/cmd/skills-x/targets"
 	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/adapter"
 	"github.com/castle-x/skills-x/pkg/discover"
 	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/lockfile"
 	"github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/skilldiff"
+	"github.com/castle-x/skills-x/pkg/skillmerge"
+	"github.com/castle-x/skills-x/pkg/store"
 	"github.com/spf13/cobra"
 )
 
@@ -26,15 +35,23 @@ const (
 )
 
 var (
-	flagAll    bool
-	flagCheck  bool
-	flagTarget string
+	flagAll        bool
+	flagCheck      bool
+	flagTarget     string
+	flagOnConflict string
+	flagLink       string
+	flagTargets    targets.Flags
 )
 
 var (
 	cloneRepoWithRefresh = gitutil.CloneRepoWithRefresh
 	sparseCloneRepo      = gitutil.SparseCloneRepo
+	cloneRepoAtRef       = gitutil.CloneRepoAtRef
+	fetchArchive         = gitutil.FetchArchive
 	getRepoHeadCommit    = gitutil.GetRepoHeadCommit
+	getLockPin           = gitutil.GetLockPin
+	getTreeHash          = gitutil.GetTreeHash
+	readFileAtCommit     = gitutil.ReadFileAtCommit
 )
 
 // NewCommand creates the update command
@@ -49,31 +66,49 @@ func NewCommand() *cobra.Command {
 	cmd.Flags().BoolVarP(&flagAll, "all", "a", false, i18n.T("cmd_update_flag_all"))
 	cmd.Flags().BoolVarP(&flagCheck, "check", "c", false, i18n.T("cmd_update_flag_check"))
 	cmd.Flags().StringVarP(&flagTarget, "target", "t", "", i18n.T("cmd_update_flag_target"))
+	cmd.Flags().StringVar(&flagOnConflict, "on-conflict", string(skillmerge.Refuse), i18n.T("cmd_update_flag_on_conflict"))
+	cmd.Flags().StringVar(&flagLink, "link", string(store.DefaultMode()), i18n.T("cmd_update_flag_link"))
+	flagTargets.Register(cmd)
 
 	return cmd
 }
 
 type skillCheckResult struct {
 	name         string
-	status       string // "up_to_date", "update_available", "no_meta", "error"
+	status       string // "up_to_date", "update_available", "no_meta", "local_changes", "error"
 	localCommit  string
 	remoteCommit string
+	merge        *skillmerge.Result // What happened to locally modified files
 	err          error
 }
 
 func runUpdate(cmd *cobra.Command, args []string) error {
-	targetDir := flagTarget
-	if targetDir == "" {
-		// Default: ~/.claude/skills (or similar)
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("cannot determine home directory: %w", err)
-		}
-		targetDir = filepath.Join(home, ".claude", "skills")
+	policy, err := skillmerge.ParsePolicy(flagOnConflict)
+	if err != nil {
+		return err
+	}
+	linkMode, err := store.ParseMode(flagLink)
+	if err != nil {
+		return err
 	}
 
-	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
-		return fmt.Errorf("target directory does not exist: %s", targetDir)
+	// Default: ~/.claude/skills (or similar)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("cannot determine home directory: %w", err)
+	}
+	cwd, _ := os.Getwd()
+	ts, err := flagTargets.Resolve(flagTarget, cwd, filepath.Join(home, ".claude", "skills"))
+	if err != nil {
+		return err
+	}
+	// Keep the project's skills index current (SKILLS_X_INDEX)
+	if !flagCheck {
+		defer indexcmd.AutoRefresh(targets.Dirs(ts)...)
+	}
+
+	if !flagAll && len(args) == 0 {
+		return fmt.Errorf("specify skill names or use --all to update all installed skills")
 	}
 
 	reg, warnings, err := registry.LoadWithUser()
@@ -84,6 +119,29 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "⚠ %s\n", w)
 	}
 
+	if len(ts) == 1 {
+		if _, err := os.Stat(ts[0].Dir); os.IsNotExist(err) {
+			return fmt.Errorf("target directory does not exist: %s", ts[0].Dir)
+		}
+		return updateDir(reg, ts[0].Dir, args, policy, linkMode)
+	}
+
+	// Several products: update each one that has skills installed
+	for _, t := range ts {
+		if _, err := os.Stat(t.Dir); os.IsNotExist(err) {
+			continue
+		}
+		fmt.Printf("%s%s%s\n", colorBold, targets.Label(t), colorReset)
+		if err := updateDir(reg, t.Dir, args, policy, linkMode); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// updateDir checks or updates the skills installed in targetDir
+func updateDir(reg *registry.Registry, targetDir string, args []string, policy skillmerge.Policy, linkMode store.Mode) error {
 	// Find installed skills in target directory
 	entries, err := os.ReadDir(targetDir)
 	if err != nil {
@@ -153,10 +211,6 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		})
 	}
 
-	if !flagAll && len(args) == 0 {
-		return fmt.Errorf("specify skill names or use --all to update all installed skills")
-	}
-
 	if len(installed) == 0 {
 		fmt.Println("No installed skills found to update.")
 		return nil
@@ -164,6 +218,9 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Checking for updates (%s)...\n\n", targetDir)
 
+	tx := installtx.New()
+	defer tx.AbortOnInterrupt()()
+
 	var results []skillCheckResult
 	updateAvailable := 0
 
@@ -176,11 +233,23 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// A skill pinned at install time (init name@ref) stays on that ref;
+		// otherwise follow the registry pin, if any.
+		ref := is.source.SkillRef(is.skill)
+		if is.meta != nil && is.meta.Ref != "" {
+			ref = is.meta.Ref
+		}
+
 		// Get cached/fresh repo
 		var cloneResult *gitutil.CloneResult
 			// Check mode must also refresh, otherwise stale cache can hide updates.
 			refresh := true
-			if is.source.SkipFetch && is.skill.Path != "" {
+			if is.source.UsesArchive() {
+				archiveRef := is.source.ArchiveRef(ref)
+				cloneResult, err = fetchArchive(is.source.ArchiveURL(archiveRef), is.source.Repo, archiveRef, is.skill.FetchPaths(), refresh)
+			} else if ref != "" {
+				cloneResult, err = cloneRepoAtRef(is.source.GetGitURL(), is.source.Repo, ref)
+			} else if is.source.SkipFetch && is.skill.Path != "" {
 				cloneResult, err = sparseCloneRepo(is.source.GetGitURL(), is.source.Repo, is.source.Branch, []string{is.skill.Path})
 			} else {
 				cloneResult, err = cloneRepoWithRefresh(is.source.GetGitURL(), is.source.Repo, is.source.Branch, refresh)
@@ -204,10 +273,35 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// Locate the skill in the checkout; its tree hash tells whether the
+		// skill itself changed, independent of other commits in the repo.
+		var skillPath string
+		if is.skill.Path != "" {
+			skillPath = filepath.Join(cloneResult.TempDir, is.skill.Path)
+		} else {
+			discovered, err := discover.DiscoverSkillByPath(cloneResult.TempDir, is.skill.Name)
+			if err != nil || discovered == nil {
+				discovered, _ = findSkillInRepo(cloneResult.TempDir, is.skill.Name)
+			}
+			if discovered != nil {
+				skillPath = discovered.Path
+			}
+		}
+
+		relPath := ""
+		remoteTree := ""
+		if skillPath != "" {
+			if rel, err := filepath.Rel(cloneResult.TempDir, skillPath); err == nil && rel != "." {
+				relPath = rel
+			}
+			remoteTree, _ = getTreeHash(cloneResult.TempDir, relPath)
+		}
+
 		localCommit := ""
 		if is.meta != nil {
 			localCommit = is.meta.Commit
 		}
+		changed := is.meta.HasChanged(remoteCommit, remoteTree)
 
 		if localCommit == "" {
 			results = append(results, skillCheckResult{
@@ -216,7 +310,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 				remoteCommit: remoteCommit,
 			})
 			updateAvailable++
-		} else if localCommit == remoteCommit {
+		} else if !changed {
 			results = append(results, skillCheckResult{
 				name:         is.name,
 				status:       "up_to_date",
@@ -234,20 +328,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		}
 
 		// If not check-only, perform the update
-		if !flagCheck && (localCommit != remoteCommit || localCommit == "") {
-			var skillPath string
-			if is.skill.Path != "" {
-				skillPath = filepath.Join(cloneResult.TempDir, is.skill.Path)
-			} else {
-				discovered, err := discover.DiscoverSkillByPath(cloneResult.TempDir, is.skill.Name)
-				if err != nil || discovered == nil {
-					discovered, _ = findSkillInRepo(cloneResult.TempDir, is.skill.Name)
-				}
-				if discovered != nil {
-					skillPath = discovered.Path
-				}
-			}
-
+		if !flagCheck && (changed || localCommit == "") {
 			if skillPath == "" {
 				results[len(results)-1].status = "error"
 				results[len(results)-1].err = fmt.Errorf("skill path not found")
@@ -255,25 +336,80 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 			}
 
 			dstPath := filepath.Join(targetDir, is.skill.Name)
-			os.RemoveAll(dstPath)
 
-			if err := copyDir(skillPath, dstPath); err != nil {
+			// Files edited since install are handled by --on-conflict; the
+			// merge base is the file at the commit that was installed.
+			opts := skillmerge.Options{Policy: policy}
+			if is.meta != nil {
+				opts.Baseline = is.meta.Files
+				opts.Base = func(p string) ([]byte, error) {
+					return readFileAtCommit(cloneResult.TempDir, localCommit, filepath.Join(relPath, p))
+				}
+			}
+			// The new version is built in a staging directory and swapped
+			// in; the old one is kept as a backup for "skills-x rollback".
+			var mergeResult *skillmerge.Result
+			err := tx.Install(dstPath, func(stageDir string) error {
+				var err error
+				mergeResult, err = skillmerge.Apply(dstPath, skillPath, stageDir, opts)
+				if err != nil {
+					return err
+				}
+				if _, err := store.Share(stageDir, dstPath, linkMode); err != nil {
+					return err
+				}
+				// Hashes describe the upstream files, so files kept or
+				// merged locally still count as local modifications next time.
+				files, _ := skilldiff.HashFiles(skillPath)
+				return tui.WriteSkillMeta(stageDir, tui.SkillMeta{
+					Skill:       is.skill.Name,
+					Source:      is.source.Name,
+					Repo:        is.source.Repo,
+					Commit:      remoteCommit,
+					Ref:         ref,
+					TreeHash:    remoteTree,
+					ResolvedRef: gitutil.ArchiveRef(cloneResult.TempDir),
+					Files:       files,
+				})
+			})
+			results[len(results)-1].merge = mergeResult
+			if errors.Is(err, skillmerge.ErrLocalChanges) {
+				results[len(results)-1].status = "local_changes"
+				continue
+			}
+			if err != nil {
 				results[len(results)-1].status = "error"
 				results[len(results)-1].err = fmt.Errorf("copy failed: %w", err)
 				continue
 			}
 
-			// Write meta
-			meta := tui.SkillMeta{
-				Skill:  is.skill.Name,
-				Source: is.source.Name,
-				Repo:   is.source.Repo,
-				Commit: remoteCommit,
+			// Keep the lockfile pinned to the commit we just installed, or
+			// to the archive when it did not record one
+			fullCommit, archive, err := getLockPin(cloneResult.TempDir)
+			if err == nil {
+				err = lockfile.Record(targetDir, lockfile.Entry{
+					Name:    is.skill.Name,
+					Source:  is.source.Name,
+					Repo:    is.source.Repo,
+					Path:    filepath.ToSlash(relPath),
+					Branch:  is.source.Branch,
+					Ref:     ref,
+					Commit:  fullCommit,
+					Archive: archive,
+				})
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ failed to update %s: %v\n", lockfile.FileName, err)
+			}
+			// Regenerate the product's converted rules from the new version
+			if err := adapter.Apply(targetDir, is.skill.Name); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ %s: %v\n", i18n.Tf("adapter_failed", is.skill.Name), err)
 			}
-			_ = tui.WriteSkillMeta(dstPath, meta)
 		}
 	}
 
+	tx.Commit()
+
 	// Print results
 	for _, r := range results {
 		name := padRight(r.name, 25)
@@ -292,13 +428,19 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 			} else {
 				fmt.Printf("  %s-%s %s %sno metadata, reinstalling%s\n", colorGray, colorReset, name, colorGray, colorReset)
 			}
+		case "local_changes":
+			fmt.Printf("  %s!%s %s %slocally modified, skipped%s (%s → %s)\n", colorYellow, colorReset, name, colorYellow, colorReset, r.localCommit, r.remoteCommit)
 		case "error":
 			fmt.Printf("  %s✗%s %s %serror: %v%s\n", colorRed, colorReset, name, colorRed, r.err, colorReset)
 		}
+		printMergeResult(r.merge, r.status == "local_changes")
 	}
 
 	fmt.Println()
 
+	if refused := countStatus(results, "local_changes"); refused > 0 {
+		fmt.Printf("%d skill(s) have local modifications. Rerun with %s--on-conflict=merge%s, keep-local or take-upstream.\n", refused, colorBold, colorReset)
+	}
 	if flagCheck && updateAvailable > 0 {
 		fmt.Printf("%d skill(s) can be updated. Run: %sskills-x update --all%s\n", updateAvailable, colorBold, colorReset)
 	} else if !flagCheck {
@@ -310,6 +452,49 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printMergeResult lists the locally modified files of one skill and what
+// the update did with them
+func printMergeResult(res *skillmerge.Result, refused bool) {
+	if res == nil || len(res.Modified) == 0 {
+		return
+	}
+	if refused {
+		for _, p := range res.Modified {
+			fmt.Printf("      %sM %s%s\n", colorYellow, p, colorReset)
+		}
+		return
+	}
+
+	handled := make(map[string]bool)
+	for _, p := range res.Conflicts {
+		fmt.Printf("      %sC %s%s %s(conflict, resolve by hand)%s\n", colorRed, p, colorReset, colorGray, colorReset)
+		handled[p] = true
+	}
+	for _, p := range res.Merged {
+		fmt.Printf("      %sM %s%s %s(merged)%s\n", colorGreen, p, colorReset, colorGray, colorReset)
+		handled[p] = true
+	}
+	for _, p := range res.Kept {
+		fmt.Printf("      %sK %s%s %s(kept local)%s\n", colorCyan, p, colorReset, colorGray, colorReset)
+		handled[p] = true
+	}
+	for _, p := range res.Modified {
+		if !handled[p] {
+			fmt.Printf("      %sU %s%s %s(local change overwritten)%s\n", colorGray, p, colorReset, colorGray, colorReset)
+		}
+	}
+}
+
+func countStatus(results []skillCheckResult, status string) int {
+	n := 0
+	for _, r := range results {
+		if r.status == status {
+			n++
+		}
+	}
+	return n
+}
+
 func padRight(s string, width int) string {
 	for len(s) < width {
 		s += " "
@@ -351,55 +536,3 @@ func findSkillInRepo(repoPath string, skillName string) (*discover.DiscoveredSki
 
 	return nil, nil
 }
-
-// copyDir copies a directory recursively
-func copyDir(srcPath string, dstPath string) error {
-	os.RemoveAll(dstPath)
-
-	return filepath.WalkDir(srcPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		relPath, err := filepath.Rel(srcPath, path)
-		if err != nil {
-			return err
-		}
-
-		targetPath := filepath.Join(dstPath, relPath)
-
-		if d.Type()&os.ModeSymlink != 0 {
-			realPath, err := filepath.EvalSymlinks(path)
-			if err != nil {
-				return nil
-			}
-			info, err := os.Stat(realPath)
-			if err != nil {
-				return nil
-			}
-			if info.IsDir() {
-				return copyDir(realPath, targetPath)
-			}
-			return copyFile(realPath, targetPath)
-		}
-
-		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0755)
-		}
-
-		return copyFile(path, targetPath)
-	})
-}
-
-// copyFile copies a single file
-func copyFile(srcPath string, dstPath string) error {
-	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
-		return err
-	}
-
-	data, err := os.ReadFile(srcPath)
-	if err != nil {
-		return err
-	}
-	return os.WriteFile(dstPath, data, 0644)
-}
@@ -0,0 +1,104 @@
+package updatecmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+)
+
+// tarball builds a git-archive style tarball with one top-level directory
+func tarball(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{
+		Typeflag:   tar.TypeXGlobalHeader,
+		Name:       "pax_global_header",
+		PAXRecords: map[string]string{"comment": "1111111111111111111111111111111111111111"},
+		Format:     tar.FormatPAX,
+	})
+	for name, body := range files {
+		tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "repo-v2/" + name, Mode: 0644, Size: int64(len(body))})
+		tw.Write([]byte(body))
+	}
+	tw.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestRunUpdate_ArchiveTransport(t *testing.T) {
+	archive := tarball(t, map[string]string{
+		"skills/archived-demo/SKILL.md": "# demo v2\n",
+		"skills/other/SKILL.md":         "# other\n",
+	})
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Write(archive)
+	}))
+	defer srv.Close()
+
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("TMPDIR", t.TempDir())
+	userRegistry := []byte(`archived:
+  repo: https://example.com/team/skills
+  ref: v2
+  transport: archive
+  archive: ` + srv.URL + `/team/skills/{ref}.tar.gz
+  skills:
+    - name: archived-demo
+      path: skills/archived-demo
+`)
+	if err := os.MkdirAll(filepath.Join(configDir, "skills-x"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "skills-x", "user-registry.yaml"), userRegistry, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	targetDir := t.TempDir()
+	skillDir := filepath.Join(targetDir, "archived-demo")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# demo v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := tui.WriteSkillMeta(skillDir, tui.SkillMeta{Skill: "archived-demo", Source: "archived", Commit: "0000000"}); err != nil {
+		t.Fatal(err)
+	}
+
+	origAll, origCheck, origTarget, origPolicy := flagAll, flagCheck, flagTarget, flagOnConflict
+	defer func() {
+		flagAll, flagCheck, flagTarget, flagOnConflict = origAll, origCheck, origTarget, origPolicy
+	}()
+	flagAll, flagCheck, flagTarget, flagOnConflict = false, false, targetDir, ""
+
+	if err := runUpdate(nil, []string{"archived-demo"}); err != nil {
+		t.Fatalf("runUpdate returned error: %v", err)
+	}
+
+	if gotPath != "/team/skills/v2.tar.gz" {
+		t.Errorf("downloaded %q; want the archive template at the source ref", gotPath)
+	}
+	data, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+	if err != nil || string(data) != "# demo v2\n" {
+		t.Fatalf("SKILL.md = %q, %v; want the archived version", data, err)
+	}
+	meta, err := tui.ReadSkillMeta(skillDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.ResolvedRef != "v2" || meta.Commit != "1111111" {
+		t.Errorf("meta resolved_ref = %q, commit = %q; want v2 and 1111111", meta.ResolvedRef, meta.Commit)
+	}
+}
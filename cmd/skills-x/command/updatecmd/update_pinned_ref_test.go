@@ -0,0 +1,58 @@
+package updatecmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/gitutil"
+)
+
+func TestRunUpdate_PinnedSkillStaysOnRef(t *testing.T) {
+	targetDir := t.TempDir()
+	skillDir := filepath.Join(targetDir, "gve")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatalf("mkdir skill dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# test\n"), 0644); err != nil {
+		t.Fatalf("write SKILL.md: %v", err)
+	}
+	meta := []byte(`{"skill":"gve","source":"castle-x-gve","repo":"github.com/castle-x/gve","commit":"old1234","ref":"v1.0.0","installed_at":"2026-03-05T00:00:00Z"}`)
+	if err := os.WriteFile(filepath.Join(skillDir, ".skills-x-meta.json"), meta, 0644); err != nil {
+		t.Fatalf("write meta: %v", err)
+	}
+
+	origAll, origCheck, origTarget := flagAll, flagCheck, flagTarget
+	origClone, origAtRef := cloneRepoWithRefresh, cloneRepoAtRef
+	origHead := getRepoHeadCommit
+	defer func() {
+		flagAll, flagCheck, flagTarget = origAll, origCheck, origTarget
+		cloneRepoWithRefresh, cloneRepoAtRef = origClone, origAtRef
+		getRepoHeadCommit = origHead
+	}()
+
+	flagAll = false
+	flagCheck = true
+	flagTarget = targetDir
+
+	cloneRepoWithRefresh = func(gitURL string, repoName string, branch string, refresh bool) (*gitutil.CloneResult, error) {
+		t.Fatalf("pinned skill must not fetch the branch tip")
+		return nil, nil
+	}
+	gotRef := ""
+	cloneRepoAtRef = func(gitURL string, repoName string, ref string) (*gitutil.CloneResult, error) {
+		gotRef = ref
+		return &gitutil.CloneResult{TempDir: t.TempDir(), Repo: repoName}, nil
+	}
+	getRepoHeadCommit = func(repoDir string) (string, error) {
+		return "old1234", nil
+	}
+
+	if err := runUpdate(nil, []string{"gve"}); err != nil {
+		t.Fatalf("runUpdate returned error: %v", err)
+	}
+
+	if gotRef != "v1.0.0" {
+		t.Fatalf("cloneRepoAtRef ref = %q; want %q", gotRef, "v1.0.0")
+	}
+}
@@ -0,0 +1,65 @@
+package updatecmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/gitutil"
+)
+
+func TestRunUpdate_UnchangedTreeIsUpToDate(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	targetDir := t.TempDir()
+	skillDir := filepath.Join(targetDir, "gve")
+	if err := os.MkdirAll(skillDir, 0755); err != nil {
+		t.Fatalf("mkdir skill dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# local\n"), 0644); err != nil {
+		t.Fatalf("write SKILL.md: %v", err)
+	}
+	meta := []byte(`{"skill":"gve","source":"castle-x-gve","repo":"github.com/castle-x/gve","commit":"old1234","tree_hash":"tree-a","installed_at":"2026-03-05T00:00:00Z"}`)
+	if err := os.WriteFile(filepath.Join(skillDir, ".skills-x-meta.json"), meta, 0644); err != nil {
+		t.Fatalf("write meta: %v", err)
+	}
+
+	origAll, origCheck, origTarget := flagAll, flagCheck, flagTarget
+	origClone, origHead, origTree := cloneRepoWithRefresh, getRepoHeadCommit, getTreeHash
+	defer func() {
+		flagAll, flagCheck, flagTarget = origAll, origCheck, origTarget
+		cloneRepoWithRefresh, getRepoHeadCommit, getTreeHash = origClone, origHead, origTree
+	}()
+
+	flagAll = false
+	flagCheck = false
+	flagTarget = targetDir
+
+	cloneRepoWithRefresh = func(gitURL string, repoName string, branch string, refresh bool) (*gitutil.CloneResult, error) {
+		return &gitutil.CloneResult{TempDir: t.TempDir(), Repo: repoName}, nil
+	}
+	// The repo moved on, but nothing under skills/gve changed
+	getRepoHeadCommit = func(repoDir string) (string, error) {
+		return "new9999", nil
+	}
+	getTreeHash = func(repoDir string, path string) (string, error) {
+		if path != filepath.Join("skills", "gve") {
+			t.Fatalf("tree hash requested for %q; want skills/gve", path)
+		}
+		return "tree-a", nil
+	}
+
+	if err := runUpdate(nil, []string{"gve"}); err != nil {
+		t.Fatalf("runUpdate returned error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(skillDir, "SKILL.md"))
+	if err != nil || string(data) != "# local\n" {
+		t.Fatalf("unchanged skill should not be reinstalled, SKILL.md = %q (%v)", data, err)
+	}
+	got, err := tui.ReadSkillMeta(skillDir)
+	if err != nil || got.Commit != "old1234" {
+		t.Fatalf("meta should be untouched, got %+v (%v)", got, err)
+	}
+}
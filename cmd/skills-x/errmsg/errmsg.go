@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/pkg/gitauth"
 )
 
 // ANSI color codes
@@ -87,12 +88,13 @@ func IsCustomError(err error) bool {
 	return ok
 }
 
-// PrintError prints the error, formatted if custom error, plain otherwise
+// PrintError prints the error, formatted if custom error, plain otherwise.
+// Tokens are hidden from plain errors, which may quote git output.
 func PrintError(err error) {
 	if e, ok := err.(*Error); ok {
 		e.Print()
 	} else {
-		fmt.Printf("\n%s%s%s: %s%s\n\n", colorBold, colorRed, i18n.T("err_title"), err.Error(), colorReset)
+		fmt.Printf("\n%s%s%s: %s%s\n\n", colorBold, colorRed, i18n.T("err_title"), gitauth.Redact(err.Error()), colorReset)
 	}
 }
 
@@ -147,3 +149,38 @@ func CopyFailed(skillName string) *Error {
 		},
 	}
 }
+
+// ManifestNotFound returns an error when skills.yaml is missing
+func ManifestNotFound(path string) *Error {
+	return &Error{
+		Title:  i18n.T("err_manifest_not_found"),
+		Detail: i18n.Tf("err_manifest_not_found_detail", path),
+		Solutions: []string{
+			i18n.T("err_manifest_not_found_sol1"),
+			i18n.T("err_manifest_not_found_sol2"),
+		},
+	}
+}
+
+// ManifestInvalid returns an error when skills.yaml cannot be parsed or validated
+func ManifestInvalid(path string, err error) *Error {
+	return &Error{
+		Title:  i18n.T("err_manifest_invalid"),
+		Detail: i18n.Tf("err_manifest_invalid_detail", path, err),
+		Solutions: []string{
+			i18n.T("err_manifest_invalid_sol1"),
+		},
+	}
+}
+
+// LockfileNotFound returns an error when skills-x.lock is missing
+func LockfileNotFound(path string) *Error {
+	return &Error{
+		Title:  i18n.T("err_lockfile_not_found"),
+		Detail: i18n.Tf("err_lockfile_not_found_detail", path),
+		Solutions: []string{
+			i18n.T("err_lockfile_not_found_sol1"),
+			i18n.T("err_lockfile_not_found_sol2"),
+		},
+	}
+}
@@ -6,13 +6,22 @@ import (
 	"os"
 	"time"
 
+	"github.com/castle-x/skills-x/cmd/skills-x/command/diffcmd"
+	"github.com/castle-x/skills-x/cmd/skills-x/command/indexcmd"
 	"github.com/castle-x/skills-x/cmd/skills-x/command/initcmd"
+	"github.com/castle-x/skills-x/cmd/skills-x/command/installcmd"
 	"github.com/castle-x/skills-x/cmd/skills-x/command/list"
 	"github.com/castle-x/skills-x/cmd/skills-x/command/registry"
+	"github.com/castle-x/skills-x/cmd/skills-x/command/rollbackcmd"
+	"github.com/castle-x/skills-x/cmd/skills-x/command/searchcmd"
+	"github.com/castle-x/skills-x/cmd/skills-x/command/storecmd"
+	"github.com/castle-x/skills-x/cmd/skills-x/command/synccmd"
+	"github.com/castle-x/skills-x/cmd/skills-x/command/uninstallcmd"
 	"github.com/castle-x/skills-x/cmd/skills-x/command/updatecmd"
 	"github.com/castle-x/skills-x/cmd/skills-x/errmsg"
 	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
 	"github.com/castle-x/skills-x/cmd/skills-x/tui"
+	"github.com/castle-x/skills-x/pkg/products"
 	"github.com/castle-x/skills-x/pkg/versioncheck"
 	"github.com/spf13/cobra"
 )
@@ -26,6 +35,12 @@ var (
 func main() {
 	// Initialize i18n
 	i18n.MustInit()
+	versioncheck.Running = Version
+
+	// A broken products.yaml falls back to the built-in products
+	if err := products.LoadError(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", i18n.Tf("products_config_invalid", err))
+	}
 
 	rootCmd := &cobra.Command{
 		Use:     "skills-x",
@@ -42,15 +57,24 @@ func main() {
 				Version:   Version,
 				TargetDir: cwd,
 			}
+			defer indexcmd.AutoRefreshProject(cwd)
 			return tui.RunTUI(opts)
 		},
 	}
 
 	// Register subcommands
-	rootCmd.AddCommand(list.NewCommand())       // list
-	rootCmd.AddCommand(initcmd.NewCommand())    // init
-	rootCmd.AddCommand(updatecmd.NewCommand())  // update
-	rootCmd.AddCommand(registry.NewCommand())   // registry
+	rootCmd.AddCommand(list.NewCommand())         // list
+	rootCmd.AddCommand(searchcmd.NewCommand())    // search
+	rootCmd.AddCommand(initcmd.NewCommand())      // init
+	rootCmd.AddCommand(installcmd.NewCommand())   // install
+	rootCmd.AddCommand(synccmd.NewCommand())      // sync
+	rootCmd.AddCommand(updatecmd.NewCommand())    // update
+	rootCmd.AddCommand(uninstallcmd.NewCommand()) // uninstall
+	rootCmd.AddCommand(diffcmd.NewCommand())      // diff
+	rootCmd.AddCommand(rollbackcmd.NewCommand())  // rollback
+	rootCmd.AddCommand(storecmd.NewCommand())     // store
+	rootCmd.AddCommand(indexcmd.NewCommand())     // index
+	rootCmd.AddCommand(registry.NewCommand())     // registry
 
 	// Disable cobra's default error output
 	rootCmd.SilenceErrors = true
@@ -0,0 +1,61 @@
+// Package targets resolves the install directories named on the command
+// line: --target, or repeatable --product and --scope flags that fan one
+// command out to several AI tools at once.
+package targets
+
+import (
+	"fmt"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/pkg/products"
+	"github.com/spf13/cobra"
+)
+
+// Flags holds the --product and --scope values of a command
+type Flags struct {
+	Products []string
+	Scopes   []string
+}
+
+// Register adds --product and --scope to cmd
+func (f *Flags) Register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&f.Products, "product", nil, i18n.T("flag_targets_product"))
+	cmd.Flags().StringArrayVar(&f.Scopes, "scope", nil, i18n.T("flag_targets_scope"))
+}
+
+// Resolve returns the directories to work on: every --product at every
+// --scope, with project directories under projectDir, or else the --target
+// value, or else defaultDir. Targets without --product have no Product.
+func (f *Flags) Resolve(target string, projectDir string, defaultDir string) ([]products.Target, error) {
+	if len(f.Products) == 0 {
+		if len(f.Scopes) > 0 {
+			return nil, fmt.Errorf("%s", i18n.T("targets_scope_without_product"))
+		}
+		if target == "" {
+			target = defaultDir
+		}
+		return []products.Target{{Dir: target}}, nil
+	}
+	if target != "" {
+		return nil, fmt.Errorf("%s", i18n.T("targets_target_with_product"))
+	}
+	return products.ResolveTargets(f.Products, f.Scopes, projectDir)
+}
+
+// Label names a target for output: "Claude Code (global)", or its directory
+// when it came from --target
+func Label(t products.Target) string {
+	if t.Product == nil {
+		return t.Dir
+	}
+	return fmt.Sprintf("%s (%s)", t.Product.Name, t.Scope)
+}
+
+// Dirs returns the directories of ts
+func Dirs(ts []products.Target) []string {
+	dirs := make([]string, len(ts))
+	for i, t := range ts {
+		dirs[i] = t.Dir
+	}
+	return dirs
+}
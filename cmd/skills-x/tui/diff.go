@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/skilldiff"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// SkillDiff describes how an installed skill differs from its upstream copy
+type SkillDiff struct {
+	Name         string
+	Repo         string
+	LocalCommit  string                 // Commit recorded in the meta file ("" if unknown)
+	RemoteCommit string                 // Commit the upstream copy was taken from
+	Changes      []skilldiff.FileChange // Files that differ, installed → upstream
+	Log          []string               // Commits touching the skill since LocalCommit, newest first
+	LogErr       error                  // Set when the history could not be read
+}
+
+// LoadSkillDiff fetches the upstream copy of an installed skill and compares
+// it with the installed directory. The upstream side follows the same rules
+// as an update: the pinned ref if there is one, otherwise the branch tip.
+func LoadSkillDiff(targetDir string, name string) (*SkillDiff, error) {
+	skillDir := filepath.Join(targetDir, name)
+	if !dirExists(skillDir) {
+		return nil, fmt.Errorf("%s", i18n.Tf("diff_not_installed", name, targetDir))
+	}
+	meta, _ := ReadSkillMeta(skillDir)
+
+	reg, err := loadMergedRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	matches := reg.FindSkillsWithConflict(name)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%s", i18n.T("tui_err_not_in_registry"))
+	}
+	var skill *registry.Skill
+	var source *registry.Source
+	for _, match := range matches {
+		if meta != nil && match.Source.Name == meta.Source {
+			skill = match.Skill
+			source = match.Source
+			break
+		}
+	}
+	if skill == nil || source == nil {
+		skill = matches[0].Skill
+		source = matches[0].Source
+	}
+
+	ref := source.SkillRef(skill)
+	if meta != nil && meta.Ref != "" {
+		ref = meta.Ref
+	}
+
+	var result *gitutil.CloneResult
+	if source.UsesArchive() {
+		archiveRef := source.ArchiveRef(ref)
+		result, err = gitutil.FetchArchive(source.ArchiveURL(archiveRef), source.Repo, archiveRef, skill.FetchPaths(), true)
+	} else if ref != "" {
+		result, err = gitutil.CloneRepoAtRef(source.GetGitURL(), source.Repo, ref)
+	} else if source.SkipFetch && skill.Path != "" {
+		result, err = gitutil.SparseCloneRepo(source.GetGitURL(), source.Repo, source.Branch, []string{skill.Path})
+	} else {
+		result, err = gitutil.CloneRepoWithRefresh(source.GetGitURL(), source.Repo, source.Branch, true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("tui_err_fetch_repo"), err)
+	}
+
+	var skillPath string
+	if skill.Path != "" {
+		skillPath = filepath.Join(result.TempDir, skill.Path)
+	} else if discovered, _ := findSkillInRepo(result.TempDir, skill.Name); discovered != nil {
+		skillPath = discovered.Path
+	}
+	if skillPath == "" || !dirExists(skillPath) {
+		return nil, fmt.Errorf("skill path not found: %s", skill.Name)
+	}
+
+	remoteCommit, err := gitutil.GetRepoHeadCommit(result.TempDir)
+	if err != nil {
+		return nil, fmt.Errorf(i18n.T("tui_err_get_commit"), err)
+	}
+
+	changes, err := skilldiff.Compare(skillDir, skillPath)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &SkillDiff{
+		Name:         name,
+		Repo:         source.Repo,
+		RemoteCommit: remoteCommit,
+		Changes:      changes,
+	}
+	if meta != nil {
+		d.LocalCommit = meta.Commit
+	}
+	if d.LocalCommit != "" && d.LocalCommit != remoteCommit {
+		relPath, _ := filepath.Rel(result.TempDir, skillPath)
+		d.Log, d.LogErr = gitutil.GetLogSubjects(result.TempDir, d.LocalCommit, relPath)
+	}
+	return d, nil
+}
+
+// renderDiffLines renders a SkillDiff as styled lines for the TUI diff view
+func renderDiffLines(d *SkillDiff) []string {
+	var lines []string
+
+	local := d.LocalCommit
+	if local == "" {
+		local = "?"
+	}
+	lines = append(lines, titleStyle.Render(d.Name)+"  "+hintStyle.Render(local+" → "+d.RemoteCommit+"  "+d.Repo), "")
+
+	if d.LogErr != nil {
+		lines = append(lines, warningStyle.Render(i18n.Tf("diff_log_unavailable", d.LogErr)), "")
+	} else if len(d.Log) > 0 {
+		lines = append(lines, valueStyle.Render(i18n.T("diff_commits")))
+		for _, l := range d.Log {
+			lines = append(lines, "  "+l)
+		}
+		lines = append(lines, "")
+	}
+
+	if len(d.Changes) == 0 {
+		lines = append(lines, successStyle.Render(i18n.T("diff_no_changes")))
+		return lines
+	}
+
+	lines = append(lines, valueStyle.Render(i18n.T("diff_files")))
+	for _, c := range d.Changes {
+		lines = append(lines, "  "+statusStyle(c.Status).Render(c.Status.Symbol()+" "+c.Path))
+	}
+
+	for _, c := range d.Changes {
+		lines = append(lines, "")
+		if c.Binary {
+			lines = append(lines, hintStyle.Render(i18n.Tf("diff_binary", c.Path)))
+			continue
+		}
+		for _, l := range strings.Split(strings.TrimSuffix(c.Diff, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"):
+				lines = append(lines, valueStyle.Render(l))
+			case strings.HasPrefix(l, "@@"):
+				lines = append(lines, updateStyle.Render(l))
+			case strings.HasPrefix(l, "+"):
+				lines = append(lines, successStyle.Render(l))
+			case strings.HasPrefix(l, "-"):
+				lines = append(lines, errorStyle.Render(l))
+			default:
+				lines = append(lines, normalStyle.Render(l))
+			}
+		}
+	}
+	return lines
+}
+
+// statusStyle picks the file-list color for a change status
+func statusStyle(s skilldiff.Status) lipgloss.Style {
+	switch s {
+	case skilldiff.Added:
+		return successStyle
+	case skilldiff.Removed:
+		return errorStyle
+	default:
+		return updateStyle
+	}
+}
@@ -2,41 +2,57 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
+	"github.com/castle-x/skills-x/pkg/adapter"
 	"github.com/castle-x/skills-x/pkg/discover"
 	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/lockfile"
+	"github.com/castle-x/skills-x/pkg/products"
 	"github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/skillmerge"
+	"github.com/castle-x/skills-x/pkg/store"
+	"github.com/castle-x/skills-x/pkg/workpool"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// installerWorkers is how many skills the installer processes at once
+const installerWorkers = workpool.DefaultWorkers
+
 // InstallerModel represents the installation state
 type InstallerModel struct {
 	installSkills    []SkillItem
 	updateSkills     []SkillItem
 	uninstallSkills  []SkillItem
-	targetDir        string
-	currentIdx       int
+	targets          []products.Target // every skill goes to each of them
+	currentIdx       int               // next job (skill × target) of the current phase to start
+	running          int               // jobs of the current phase still in flight
 	completed        int
 	failed           int
+	skipped          int // jobs for products a skill is not meant for
 	quitting         bool
 	finished         bool
 	err              error
 	progressMsg      string
 	phase            string   // "install", "update", or "uninstall"
-	installResults   []string // per-skill result: "ok", "fail"
+	installResults   []string // per job, skill-major: "running", "ok", "fail", "skip"
 	updateResults    []string
 	uninstallResults []string
+	tx               *installtx.Tx     // swaps skills in atomically; undone if the batch is cancelled
+	clones           *gitutil.CloneSet // each repository is fetched once per phase
 }
 
-// NewInstallerModel creates a new installer model with three operation lists
-func NewInstallerModel(installSkills, uninstallSkills, updateSkills []SkillItem, targetDir string) InstallerModel {
+// NewInstallerModel creates a new installer model with three operation lists,
+// each applied to every target
+func NewInstallerModel(installSkills, uninstallSkills, updateSkills []SkillItem, targets []products.Target) InstallerModel {
 	phase := "install"
 	if len(installSkills) == 0 {
 		phase = "update"
@@ -48,168 +64,175 @@ func NewInstallerModel(installSkills, uninstallSkills, updateSkills []SkillItem,
 		installSkills:    installSkills,
 		updateSkills:     updateSkills,
 		uninstallSkills:  uninstallSkills,
-		targetDir:        targetDir,
+		targets:          targets,
 		currentIdx:       0,
 		completed:        0,
 		failed:           0,
 		phase:            phase,
-		installResults:   make([]string, len(installSkills)),
-		updateResults:    make([]string, len(updateSkills)),
-		uninstallResults: make([]string, len(uninstallSkills)),
+		installResults:   make([]string, len(installSkills)*len(targets)),
+		updateResults:    make([]string, len(updateSkills)*len(targets)),
+		uninstallResults: make([]string, len(uninstallSkills)*len(targets)),
+		tx:               installtx.New(),
+		clones:           gitutil.NewCloneSet(),
 	}
 }
 
+// installStartMsg starts the first wave of workers
+type installStartMsg struct{}
+
 func (m InstallerModel) Init() tea.Cmd {
-	return m.installNext
+	return func() tea.Msg { return installStartMsg{} }
 }
 
-// installProgressMsg is a message sent during installation progress
+// installProgressMsg is a message sent when one skill has been processed in
+// one target
 type installProgressMsg struct {
-	current      int
+	phase        string
+	index        int // job index: skill*len(targets) + target
 	completedAdd int
 	failedAdd    int
+	skippedAdd   int
 	skill        string
 	progress     string
 	result       string // "ok" or "fail"
 }
 
-// installNext executes the current operation and returns a progress message
-func (m *InstallerModel) installNext() tea.Msg {
-	switch m.phase {
+// phaseSkills returns the skills and result slots of a phase
+func (m *InstallerModel) phaseSkills(phase string) ([]SkillItem, []string) {
+	switch phase {
 	case "install":
-		if m.currentIdx >= len(m.installSkills) {
-			return nil
-		}
-		skill := m.installSkills[m.currentIdx]
-		var progress, result string
-		var completed, failed int
+		return m.installSkills, m.installResults
+	case "update":
+		return m.updateSkills, m.updateResults
+	default:
+		return m.uninstallSkills, m.uninstallResults
+	}
+}
 
-		tempDir, err := m.installSkill(skill)
-		if err != nil {
-			failed = 1
-			result = "fail"
-			progress = i18n.Tf("tui_installer_fail_install", m.currentIdx+1, len(m.installSkills), skill.FullName, err)
-		} else {
-			completed = 1
-			result = "ok"
-			progress = i18n.Tf("tui_installer_progress_install", m.currentIdx+1, len(m.installSkills), skill.FullName)
-			m.writeMetaForSkill(skill, tempDir)
+// dispatch starts jobs of the current phase until installerWorkers are in
+// flight. Phases still run one after another: a phase starts once every
+// job of the previous one has finished.
+func (m *InstallerModel) dispatch() tea.Cmd {
+	var cmds []tea.Cmd
+	for {
+		_, results := m.phaseSkills(m.phase)
+		for m.running < installerWorkers && m.currentIdx < len(results) {
+			results[m.currentIdx] = "running"
+			cmds = append(cmds, m.processCmd(m.phase, m.currentIdx))
+			m.currentIdx++
+			m.running++
 		}
-		return installProgressMsg{
-			current: m.currentIdx + 1, completedAdd: completed, failedAdd: failed,
-			skill: skill.FullName, progress: progress, result: result,
+		if m.running > 0 || m.currentIdx < len(results) {
+			break
 		}
 
-	case "update":
-		if m.currentIdx >= len(m.updateSkills) {
+		// Phase done: move on to the next non-empty one
+		switch m.phase {
+		case "install":
+			m.phase = "update"
+		case "update":
+			m.phase = "uninstall"
+		default:
+			m.finished = true
 			return nil
 		}
-		skill := m.updateSkills[m.currentIdx]
-		var progress, result string
-		var completed, failed int
+		m.currentIdx = 0
+		m.clones = gitutil.NewCloneSet()
+	}
+	return tea.Batch(cmds...)
+}
 
-		tempDir, err := m.updateSkill(skill)
-		if err != nil {
-			failed = 1
-			result = "fail"
-			progress = i18n.Tf("tui_installer_fail_update", m.currentIdx+1, len(m.updateSkills), skill.FullName, err)
-		} else {
-			completed = 1
-			result = "ok"
-			progress = i18n.Tf("tui_installer_progress_update", m.currentIdx+1, len(m.updateSkills), skill.FullName)
-			m.writeMetaForSkill(skill, tempDir)
-		}
-		return installProgressMsg{
-			current: m.currentIdx + 1, completedAdd: completed, failedAdd: failed,
-			skill: skill.FullName, progress: progress, result: result,
+// processCmd returns a command that installs, updates or uninstalls one
+// skill in one target
+func (m *InstallerModel) processCmd(phase string, idx int) tea.Cmd {
+	skills, results := m.phaseSkills(phase)
+	skill := skills[idx/len(m.targets)]
+	target := m.targets[idx%len(m.targets)]
+	total := len(results)
+	name := skill.FullName
+	if len(m.targets) > 1 {
+		name += " → " + targetLabel(target)
+	}
+	w := *m // the command runs on its own goroutine; give it a snapshot
+	return func() tea.Msg {
+		msg := installProgressMsg{phase: phase, index: idx, skill: skill.FullName}
+
+		// Skills whose registry products list excludes the target's
+		// product are not installed there
+		if phase == "install" && !skill.meantFor(target) {
+			msg.skippedAdd = 1
+			msg.result = "skip"
+			msg.progress = i18n.Tf("tui_installer_skip_product", idx+1, total, name, target.Product.Name)
+			return msg
 		}
 
-	case "uninstall":
-		if m.currentIdx >= len(m.uninstallSkills) {
-			return nil
+		var origin *installOrigin
+		var err error
+		var okKey, failKey string
+		switch phase {
+		case "install":
+			origin, err = w.installSkill(skill, target.Dir)
+			okKey, failKey = "tui_installer_progress_install", "tui_installer_fail_install"
+		case "update":
+			origin, err = w.updateSkill(skill, target.Dir)
+			okKey, failKey = "tui_installer_progress_update", "tui_installer_fail_update"
+		default:
+			err = w.uninstallSkill(skill, target.Dir)
+			okKey, failKey = "tui_installer_progress_uninstall", "tui_installer_fail_uninstall"
 		}
-		skill := m.uninstallSkills[m.currentIdx]
-		var progress, result string
-		var completed, failed int
 
-		err := m.uninstallSkill(skill)
 		if err != nil {
-			failed = 1
-			result = "fail"
-			progress = i18n.Tf("tui_installer_fail_uninstall", m.currentIdx+1, len(m.uninstallSkills), skill.FullName, err)
-		} else {
-			completed = 1
-			result = "ok"
-			progress = i18n.Tf("tui_installer_progress_uninstall", m.currentIdx+1, len(m.uninstallSkills), skill.FullName)
+			msg.failedAdd = 1
+			msg.result = "fail"
+			msg.progress = i18n.Tf(failKey, idx+1, total, name, err)
+			return msg
 		}
-		return installProgressMsg{
-			current: m.currentIdx + 1, completedAdd: completed, failedAdd: failed,
-			skill: skill.FullName, progress: progress, result: result,
+		if phase != "uninstall" {
+			w.writeMetaForSkill(skill, origin, target.Dir)
 		}
+		msg.completedAdd = 1
+		msg.result = "ok"
+		msg.progress = i18n.Tf(okKey, idx+1, total, name)
+		return msg
 	}
-	return nil
+}
+
+// meantFor reports whether the skill may be installed into t: always for a
+// plain directory, otherwise when the skill supports t's product
+func (s SkillItem) meantFor(t products.Target) bool {
+	if t.Product == nil {
+		return true
+	}
+	return (&registry.Skill{Products: s.Products}).SupportsProduct(t.Product.Name)
+}
+
+// targetLabel names a target as "Claude Code (global)"
+func targetLabel(t products.Target) string {
+	scope := i18n.T("tui_project")
+	if t.Scope == products.ScopeGlobal {
+		scope = i18n.T("tui_global")
+	}
+	return t.Product.Name + " (" + scope + ")"
 }
 
 func (m InstallerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
+	case installStartMsg:
+		return m, m.dispatch()
+
 	case installProgressMsg:
-		idx := msg.current - 1
-		switch m.phase {
-		case "install":
-			if idx >= 0 && idx < len(m.installResults) {
-				m.installResults[idx] = msg.result
-			}
-		case "update":
-			if idx >= 0 && idx < len(m.updateResults) {
-				m.updateResults[idx] = msg.result
-			}
-		case "uninstall":
-			if idx >= 0 && idx < len(m.uninstallResults) {
-				m.uninstallResults[idx] = msg.result
-			}
+		_, results := m.phaseSkills(msg.phase)
+		if msg.index >= 0 && msg.index < len(results) {
+			results[msg.index] = msg.result
 		}
 
-		m.currentIdx = msg.current
+		m.running--
 		m.completed += msg.completedAdd
 		m.failed += msg.failedAdd
+		m.skipped += msg.skippedAdd
 		m.progressMsg = msg.progress
 
-		// Continue current phase if not done
-		switch m.phase {
-		case "install":
-			if m.currentIdx < len(m.installSkills) {
-				return m, m.installNext
-			}
-			// Transition to update phase
-			if len(m.updateSkills) > 0 {
-				m.phase = "update"
-				m.currentIdx = 0
-				return m, m.installNext
-			}
-			// Transition to uninstall phase
-			if len(m.uninstallSkills) > 0 {
-				m.phase = "uninstall"
-				m.currentIdx = 0
-				return m, m.installNext
-			}
-		case "update":
-			if m.currentIdx < len(m.updateSkills) {
-				return m, m.installNext
-			}
-			// Transition to uninstall phase
-			if len(m.uninstallSkills) > 0 {
-				m.phase = "uninstall"
-				m.currentIdx = 0
-				return m, m.installNext
-			}
-		case "uninstall":
-			if m.currentIdx < len(m.uninstallSkills) {
-				return m, m.installNext
-			}
-		}
-
-		m.finished = true
-		return m, nil
+		return m, m.dispatch()
 
 	case tea.KeyMsg:
 		if msg.String() == "q" || msg.String() == "ctrl+c" {
@@ -226,20 +249,14 @@ func (m InstallerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 func (m InstallerModel) View() string {
 	var b strings.Builder
 
-	totalItems := len(m.installSkills) + len(m.updateSkills) + len(m.uninstallSkills)
+	totalItems := len(m.installResults) + len(m.updateResults) + len(m.uninstallResults)
 
 	b.WriteString(titleStyle.Render(i18n.T("tui_installer_title")))
 	b.WriteString("\n\n")
 
 	// Progress bar
 	barWidth := 40
-	currentTotal := m.currentIdx
-	switch m.phase {
-	case "update":
-		currentTotal = len(m.installSkills) + m.currentIdx
-	case "uninstall":
-		currentTotal = len(m.installSkills) + len(m.updateSkills) + m.currentIdx
-	}
+	currentTotal := m.completed + m.failed + m.skipped
 	if m.finished {
 		currentTotal = totalItems
 	}
@@ -267,20 +284,7 @@ func (m InstallerModel) View() string {
 	// Install list
 	if len(m.installSkills) > 0 {
 		b.WriteString("\n")
-		for i, skill := range m.installSkills {
-			status := "  "
-			if i < len(m.installResults) && m.installResults[i] != "" {
-				switch m.installResults[i] {
-				case "ok":
-					status = successStyle.Render("✓ ")
-				case "fail":
-					status = errorStyle.Render("✗ ")
-				}
-			} else if m.phase == "install" && i == m.currentIdx {
-				status = hintStyle.Render("▸ ")
-			}
-			b.WriteString(fmt.Sprintf("  %s%s\n", status, skill.FullName))
-		}
+		m.renderJobs(&b, m.installSkills, m.installResults)
 	}
 
 	// Update list
@@ -288,20 +292,7 @@ func (m InstallerModel) View() string {
 		b.WriteString("\n")
 		b.WriteString(updateStyle.Render(i18n.T("tui_installer_updating")))
 		b.WriteString("\n")
-		for i, skill := range m.updateSkills {
-			status := "  "
-			if i < len(m.updateResults) && m.updateResults[i] != "" {
-				switch m.updateResults[i] {
-				case "ok":
-					status = successStyle.Render("✓ ")
-				case "fail":
-					status = errorStyle.Render("✗ ")
-				}
-			} else if m.phase == "update" && i == m.currentIdx {
-				status = hintStyle.Render("▸ ")
-			}
-			b.WriteString(fmt.Sprintf("  %s%s\n", status, skill.FullName))
-		}
+		m.renderJobs(&b, m.updateSkills, m.updateResults)
 	}
 
 	// Uninstall list
@@ -309,20 +300,7 @@ func (m InstallerModel) View() string {
 		b.WriteString("\n")
 		b.WriteString(hintStyle.Render(i18n.T("tui_installer_uninstalling")))
 		b.WriteString("\n")
-		for i, skill := range m.uninstallSkills {
-			status := "  "
-			if i < len(m.uninstallResults) && m.uninstallResults[i] != "" {
-				switch m.uninstallResults[i] {
-				case "ok":
-					status = successStyle.Render("✓ ")
-				case "fail":
-					status = errorStyle.Render("✗ ")
-				}
-			} else if m.phase == "uninstall" && i == m.currentIdx {
-				status = hintStyle.Render("▸ ")
-			}
-			b.WriteString(fmt.Sprintf("  %s%s\n", status, skill.FullName))
-		}
+		m.renderJobs(&b, m.uninstallSkills, m.uninstallResults)
 	}
 
 	// Bottom hint
@@ -340,6 +318,66 @@ func (m InstallerModel) View() string {
 	return b.String()
 }
 
+// renderJobs writes one line per skill. With several targets the line
+// carries the skill's overall status followed by a mark per target.
+func (m InstallerModel) renderJobs(b *strings.Builder, skills []SkillItem, results []string) {
+	n := len(m.targets)
+	for i, skill := range skills {
+		jobs := results[i*n : (i+1)*n]
+		b.WriteString(fmt.Sprintf("  %s%s", jobStatus(overallResult(jobs)), skill.FullName))
+		if n > 1 {
+			for t, result := range jobs {
+				b.WriteString("  " + jobStatus(result) + hintStyle.Render(targetLabel(m.targets[t])))
+			}
+		}
+		b.WriteString("\n")
+	}
+}
+
+// overallResult folds the results of a skill's targets: running until every
+// target is done, then failed if any failed, skipped if every one was
+func overallResult(results []string) string {
+	done, skipped, failed := 0, 0, false
+	for _, r := range results {
+		switch r {
+		case "ok":
+			done++
+		case "skip":
+			done++
+			skipped++
+		case "fail":
+			done++
+			failed = true
+		}
+	}
+	switch {
+	case done == len(results) && failed:
+		return "fail"
+	case done == len(results) && skipped == len(results):
+		return "skip"
+	case done == len(results):
+		return "ok"
+	case done > 0 || slices.Contains(results, "running"):
+		return "running"
+	}
+	return ""
+}
+
+// jobStatus renders a result as a two-column marker
+func jobStatus(result string) string {
+	switch result {
+	case "ok":
+		return successStyle.Render("✓ ")
+	case "fail":
+		return errorStyle.Render("✗ ")
+	case "running":
+		return hintStyle.Render("▸ ")
+	case "skip":
+		return hintStyle.Render("- ")
+	}
+	return "  "
+}
+
 func (m InstallerModel) IsQuitting() bool {
 	return m.quitting
 }
@@ -360,13 +398,22 @@ func (m InstallerModel) Error() error {
 	return m.err
 }
 
-// writeMetaForSkill writes .skills-x-meta.json after a successful install/update
-func (m *InstallerModel) writeMetaForSkill(item SkillItem, tempDir string) {
-	dstPath := filepath.Join(m.targetDir, item.Name)
+// installOrigin describes where an installed skill was copied from
+type installOrigin struct {
+	cloneDir  string           // cached clone the skill was copied from
+	skillPath string           // skill path relative to cloneDir ("" for repo root)
+	source    *registry.Source // registry source the skill belongs to
+	ref       string           // pinned tag or commit ("" for the branch tip)
+}
+
+// writeMetaForSkill writes .skills-x-meta.json and the skills-x.lock entry
+// in targetDir after a successful install/update
+func (m *InstallerModel) writeMetaForSkill(item SkillItem, origin *installOrigin, targetDir string) {
+	dstPath := filepath.Join(targetDir, item.Name)
 
 	commit := ""
-	if tempDir != "" {
-		commit, _ = gitutil.GetRepoHeadCommit(tempDir)
+	if origin != nil && origin.cloneDir != "" {
+		commit, _ = gitutil.GetRepoHeadCommit(origin.cloneDir)
 	}
 
 	meta := SkillMeta{
@@ -376,48 +423,71 @@ func (m *InstallerModel) writeMetaForSkill(item SkillItem, tempDir string) {
 		Commit:      commit,
 		InstalledAt: time.Now().UTC().Format(time.RFC3339),
 	}
+	if origin != nil {
+		meta.Ref = origin.ref
+		if origin.cloneDir != "" {
+			meta.TreeHash, _ = gitutil.GetTreeHash(origin.cloneDir, origin.skillPath)
+			meta.ResolvedRef = gitutil.ArchiveRef(origin.cloneDir)
+		}
+	}
 	_ = WriteSkillMeta(dstPath, meta)
+
+	if origin == nil || origin.cloneDir == "" {
+		return
+	}
+	fullCommit, archive, err := gitutil.GetLockPin(origin.cloneDir)
+	if err != nil {
+		return
+	}
+	_ = lockfile.Record(targetDir, lockfile.Entry{
+		Name:    item.Name,
+		Source:  origin.source.Name,
+		Repo:    origin.source.Repo,
+		Path:    origin.skillPath,
+		Branch:  origin.source.Branch,
+		Ref:     origin.ref,
+		Commit:  fullCommit,
+		Archive: archive,
+	})
 }
 
-// installSkill installs a single skill, returns the temp/cache dir for meta writing
-func (m *InstallerModel) installSkill(item SkillItem) (string, error) {
-	targetDir := m.targetDir
+// installSkill installs a single skill into targetDir, returns where it was
+// copied from for meta writing
+func (m *InstallerModel) installSkill(item SkillItem, targetDir string) (*installOrigin, error) {
 	if targetDir == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		targetDir = cwd
 	}
 
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	return m.installRegistrySkill(item, targetDir)
 }
 
-// updateSkill updates a single skill using refresh=true
-func (m *InstallerModel) updateSkill(item SkillItem) (string, error) {
-	targetDir := m.targetDir
+// updateSkill updates a single skill in targetDir using refresh=true
+func (m *InstallerModel) updateSkill(item SkillItem, targetDir string) (*installOrigin, error) {
 	if targetDir == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 		targetDir = cwd
 	}
 
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	return m.installRegistrySkillWithRefresh(item, targetDir, true)
 }
 
-// uninstallSkill removes a skill from the target directory
-func (m *InstallerModel) uninstallSkill(item SkillItem) error {
-	targetDir := m.targetDir
+// uninstallSkill removes a skill from targetDir
+func (m *InstallerModel) uninstallSkill(item SkillItem, targetDir string) error {
 	if targetDir == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -431,27 +501,30 @@ func (m *InstallerModel) uninstallSkill(item SkillItem) error {
 		return nil
 	}
 
-	if err := os.RemoveAll(skillPath); err != nil {
+	// Moved to the backup location so a cancelled batch or "skills-x
+	// rollback" can bring it back
+	if err := m.tx.Remove(skillPath); err != nil {
 		return fmt.Errorf("failed to remove skill directory: %w", err)
 	}
+	_ = lockfile.Forget(targetDir, item.Name)
 	return nil
 }
 
 // installRegistrySkill installs a registry skill (refresh=false)
-func (m *InstallerModel) installRegistrySkill(item SkillItem, targetDir string) (string, error) {
+func (m *InstallerModel) installRegistrySkill(item SkillItem, targetDir string) (*installOrigin, error) {
 	return m.installRegistrySkillWithRefresh(item, targetDir, false)
 }
 
 // installRegistrySkillWithRefresh installs/updates a registry skill
-func (m *InstallerModel) installRegistrySkillWithRefresh(item SkillItem, targetDir string, refresh bool) (string, error) {
+func (m *InstallerModel) installRegistrySkillWithRefresh(item SkillItem, targetDir string, refresh bool) (*installOrigin, error) {
 	reg, err := loadMergedRegistry()
 	if err != nil {
-		return "", fmt.Errorf("failed to load registry: %w", err)
+		return nil, fmt.Errorf("failed to load registry: %w", err)
 	}
 
 	matches := reg.FindSkillsWithConflict(item.Name)
 	if len(matches) == 0 {
-		return "", fmt.Errorf("skill not found in registry: %s", item.Name)
+		return nil, fmt.Errorf("skill not found in registry: %s", item.Name)
 	}
 
 	var skill *registry.Skill
@@ -469,13 +542,19 @@ func (m *InstallerModel) installRegistrySkillWithRefresh(item SkillItem, targetD
 	}
 
 	var result *gitutil.CloneResult
-	if source.SkipFetch && skill.Path != "" {
-		result, err = gitutil.SparseCloneRepo(source.GetGitURL(), source.Repo, source.Branch, []string{skill.Path})
+	ref := source.SkillRef(skill)
+	if source.UsesArchive() {
+		archiveRef := source.ArchiveRef(ref)
+		result, err = m.clones.FetchArchive(source.ArchiveURL(archiveRef), source.Repo, archiveRef, skill.FetchPaths(), refresh)
+	} else if ref != "" {
+		result, err = m.clones.CloneRepoAtRef(source.GetGitURL(), source.Repo, ref)
+	} else if source.SkipFetch && skill.Path != "" {
+		result, err = m.clones.SparseCloneRepo(source.GetGitURL(), source.Repo, source.Branch, []string{skill.Path})
 	} else {
-		result, err = gitutil.CloneRepoWithRefresh(source.GetGitURL(), source.Repo, source.Branch, refresh)
+		result, err = m.clones.CloneRepoWithRefresh(source.GetGitURL(), source.Repo, source.Branch, refresh)
 	}
 	if err != nil {
-		return "", fmt.Errorf("clone failed: %w", err)
+		return nil, fmt.Errorf("clone failed: %w", err)
 	}
 
 	var skillPath string
@@ -492,18 +571,43 @@ func (m *InstallerModel) installRegistrySkillWithRefresh(item SkillItem, targetD
 	}
 
 	if skillPath == "" || !dirExists(skillPath) {
-		return "", fmt.Errorf("skill path not found: %s", skill.Name)
+		return nil, fmt.Errorf("skill path not found: %s", skill.Name)
 	}
 
 	dstPath := filepath.Join(targetDir, skill.Name)
-	os.RemoveAll(dstPath)
-
-	if err := copyDir(skillPath, dstPath); err != nil {
-		os.RemoveAll(dstPath)
-		return "", fmt.Errorf("copy failed: %w", err)
+	// Never overwrite files edited since install; the CLI offers the
+	// keep/merge choices through update --on-conflict.
+	var baseline map[string]string
+	if meta, err := ReadSkillMeta(dstPath); err == nil && refresh {
+		baseline = meta.Files
+	}
+	var res *skillmerge.Result
+	err = m.tx.Install(dstPath, func(stageDir string) error {
+		var err error
+		res, err = skillmerge.Apply(dstPath, skillPath, stageDir, skillmerge.Options{Policy: skillmerge.Refuse, Baseline: baseline})
+		if err != nil {
+			return err
+		}
+		_, err = store.Share(stageDir, dstPath, store.DefaultMode())
+		return err
+	})
+	if errors.Is(err, skillmerge.ErrLocalChanges) {
+		return nil, fmt.Errorf(i18n.T("tui_err_local_changes"), len(res.Modified), skill.Name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("copy failed: %w", err)
 	}
 
-	return result.TempDir, nil
+	relPath, err := filepath.Rel(result.TempDir, skillPath)
+	if err != nil || relPath == "." {
+		relPath = ""
+	}
+	return &installOrigin{
+		cloneDir:  result.TempDir,
+		skillPath: filepath.ToSlash(relPath),
+		source:    source,
+		ref:       ref,
+	}, nil
 }
 
 // findSkillInRepo searches for a skill by name in common locations
@@ -556,77 +660,34 @@ func fileExists(path string) bool {
 	return !info.IsDir()
 }
 
-// copyDir copies a directory from source to destination
-func copyDir(srcPath string, dstPath string) error {
-	os.RemoveAll(dstPath)
-
-	return filepath.WalkDir(srcPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		relPath, err := filepath.Rel(srcPath, path)
-		if err != nil {
-			return err
-		}
-
-		targetPath := filepath.Join(dstPath, relPath)
-
-		if d.Type()&os.ModeSymlink != 0 {
-			realPath, err := filepath.EvalSymlinks(path)
-			if err != nil {
-				return nil
-			}
-			info, err := os.Stat(realPath)
-			if err != nil {
-				return nil
-			}
-			if info.IsDir() {
-				return copyDir(realPath, targetPath)
-			}
-			return copyFile(realPath, targetPath)
-		}
-
-		if d.IsDir() {
-			return os.MkdirAll(targetPath, 0755)
-		}
-
-		return copyFile(path, targetPath)
-	})
-}
-
-// copyFile copies a single file
-func copyFile(srcPath string, dstPath string) error {
-	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
-		return err
-	}
-
-	srcFile, err := os.Open(srcPath)
-	if err != nil {
-		return err
-	}
-	defer srcFile.Close()
-
-	dstFile, err := os.Create(dstPath)
-	if err != nil {
-		return err
-	}
-	defer dstFile.Close()
-
-	_, err = io.Copy(dstFile, srcFile)
-	return err
-}
-
-// RunInstaller runs the installer UI with three operation lists
-func RunInstaller(installSkills, uninstallSkills, updateSkills []SkillItem, targetDir string) (completed, failed int, err error) {
-	m := NewInstallerModel(installSkills, uninstallSkills, updateSkills, targetDir)
+// RunInstaller runs the installer UI with three operation lists, applying
+// each to every target
+func RunInstaller(installSkills, uninstallSkills, updateSkills []SkillItem, targets []products.Target) (completed, failed int, err error) {
+	m := NewInstallerModel(installSkills, uninstallSkills, updateSkills, targets)
 	p := tea.NewProgram(m)
 
 	finalModel, err := p.Run()
 	if err != nil {
+		_ = m.tx.Abort()
 		return 0, 0, err
 	}
 
+	// A batch cancelled before it finished is undone as a whole
 	result := finalModel.(InstallerModel)
+	if !result.IsFinished() {
+		_ = m.tx.Abort()
+	} else {
+		m.tx.Commit()
+	}
+
+	// Bring the converted rules of formats like Cursor's .mdc in line with
+	// whatever is installed now, after commit or rollback alike
+	for _, list := range [][]SkillItem{installSkills, uninstallSkills, updateSkills} {
+		for _, item := range list {
+			for _, t := range targets {
+				_ = adapter.Apply(t.Dir, item.Name)
+			}
+		}
+	}
 	return result.Completed(), result.Failed(), result.Error()
 }
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/products"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestOverallResult(t *testing.T) {
+	cases := []struct {
+		results []string
+		want    string
+	}{
+		{[]string{"", ""}, ""},
+		{[]string{"running", ""}, "running"},
+		{[]string{"ok", ""}, "running"},
+		{[]string{"ok", "ok"}, "ok"},
+		{[]string{"ok", "fail"}, "fail"},
+		{[]string{"fail", "running"}, "running"},
+		{[]string{"ok", "skip"}, "ok"},
+		{[]string{"skip", "skip"}, "skip"},
+	}
+	for _, c := range cases {
+		if got := overallResult(c.results); got != c.want {
+			t.Errorf("overallResult(%q) = %q; want %q", c.results, got, c.want)
+		}
+	}
+}
+
+func TestProductModel_MultiSelect(t *testing.T) {
+	m := NewProductModel("test", t.TempDir())
+	press := func(key string) {
+		var msg tea.KeyMsg
+		switch key {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "space":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+		}
+		next, _ := m.Update(msg)
+		m = next.(ProductModel)
+	}
+
+	// Nothing ticked: the product under the cursor
+	if got := m.SelectedProducts(); len(got) != 1 || got[0].Name != m.products[0].Name {
+		t.Fatalf("SelectedProducts() = %v; want the first product", got)
+	}
+
+	press("space")
+	press("down")
+	press("down")
+	press("space")
+	got := m.SelectedProducts()
+	if len(got) != 2 || got[0].Name != m.products[0].Name || got[1].Name != m.products[2].Name {
+		t.Fatalf("SelectedProducts() = %v; want the first and third products", got)
+	}
+
+	press("space")
+	if got := m.SelectedProducts(); len(got) != 1 {
+		t.Errorf("unticking left %d products selected; want 1", len(got))
+	}
+}
+
+func TestInstaller_SkipsProductsASkillIsNotMeantFor(t *testing.T) {
+	claude := products.GetProductByName("claude-code")
+	cursor := products.GetProductByName("cursor")
+	if claude == nil || cursor == nil {
+		t.Fatal("built-in products missing")
+	}
+	targets := []products.Target{
+		{Product: claude, Scope: products.ScopeProject, Dir: t.TempDir()},
+		{Product: cursor, Scope: products.ScopeProject, Dir: t.TempDir()},
+	}
+	skill := SkillItem{Name: "cursor-only", FullName: "a/cursor-only", Products: []string{"cursor"}}
+	m := NewInstallerModel([]SkillItem{skill}, nil, nil, targets)
+
+	// Job 0 is the Claude Code target: skipped without fetching anything
+	msg, ok := m.processCmd("install", 0)().(installProgressMsg)
+	if !ok || msg.result != "skip" || msg.skippedAdd != 1 || msg.failedAdd != 0 {
+		t.Fatalf("Claude Code job = %+v; want it skipped", msg)
+	}
+	if !skill.meantFor(targets[1]) {
+		t.Fatal("the Cursor target should be installed")
+	}
+}
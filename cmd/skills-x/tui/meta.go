@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/castle-x/skills-x/pkg/skilldiff"
 )
 
 const metaFileName = ".skills-x-meta.json"
@@ -15,14 +17,29 @@ type SkillMeta struct {
 	Source      string `json:"source"`
 	Repo        string `json:"repo"`
 	Commit      string `json:"commit"`
+	Ref         string `json:"ref,omitempty"`          // Pinned tag or commit; empty when tracking the branch tip
+	TreeHash    string `json:"tree_hash,omitempty"`    // Git tree hash of the skill directory at Commit
+	ResolvedRef string `json:"resolved_ref,omitempty"` // Ref the archive was downloaded at (archive transport only)
 	InstalledAt string `json:"installed_at"`
+	// Files maps each installed file to its SHA-256, so later updates can
+	// tell which files were edited locally
+	Files map[string]string `json:"files,omitempty"`
 }
 
-// WriteSkillMeta writes meta to .skills-x-meta.json inside the skill directory
+// WriteSkillMeta writes meta to .skills-x-meta.json inside the skill directory.
+// When meta.Files is nil the files currently in skillDir are hashed, which is
+// right straight after a plain install.
 func WriteSkillMeta(skillDir string, meta SkillMeta) error {
 	if meta.InstalledAt == "" {
 		meta.InstalledAt = time.Now().UTC().Format(time.RFC3339)
 	}
+	if meta.Files == nil {
+		files, err := skilldiff.HashFiles(skillDir)
+		if err != nil {
+			return err
+		}
+		meta.Files = files
+	}
 	data, err := json.MarshalIndent(meta, "", "  ")
 	if err != nil {
 		return err
@@ -42,3 +59,17 @@ func ReadSkillMeta(skillDir string) (*SkillMeta, error) {
 	}
 	return &meta, nil
 }
+
+// HasChanged reports whether the upstream skill differs from this installed
+// copy. When both tree hashes are known only the skill's own directory is
+// compared, so unrelated commits elsewhere in the repository are ignored;
+// otherwise it falls back to comparing repository commits.
+func (m *SkillMeta) HasChanged(remoteCommit string, remoteTreeHash string) bool {
+	if m == nil {
+		return true
+	}
+	if m.TreeHash != "" && remoteTreeHash != "" {
+		return m.TreeHash != remoteTreeHash
+	}
+	return m.Commit == "" || m.Commit != remoteCommit
+}
@@ -0,0 +1,28 @@
+package tui
+
+import "testing"
+
+func TestSkillMetaHasChanged(t *testing.T) {
+	tests := []struct {
+		name       string
+		meta       *SkillMeta
+		commit     string
+		treeHash   string
+		wantChange bool
+	}{
+		{"no meta", nil, "abc1234", "t1", true},
+		{"same tree, repo moved", &SkillMeta{Commit: "abc1234", TreeHash: "t1"}, "def5678", "t1", false},
+		{"tree changed", &SkillMeta{Commit: "abc1234", TreeHash: "t1"}, "def5678", "t2", true},
+		{"legacy meta, same commit", &SkillMeta{Commit: "abc1234"}, "abc1234", "t1", false},
+		{"legacy meta, repo moved", &SkillMeta{Commit: "abc1234"}, "def5678", "t1", true},
+		{"remote tree unknown", &SkillMeta{Commit: "abc1234", TreeHash: "t1"}, "abc1234", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.meta.HasChanged(tt.commit, tt.treeHash); got != tt.wantChange {
+				t.Fatalf("HasChanged = %v; want %v", got, tt.wantChange)
+			}
+		})
+	}
+}
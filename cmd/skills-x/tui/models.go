@@ -16,10 +16,12 @@ import (
 // Product Select Model - Level 1: Select AI Tool
 // ============================================================================
 
-// ProductModel for selecting AI tool
+// ProductModel for selecting AI tools; several can be ticked with Space
 type ProductModel struct {
 	products  []products.Product
 	cursor    int
+	selected  map[int]bool // ticked products by index
+	detected  []bool       // products that look in use on this machine
 	quitting  bool
 	goBack    bool
 	version   string
@@ -28,9 +30,16 @@ type ProductModel struct {
 
 // NewProductModel creates a new product selection model
 func NewProductModel(version string, targetDir string) ProductModel {
+	all := products.All()
+	detected := make([]bool, len(all))
+	for i := range all {
+		detected[i] = all[i].Detected(targetDir)
+	}
 	return ProductModel{
-		products:  products.AllProducts,
+		products:  all,
 		cursor:    0,
+		selected:  make(map[int]bool),
+		detected:  detected,
 		version:   version,
 		targetDir: targetDir,
 	}
@@ -79,13 +88,23 @@ func (m ProductModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.cursor = 0
 			}
-		case "enter", " ":
+		case " ":
+			m.selected[m.cursor] = !m.selected[m.cursor]
+		case "enter":
 			return m, tea.Quit
 		}
 	}
 	return m, nil
 }
 
+// checkbox renders the tick mark of a multi-select row
+func checkbox(ticked bool) string {
+	if ticked {
+		return successStyle.Render("[✓]") + " "
+	}
+	return hintStyle.Render("[ ]") + " "
+}
+
 func (m ProductModel) View() string {
 	if m.quitting {
 		return ""
@@ -98,7 +117,7 @@ func (m ProductModel) View() string {
 
 	// 2. 表头: AI Tool | Global | Project
 	headerName := padRight(i18n.T("tui_col_ai_tool"), 22)
-	b.WriteString(fmt.Sprintf("  %s  %s  %s\n",
+	b.WriteString(fmt.Sprintf("      %s  %s  %s\n",
 		titleStyle.Render(headerName),
 		titleStyle.Render(padRight(i18n.T("tui_global"), 4)),
 		titleStyle.Render(padRight(i18n.T("tui_project"), 4))))
@@ -108,6 +127,10 @@ func (m ProductModel) View() string {
 	for i, p := range m.products {
 		prefix := "  "
 		style := selectableStyle
+		// 未检测到的工具灰显
+		if !m.detected[i] {
+			style = hintStyle
+		}
 
 		if m.cursor == i {
 			prefix = cursorStyle.Render("❯ ")
@@ -134,28 +157,36 @@ func (m ProductModel) View() string {
 		globalStr := fmt.Sprintf("%4d", globalCount)
 		projectStr := fmt.Sprintf("%4d", projectCount)
 
-		b.WriteString(fmt.Sprintf("%s%s  %s  %s\n",
+		b.WriteString(fmt.Sprintf("%s%s%s  %s  %s\n",
 			prefix,
+			checkbox(m.selected[i]),
 			style.Render(name),
 			globalCountStyle.Render(globalStr),
 			projectCountStyle.Render(projectStr)))
 	}
 
 	// 4. 提示区域
-	b.WriteString(RenderHint(i18n.T("tui_hint_select")))
+	b.WriteString(RenderHint(i18n.T("tui_hint_multi_select")))
 
 	return b.String()
 }
 
-// SelectedProduct returns the selected product, nil if cancelled
-func (m ProductModel) SelectedProduct() *products.Product {
+// SelectedProducts returns the ticked products in list order, or the
+// product under the cursor when none is ticked; nil if cancelled
+func (m ProductModel) SelectedProducts() []products.Product {
 	if m.quitting {
 		return nil
 	}
-	if m.cursor >= 0 && m.cursor < len(m.products) {
-		return &m.products[m.cursor]
+	var picked []products.Product
+	for i, p := range m.products {
+		if m.selected[i] {
+			picked = append(picked, p)
+		}
 	}
-	return nil
+	if len(picked) == 0 && m.cursor >= 0 && m.cursor < len(m.products) {
+		picked = append(picked, m.products[m.cursor])
+	}
+	return picked
 }
 
 func (m ProductModel) IsQuitting() bool {
@@ -163,7 +194,7 @@ func (m ProductModel) IsQuitting() bool {
 }
 
 // RunProductSelect runs the product selection page
-func RunProductSelect(version string, targetDir string) (*products.Product, error) {
+func RunProductSelect(version string, targetDir string) ([]products.Product, error) {
 	m := NewProductModel(version, targetDir)
 	p := tea.NewProgram(m)
 
@@ -173,25 +204,26 @@ func RunProductSelect(version string, targetDir string) (*products.Product, erro
 	}
 
 	result := finalModel.(ProductModel)
-	return result.SelectedProduct(), nil
+	return result.SelectedProducts(), nil
 }
 
 // ============================================================================
 // Install Target Select Model - Level 3: Select Global or Project
 // ============================================================================
 
-// InstallTargetModel for selecting install target (global or project)
+// InstallTargetModel for selecting install targets (global, project or both)
 type InstallTargetModel struct {
-	product    *products.Product
+	products   []products.Product
 	projectDir string
 	cursor     int
+	selected   [2]bool // ticked scopes: global, project
 	quitting   bool
 }
 
 // NewInstallTargetModel creates a new install target selection model
-func NewInstallTargetModel(product *products.Product, projectDir string) InstallTargetModel {
+func NewInstallTargetModel(chosen []products.Product, projectDir string) InstallTargetModel {
 	return InstallTargetModel{
-		product:    product,
+		products:   chosen,
 		projectDir: projectDir,
 		cursor:     0,
 	}
@@ -220,7 +252,9 @@ func (m InstallTargetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.cursor = 0
 			}
-		case "enter", " ":
+		case " ":
+			m.selected[m.cursor] = !m.selected[m.cursor]
+		case "enter":
 			return m, tea.Quit
 		}
 	}
@@ -253,48 +287,59 @@ func (m InstallTargetModel) View() string {
 			style = selectedStyle
 		}
 
-		desc := ""
-		if i == 0 {
-			desc = hintStyle.Render("(" + m.product.GlobalPath() + ")")
-		} else {
-			// 项目路径 = 当前目录 + 工具的项目skills目录
-			projectSkillPath := filepath.Join(m.projectDir, m.product.ProjectSkills)
-			desc = hintStyle.Render("(" + projectSkillPath + ")")
+		// 每个选中工具在该范围下的目录（项目路径 = 当前目录 + 工具的项目skills目录）
+		scope := installScopes[i]
+		var dirs []string
+		for _, p := range m.products {
+			dirs = append(dirs, p.Dir(scope, m.projectDir))
 		}
+		desc := hintStyle.Render("(" + strings.Join(dirs, ", ") + ")")
 
-		b.WriteString(fmt.Sprintf("%s%s %s\n", prefix, style.Render(target), desc))
+		b.WriteString(fmt.Sprintf("%s%s%s %s\n", prefix, checkbox(m.selected[i]), style.Render(target), desc))
 	}
 
 	// 提示
-	b.WriteString(RenderHint(i18n.T("tui_hint_select")))
+	b.WriteString(RenderHint(i18n.T("tui_hint_multi_select")))
 
 	return b.String()
 }
 
-func (m InstallTargetModel) SelectedTarget() string {
+// installScopes are the rows of the install target page
+var installScopes = [2]string{products.ScopeGlobal, products.ScopeProject}
+
+// SelectedTargets returns the ticked scopes, or the scope under the cursor
+// when none is ticked; nil if cancelled
+func (m InstallTargetModel) SelectedTargets() []string {
 	if m.quitting {
-		return ""
+		return nil
 	}
-	if m.cursor == 0 {
-		return "global"
+	var scopes []string
+	for i, scope := range installScopes {
+		if m.selected[i] {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		scopes = append(scopes, installScopes[m.cursor])
 	}
-	return "project"
+	return scopes
 }
 
 func (m InstallTargetModel) IsQuitting() bool {
 	return m.quitting
 }
 
-// RunInstallTargetSelect runs the install target selection page
-func RunInstallTargetSelect(product *products.Product, projectDir string) (string, error) {
-	m := NewInstallTargetModel(product, projectDir)
+// RunInstallTargetSelect runs the install target selection page and returns
+// the chosen scopes
+func RunInstallTargetSelect(chosen []products.Product, projectDir string) ([]string, error) {
+	m := NewInstallTargetModel(chosen, projectDir)
 	p := tea.NewProgram(m)
 
 	finalModel, err := p.Run()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	result := finalModel.(InstallTargetModel)
-	return result.SelectedTarget(), nil
+	return result.SelectedTargets(), nil
 }
@@ -0,0 +1,77 @@
+// Package tui provides terminal interactive UI components
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// newSkillsFilePath returns the path to the file listing skills added by
+// "registry update" that the user has not seen yet.
+func newSkillsFilePath() string {
+	return filepath.Join(filepath.Dir(starredFilePath()), "new-skills.json")
+}
+
+// LoadNewSkills reads the set of unseen new skills (keyed by FullName).
+// Returns an empty map if the file does not exist or cannot be parsed.
+func LoadNewSkills() map[string]bool {
+	data, err := os.ReadFile(newSkillsFilePath())
+	if err != nil {
+		return map[string]bool{}
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return map[string]bool{}
+	}
+	set := make(map[string]bool, len(list))
+	for _, name := range list {
+		set[name] = true
+	}
+	return set
+}
+
+// AddNewSkills marks skills as new until the TUI has shown them.
+func AddNewSkills(fullNames []string) error {
+	if len(fullNames) == 0 {
+		return nil
+	}
+	set := LoadNewSkills()
+	for _, name := range fullNames {
+		set[name] = true
+	}
+	return saveNewSkills(set)
+}
+
+// MarkSkillsSeen drops skills from the new set.
+func MarkSkillsSeen(fullNames map[string]bool) error {
+	if len(fullNames) == 0 {
+		return nil
+	}
+	set := LoadNewSkills()
+	for name := range fullNames {
+		delete(set, name)
+	}
+	return saveNewSkills(set)
+}
+
+// saveNewSkills writes the new set to disk as a sorted JSON array.
+func saveNewSkills(set map[string]bool) error {
+	list := make([]string, 0, len(set))
+	for name := range set {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+
+	path := newSkillsFilePath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
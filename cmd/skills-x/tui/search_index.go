@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"sync"
+
+	"github.com/castle-x/skills-x/pkg/search"
+)
+
+// searchResultLimit caps the full-text hits added to the list per query
+const searchResultLimit = 50
+
+var (
+	searchIndexOnce sync.Once
+	searchIndex     *search.Index
+)
+
+// openSearchIndex returns the full-text index for "/" search; a variable so
+// tests can replace it
+var openSearchIndex = loadSearchIndex
+
+// loadSearchIndex opens the index shared with "skills-x search" once per
+// session, refreshing it from the git cache. It returns nil when the
+// registry cannot be loaded.
+func loadSearchIndex() *search.Index {
+	searchIndexOnce.Do(func() {
+		reg, err := loadMergedRegistry()
+		if err != nil {
+			return
+		}
+		// A failed save only costs a rebuild next time
+		searchIndex, _ = search.Open(reg)
+	})
+	return searchIndex
+}
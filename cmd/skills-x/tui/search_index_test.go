@@ -0,0 +1,47 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/search"
+)
+
+func TestFilterSkillsAddsContentMatches(t *testing.T) {
+	orig := openSearchIndex
+	defer func() { openSearchIndex = orig }()
+	ix := search.New([]search.Document{
+		{ID: "x/pocketbase-go", Name: "pocketbase-go", Content: "Register record hooks in Go."},
+		{ID: "x/hooks-guide", Name: "hooks-guide", Content: "Guide to hooks."},
+		{ID: "x/unlisted", Name: "unlisted", Content: "hooks everywhere"},
+	})
+	openSearchIndex = func() *search.Index { return ix }
+
+	m := NewSkillsModel(nil, []SkillItem{
+		{Name: "hooks-guide", FullName: "x/hooks-guide"},
+		{Name: "pocketbase-go", FullName: "x/pocketbase-go"},
+		{Name: "pdf", FullName: "x/pdf"},
+	}, "dev", "")
+	m.search = "hooks"
+	m.filterSkills()
+
+	var names []string
+	for _, s := range m.filtered {
+		names = append(names, s.Name)
+	}
+	if strings.Join(names, ",") != "hooks-guide,pocketbase-go" {
+		t.Fatalf("filtered = %v; want the name match, then the content match", names)
+	}
+	if snippet := m.snippets["x/pocketbase-go"]; !strings.Contains(snippet, "record") {
+		t.Errorf("snippet = %q", snippet)
+	}
+	if _, ok := m.snippets["x/hooks-guide"]; ok {
+		t.Error("name matches keep their description")
+	}
+
+	m.search = "#backend"
+	m.filterSkills()
+	if m.snippets != nil {
+		t.Error("tag filters should not show snippets")
+	}
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/castle-x/skills-x/pkg/fuzzy"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// nameScoreWeight makes a match in a skill's name outrank an equally good
+// one in its description
+const nameScoreWeight = 2
+
+// searchMatch records where a query matched a skill, for highlighting
+type searchMatch struct {
+	name        []int  // Matched rune positions in FullName
+	description string // The description that matched, in either language
+	descMatch   []int  // Matched rune positions in description
+}
+
+// rankSkills returns the skills that fuzzy-match query, best first. The
+// name, repository and both language descriptions are searched, whatever
+// the UI language; Chinese text also matches its pinyin and initials. Ties
+// keep the order of skills.
+func rankSkills(skills []SkillItem, query string) ([]SkillItem, map[string]searchMatch) {
+	type ranked struct {
+		item  SkillItem
+		score int
+	}
+	var hits []ranked
+	matches := make(map[string]searchMatch)
+	for _, s := range skills {
+		var sm searchMatch
+		best, found := 0, false
+		if m, ok := fuzzy.Find(query, s.FullName); ok {
+			best, found = m.Score*nameScoreWeight, true
+			sm.name = m.Positions
+		}
+		if m, ok := fuzzy.Find(query, s.Source); ok && (!found || m.Score > best) {
+			best, found = m.Score, true
+		}
+		for _, desc := range []string{s.Description, s.AltDescription} {
+			if m, ok := fuzzy.Find(query, desc); ok {
+				if !found || m.Score > best {
+					best, found = m.Score, true
+				}
+				if sm.description == "" {
+					sm.description, sm.descMatch = desc, m.Positions
+				}
+			}
+		}
+		if found {
+			hits = append(hits, ranked{s, best})
+			matches[s.FullName] = sm
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+
+	filtered := make([]SkillItem, 0, len(hits))
+	for _, h := range hits {
+		filtered = append(filtered, h.item)
+	}
+	return filtered, matches
+}
+
+// highlightRunes renders text with base, and the runes at positions with
+// mark on top of it
+func highlightRunes(text string, positions []int, base, mark lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(text)
+	}
+	marked := make(map[int]bool, len(positions))
+	for _, p := range positions {
+		marked[p] = true
+	}
+	markStyle := mark.Inherit(base)
+	var b strings.Builder
+	var run []rune
+	runMarked := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMarked {
+			b.WriteString(markStyle.Render(string(run)))
+		} else {
+			b.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		if marked[i] != runMarked {
+			flush()
+			runMarked = marked[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
@@ -13,12 +13,20 @@ import (
 // LoadSkillsFromRegistry loads skills from registry and checks installed status
 // targetDir: the directory to check for installation status
 func LoadSkillsFromRegistry(targetDir string) ([]SkillItem, error) {
+	return loadSkills(targetDir, "")
+}
+
+// loadSkills loads the registry skills that fit product ("" = any). Skills
+// meant for other products or needing a newer skills-x are left out unless
+// they are already installed, so they can still be updated or removed.
+func loadSkills(targetDir string, product string) ([]SkillItem, error) {
 	reg, err := loadMergedRegistry()
 	if err != nil {
 		return nil, err
 	}
 
 	starredSet := LoadStarred()
+	newSet := LoadNewSkills()
 
 	var skills []SkillItem
 
@@ -29,18 +37,34 @@ func LoadSkillsFromRegistry(targetDir string) ([]SkillItem, error) {
 
 			skillDir := filepath.Join(targetDir, skill.Name)
 			installed := targetDir != "" && isSkillDir(skillDir)
+			if !installed && (!skill.SupportsProduct(product) || !skill.IsCompatible()) {
+				continue
+			}
 
 			description := skill.GetDescription(i18n.GetLanguage())
+			altDescription := skill.DescriptionZh
+			if altDescription == description {
+				altDescription = skill.Description
+			}
+			if altDescription == description {
+				altDescription = ""
+			}
 
 			item := SkillItem{
-				Name:        skill.Name,
-				FullName:    fullName,
-				Source:      source.Repo,
-				SourceName:  source.Name,
-				Description: description,
-				Tags:        skill.Tags,
-				Installed:   installed,
-				Starred:     starredSet[fullName],
+				Name:           skill.Name,
+				FullName:       fullName,
+				Source:         source.Repo,
+				SourceName:     source.Name,
+				Origin:         source.Origin,
+				Description:    description,
+				AltDescription: altDescription,
+				Tags:           skill.Tags,
+				Installed:      installed,
+				Starred:        starredSet[fullName],
+				New:            newSet[fullName],
+				Deprecated:     skill.Deprecated,
+				ReplacedBy:     skill.ReplacedBy,
+				Products:       skill.Products,
 			}
 			if installed {
 				item.Meta, _ = ReadSkillMeta(skillDir)
@@ -101,7 +125,7 @@ func SortSkills(skills []SkillItem) {
 	})
 }
 
-// FilterSkills filters skills based on search query
+// FilterSkills filters skills based on search query, best matches first
 // Supports #tag prefix for tag-based filtering
 func FilterSkills(skills []SkillItem, query string) []SkillItem {
 	if query == "" {
@@ -125,15 +149,7 @@ func FilterSkills(skills []SkillItem, query string) []SkillItem {
 			}
 		}
 	} else {
-		queryLower := strings.ToLower(query)
-		for _, s := range skills {
-			if strings.Contains(strings.ToLower(s.FullName), queryLower) ||
-				strings.Contains(strings.ToLower(s.SourceName), queryLower) ||
-				strings.Contains(strings.ToLower(s.Name), queryLower) ||
-				strings.Contains(strings.ToLower(s.Description), queryLower) {
-				filtered = append(filtered, s)
-			}
-		}
+		filtered, _ = rankSkills(skills, query)
 	}
 
 	return filtered
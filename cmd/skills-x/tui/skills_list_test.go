@@ -91,6 +91,53 @@ func TestFilterSkills(t *testing.T) {
 	}
 }
 
+func TestFilterSkillsFuzzyRanking(t *testing.T) {
+	skills := []SkillItem{
+		{FullName: "anthropic/algorithmic-art", Description: "Creating algorithmic art"},
+		{FullName: "anthropic/frontend-design", Description: "Frontend design best practices"},
+		{FullName: "vercel/web-guidelines", Description: "Review UI code", AltDescription: "审查前端界面代码"},
+	}
+
+	// A typo still finds the skill
+	result := FilterSkills(skills, "frntend")
+	if len(result) != 1 || result[0].FullName != "anthropic/frontend-design" {
+		t.Fatalf("frntend: got %v, want frontend-design", fullNames(result))
+	}
+
+	// Pinyin initials match the Chinese description whatever the language
+	result = FilterSkills(skills, "qd")
+	if len(result) != 1 || result[0].FullName != "vercel/web-guidelines" {
+		t.Errorf("qd: got %v, want vercel/web-guidelines", fullNames(result))
+	}
+	result = FilterSkills(skills, "前端")
+	if len(result) != 1 {
+		t.Errorf("前端: got %v", fullNames(result))
+	}
+}
+
+func TestRankSkillsHighlights(t *testing.T) {
+	skills := []SkillItem{
+		{FullName: "x/pdf", Description: "Edit PDF forms", AltDescription: "编辑 PDF 表单"},
+	}
+	_, matches := rankSkills(skills, "bianji")
+	sm := matches["x/pdf"]
+	if sm.description != "编辑 PDF 表单" || len(sm.descMatch) != 2 || sm.descMatch[0] != 0 {
+		t.Errorf("match = %+v, want 编辑 highlighted in the Chinese description", sm)
+	}
+	_, matches = rankSkills(skills, "pdf")
+	if got := matches["x/pdf"].name; len(got) != 3 || got[0] != 2 {
+		t.Errorf("name positions = %v, want [2 3 4]", got)
+	}
+}
+
+func fullNames(skills []SkillItem) []string {
+	var names []string
+	for _, s := range skills {
+		names = append(names, s.FullName)
+	}
+	return names
+}
+
 func TestGetSkillByFullName(t *testing.T) {
 	skills := []SkillItem{
 		{FullName: "anthropic/frontend-design", Name: "frontend-design"},
@@ -127,3 +174,25 @@ func TestCheckSkillInstalled(t *testing.T) {
 		t.Error("Expected false for empty target dir")
 	}
 }
+
+func TestNewSkillsSeenOnFocus(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	if err := AddNewSkills([]string{"team:team/a", "team:team/b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	skills := []SkillItem{
+		{FullName: "team:team/a", New: true},
+		{FullName: "team:team/b", New: true},
+	}
+	m := NewSkillsModel(nil, skills, "", "")
+	m.noteSeen()
+	if err := MarkSkillsSeen(m.seen); err != nil {
+		t.Fatal(err)
+	}
+
+	left := LoadNewSkills()
+	if left["team:team/a"] || !left["team:team/b"] {
+		t.Errorf("new skills after focusing the first = %v; want only team:team/b", left)
+	}
+}
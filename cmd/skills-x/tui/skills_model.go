@@ -24,6 +24,7 @@ type repoUpdateCache struct {
 
 type repoCacheEntry struct {
 	headCommit string
+	cloneDir   string // local checkout, used to hash individual skill trees
 	checkedAt  time.Time
 	err        error
 }
@@ -77,18 +78,24 @@ const (
 
 // SkillItem represents a skill in the list
 type SkillItem struct {
-	Name        string
-	FullName    string // "source/skill-name"
-	Source      string
-	SourceName  string
-	Description string
-	Tags        []string    // tags for filtering (e.g., featured, web-frontend)
-	Installed   bool        // installed in target directory
-	Action      SkillAction // intended operation
-	Meta        *SkillMeta  // nil if not installed or no meta
-	Checking    bool        // true while u-key check is in progress
-	HasUpdate   *bool       // nil=unknown, true=has update, false=no update
-	Starred     bool        // persisted in ~/.config/skills-x/starred.json
+	Name           string
+	FullName       string // "source/skill-name"
+	Source         string
+	SourceName     string
+	Origin         string // registry the source came from (see registry.Source.Origin)
+	Description    string
+	AltDescription string      // description in the other language, searched too
+	Tags           []string    // tags for filtering (e.g., featured, web-frontend)
+	Installed      bool        // installed in target directory
+	Action         SkillAction // intended operation
+	Meta           *SkillMeta  // nil if not installed or no meta
+	Checking       bool        // true while u-key check is in progress
+	HasUpdate      *bool       // nil=unknown, true=has update, false=no update
+	Starred        bool        // persisted in ~/.config/skills-x/starred.json
+	New            bool        // added by the last registry update and not seen yet
+	Deprecated     bool        // registry marks it deprecated
+	ReplacedBy     string      // suggested replacement for a deprecated skill
+	Products       []string    // products the skill is meant for; empty means any
 }
 
 // checkUpdateResultMsg is returned by the async update check command
@@ -100,6 +107,16 @@ type checkUpdateResultMsg struct {
 	err           error
 }
 
+// diffResultMsg carries the result of loading a skill diff
+type diffResultMsg struct {
+	skillFullName string
+	diff          *SkillDiff
+	err           error
+}
+
+// diffPageSize is the number of diff lines shown at once in the diff view
+const diffPageSize = 20
+
 // tagAliases maps Chinese search terms to English tag identifiers
 var tagAliases = map[string]string{
 	"常用":     "featured",
@@ -117,14 +134,15 @@ var tagAliases = map[string]string{
 	"skills": "skills-meta",
 }
 
-// allTagNames lists English tag names for direct match
-var allTagNames = map[string]bool{
-	"starred": true, "featured": true, "ai-efficiency": true, "planning": true,
-	"web-frontend": true, "mobile": true, "backend": true,
-	"testing": true, "code-review": true, "office": true,
-	"design": true, "writing": true, "media": true,
-	"skills-meta": true,
-}
+// allTagNames lists English tag names for direct match: the virtual
+// "starred" tag plus the registry tags (registry lint warns about others)
+var allTagNames = func() map[string]bool {
+	names := map[string]bool{"starred": true}
+	for _, tag := range registry.KnownTags {
+		names[tag] = true
+	}
+	return names
+}()
 
 // getTagPickerList returns the ordered tag list with i18n labels at call time.
 // values are English tag names matching allTagNames for direct filterSkills resolution.
@@ -154,6 +172,7 @@ func getTagPickerList() []struct{ label, value string } {
 // SkillsModel for selecting skills with search and multi-select
 type SkillsModel struct {
 	product        *products.Product
+	targets        []products.Target // every target the selection goes to; product and targetDir are the first
 	allSkills      []SkillItem
 	filtered       []SkillItem
 	cursor         int
@@ -171,6 +190,12 @@ type SkillsModel struct {
 	selectAllState int    // 0=none, 1=install/update, 2=none/uninstall
 	updateCache    *repoUpdateCache // session-level cache for repo update checks
 	spinnerFrame   int  // current animation frame index for checking indicator
+	diffView       []string // rendered diff lines; non-nil while the diff view is open
+	diffOffset     int      // first visible line of the diff view
+	diffSkill      string   // FullName of the skill shown in the diff view
+	seen           map[string]bool // new skills the cursor has rested on
+	snippets       map[string]string // full-text search snippets by FullName
+	matches        map[string]searchMatch // fuzzy search matches by FullName
 }
 
 // NewSkillsModel creates a new skills selection model
@@ -189,6 +214,7 @@ func NewSkillsModel(product *products.Product, allSkills []SkillItem, version st
 		pageSize:    10,
 		targetDir:   targetDir,
 		updateCache: newRepoUpdateCache(),
+		seen:        make(map[string]bool),
 	}
 }
 
@@ -200,6 +226,8 @@ func (m SkillsModel) Init() tea.Cmd {
 // Supports #tag prefix for tag-based filtering (e.g., #前端, #featured)
 func (m *SkillsModel) filterSkills() {
 	query := strings.TrimSpace(m.search)
+	m.snippets = nil
+	m.matches = nil
 
 	if query == "" {
 		m.filtered = m.allSkills
@@ -231,15 +259,12 @@ func (m *SkillsModel) filterSkills() {
 			}
 		}
 	} else {
-		queryLower := strings.ToLower(query)
-		m.filtered = make([]SkillItem, 0)
-		for _, s := range m.allSkills {
-			if strings.Contains(strings.ToLower(s.FullName), queryLower) ||
-				strings.Contains(strings.ToLower(s.Source), queryLower) ||
-				strings.Contains(strings.ToLower(s.Name), queryLower) {
-				m.filtered = append(m.filtered, s)
-			}
+		m.filtered, m.matches = rankSkills(m.allSkills, query)
+		matched := make(map[string]bool, len(m.filtered))
+		for _, s := range m.filtered {
+			matched[s.FullName] = true
 		}
+		m.addContentMatches(query, matched)
 	}
 
 	if m.cursor >= len(m.filtered) {
@@ -251,6 +276,34 @@ func (m *SkillsModel) filterSkills() {
 	m.offset = 0
 }
 
+// addContentMatches appends the skills whose SKILL.md or reference files
+// match query, best first, after the name and description matches. Their snippets replace
+// the description line while the cursor is on them.
+func (m *SkillsModel) addContentMatches(query string, matched map[string]bool) {
+	ix := openSearchIndex()
+	if ix == nil {
+		return
+	}
+	byName := make(map[string]SkillItem, len(m.allSkills))
+	for _, s := range m.allSkills {
+		byName[s.FullName] = s
+	}
+	m.snippets = make(map[string]string)
+	for _, r := range ix.Search(query, searchResultLimit) {
+		s, ok := byName[r.Doc.ID]
+		if !ok || matched[r.Doc.ID] {
+			continue
+		}
+		matched[r.Doc.ID] = true
+		m.filtered = append(m.filtered, s)
+		if r.Snippet != "" {
+			m.snippets[r.Doc.ID] = r.HighlightSnippet(func(word string) string {
+				return warningStyle.Render(word)
+			})
+		}
+	}
+}
+
 // syncToAllSkills syncs a filtered item's Action back to allSkills
 func (m *SkillsModel) syncToAllSkills(fullName string, action SkillAction) {
 	for i := range m.allSkills {
@@ -417,9 +470,16 @@ func checkUpdateForSkill(item SkillItem, targetDir string, cache *repoUpdateCach
 			source = matches[0].Source
 		}
 
+		// Archive sources only download the skill's own path, so they are
+		// cached per skill rather than per repository
+		cacheKey := source.Repo
+		if source.UsesArchive() {
+			cacheKey += "#" + skill.Path
+		}
+
 		// Check session cache (unless force refresh)
 		if !forceRefresh && cache != nil {
-			if entry, ok := cache.get(source.Repo); ok {
+			if entry, ok := cache.get(cacheKey); ok {
 				if entry.err != nil {
 					return checkUpdateResultMsg{
 						skillFullName: item.FullName,
@@ -430,7 +490,7 @@ func checkUpdateForSkill(item SkillItem, targetDir string, cache *repoUpdateCach
 				if meta != nil {
 					localCommit = meta.Commit
 				}
-				hasUpdate := localCommit == "" || localCommit != entry.headCommit
+				hasUpdate := meta.HasChanged(entry.headCommit, skillTreeHash(entry.cloneDir, skill))
 				return checkUpdateResultMsg{
 					skillFullName: item.FullName,
 					hasUpdate:     hasUpdate,
@@ -442,11 +502,14 @@ func checkUpdateForSkill(item SkillItem, targetDir string, cache *repoUpdateCach
 
 		// Invalidate cache entry when force refreshing
 		if forceRefresh && cache != nil {
-			cache.invalidate(source.Repo)
+			cache.invalidate(cacheKey)
 		}
 
 		var result *gitutil.CloneResult
-			if source.SkipFetch && skill.Path != "" {
+			if source.UsesArchive() {
+				ref := source.ArchiveRef("")
+				result, err = gitutil.FetchArchive(source.ArchiveURL(ref), source.Repo, ref, skill.FetchPaths(), true)
+			} else if source.SkipFetch && skill.Path != "" {
 				result, err = gitutil.SparseCloneRepo(source.GetGitURL(), source.Repo, source.Branch, []string{skill.Path})
 			} else {
 				// Always refresh on explicit update checks to avoid stale cache false negatives.
@@ -455,7 +518,7 @@ func checkUpdateForSkill(item SkillItem, targetDir string, cache *repoUpdateCach
 		if err != nil {
 			// Cache the error so subsequent checks for the same repo don't retry
 			if cache != nil {
-				cache.set(source.Repo, &repoCacheEntry{
+				cache.set(cacheKey, &repoCacheEntry{
 					err:       fmt.Errorf(i18n.T("tui_err_fetch_repo"), err),
 					checkedAt: time.Now(),
 				})
@@ -476,8 +539,9 @@ func checkUpdateForSkill(item SkillItem, targetDir string, cache *repoUpdateCach
 
 		// Cache the successful result
 		if cache != nil {
-			cache.set(source.Repo, &repoCacheEntry{
+			cache.set(cacheKey, &repoCacheEntry{
 				headCommit: remoteCommit,
+				cloneDir:   result.TempDir,
 				checkedAt:  time.Now(),
 			})
 		}
@@ -487,7 +551,7 @@ func checkUpdateForSkill(item SkillItem, targetDir string, cache *repoUpdateCach
 			localCommit = meta.Commit
 		}
 
-		hasUpdate := localCommit == "" || localCommit != remoteCommit
+		hasUpdate := meta.HasChanged(remoteCommit, skillTreeHash(result.TempDir, skill))
 
 		return checkUpdateResultMsg{
 			skillFullName: item.FullName,
@@ -498,7 +562,83 @@ func checkUpdateForSkill(item SkillItem, targetDir string, cache *repoUpdateCach
 	}
 }
 
+// skillTreeHash returns the tree hash of a registry skill inside a checkout,
+// or "" when the skill cannot be located there.
+func skillTreeHash(cloneDir string, skill *registry.Skill) string {
+	if cloneDir == "" {
+		return ""
+	}
+	relPath := skill.Path
+	if relPath == "" {
+		discovered, _ := findSkillInRepo(cloneDir, skill.Name)
+		if discovered == nil {
+			return ""
+		}
+		rel, err := filepath.Rel(cloneDir, discovered.Path)
+		if err != nil {
+			return ""
+		}
+		relPath = rel
+	}
+	hash, _ := gitutil.GetTreeHash(cloneDir, relPath)
+	return hash
+}
+
+// loadDiffForSkill creates a command that compares an installed skill with upstream
+func loadDiffForSkill(item SkillItem, targetDir string) tea.Cmd {
+	return func() tea.Msg {
+		d, err := LoadSkillDiff(targetDir, item.Name)
+		return diffResultMsg{skillFullName: item.FullName, diff: d, err: err}
+	}
+}
+
+// updateDiffView handles keys while the diff view is open
+func (m SkillsModel) updateDiffView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	maxOffset := len(m.diffView) - diffPageSize
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		m.quitting = true
+		return m, tea.Quit
+	case "up", "k":
+		if m.diffOffset > 0 {
+			m.diffOffset--
+		}
+	case "down", "j":
+		if m.diffOffset < maxOffset {
+			m.diffOffset++
+		}
+	case "pgup":
+		m.diffOffset = max(m.diffOffset-diffPageSize, 0)
+	case "pgdown", " ":
+		m.diffOffset = min(m.diffOffset+diffPageSize, maxOffset)
+	case "home":
+		m.diffOffset = 0
+	case "end":
+		m.diffOffset = maxOffset
+	case "u", "enter":
+		// Mark the skill for update and return to the list
+		for i := range m.allSkills {
+			if m.allSkills[i].FullName == m.diffSkill && m.allSkills[i].Installed {
+				m.allSkills[i].Action = ActionUpdate
+				break
+			}
+		}
+		m.syncFilteredFromAll()
+		m.diffView = nil
+	case "esc", "q", "b", "d":
+		m.diffView = nil
+	}
+	return m, nil
+}
+
 func (m SkillsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// The focused skill has been on screen since the last render
+	m.noteSeen()
+
 	switch msg := msg.(type) {
 	case spinnerTickMsg:
 		// Advance spinner frame if any skill is still checking
@@ -540,9 +680,30 @@ func (m SkillsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.syncFilteredFromAll()
 		return m, nil
 
+	case diffResultMsg:
+		for i := range m.allSkills {
+			if m.allSkills[i].FullName == msg.skillFullName {
+				m.allSkills[i].Checking = false
+				break
+			}
+		}
+		m.syncFilteredFromAll()
+		if msg.err != nil {
+			m.errMsg = i18n.Tf("tui_diff_failed", msg.err)
+			return m, nil
+		}
+		m.diffView = renderDiffLines(msg.diff)
+		m.diffOffset = 0
+		m.diffSkill = msg.skillFullName
+		return m, nil
+
 	case tea.KeyMsg:
 		m.errMsg = ""
 
+		if m.diffView != nil {
+			return m.updateDiffView(msg)
+		}
+
 		// Tag picker mode: intercept all navigation before the main switch
 		if m.tagPicking {
 			tagList := getTagPickerList()
@@ -671,7 +832,7 @@ func (m SkillsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						break
 					}
 				}
-				return m, tea.Batch(checkUpdateForSkill(*item, m.targetDir, m.updateCache, false), spinnerTick())
+				return m, tea.Batch(checkUpdateForSkill(*item, m.dirOf(*item), m.updateCache, false), spinnerTick())
 			}
 		case "R": // force-refresh update check (bypasses session cache)
 			if m.searching {
@@ -694,7 +855,28 @@ func (m SkillsModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						break
 					}
 				}
-				return m, tea.Batch(checkUpdateForSkill(*item, m.targetDir, m.updateCache, true), spinnerTick())
+				return m, tea.Batch(checkUpdateForSkill(*item, m.dirOf(*item), m.updateCache, true), spinnerTick())
+			}
+		case "d":
+			if m.searching {
+				m.search += "d"
+				m.filterSkills()
+				return m, nil
+			}
+			if m.cursor >= 0 && m.cursor < len(m.filtered) {
+				item := &m.filtered[m.cursor]
+				if !item.Installed {
+					m.errMsg = i18n.T("tui_only_installed_check")
+					return m, nil
+				}
+				item.Checking = true
+				for i := range m.allSkills {
+					if m.allSkills[i].FullName == item.FullName {
+						m.allSkills[i].Checking = true
+						break
+					}
+				}
+				return m, tea.Batch(loadDiffForSkill(*item, m.dirOf(*item)), spinnerTick())
 			}
 		case "f":
 			if m.searching {
@@ -773,10 +955,19 @@ func (m SkillsModel) View() string {
 	// 1. Logo
 	b.WriteString(RenderLogo(m.version))
 
+	if m.diffView != nil {
+		b.WriteString(m.renderDiffView())
+		return b.String()
+	}
+
 	// 2. Title + path on one line
-	titleLine := titleStyle.Render(i18n.T("tui_skills_for") + " " + m.product.Name)
+	titleLine := titleStyle.Render(i18n.T("tui_skills_for") + " " + m.productNames())
 	if m.targetDir != "" {
-		titleLine += "  " + hintStyle.Render(m.targetDir)
+		path := m.targetDir
+		if len(m.targets) > 1 {
+			path += " " + i18n.Tf("tui_more_targets", len(m.targets)-1)
+		}
+		titleLine += "  " + hintStyle.Render(path)
 	}
 	b.WriteString(titleLine)
 	b.WriteString("\n")
@@ -890,6 +1081,10 @@ func (m SkillsModel) View() string {
 			displayName = displayName[:32] + "..."
 		}
 		displayName = padRight(displayName, 35)
+		renderedName := nameStyle.Render(displayName)
+		if match, ok := m.matches[s.FullName]; ok && len(match.name) > 0 {
+			renderedName = highlightRunes(displayName, match.name, nameStyle, matchStyle)
+		}
 
 		// Installation date from meta
 		dateStr := ""
@@ -911,7 +1106,16 @@ func (m SkillsModel) View() string {
 			starHint = " " + warningStyle.Render("★")
 		}
 
-		b.WriteString(fmt.Sprintf("%s%s %s%s%s%s\n", prefix, marker, nameStyle.Render(displayName), dateStr, updateHint, starHint))
+		// New since the last registry update
+		newHint := ""
+		if s.New {
+			newHint = " " + successStyle.Render(i18n.T("tui_new_badge"))
+		}
+		if s.Deprecated {
+			newHint += " " + warningStyle.Render(i18n.T("tui_deprecated_badge"))
+		}
+
+		b.WriteString(fmt.Sprintf("%s%s %s%s%s%s%s\n", prefix, marker, renderedName, dateStr, updateHint, starHint, newHint))
 	}
 
 	// Padding for stable layout
@@ -959,9 +1163,20 @@ func (m SkillsModel) View() string {
 			b.WriteString(cursorStyle.Render("⚠ " + m.errMsg))
 		}
 	} else if m.cursor >= 0 && m.cursor < len(m.filtered) {
-		desc := m.filtered[m.cursor].Description
-		if desc != "" {
-			b.WriteString(RenderDescriptionGradient(desc))
+		item := m.filtered[m.cursor]
+		// Provenance for skills that do not come from the official registry
+		if item.Origin != "" && item.Origin != registry.OfficialRemote {
+			b.WriteString(hintStyle.Render("[" + item.Origin + "] "))
+		}
+		if item.Deprecated && item.ReplacedBy != "" {
+			b.WriteString(warningStyle.Render(i18n.Tf("tui_replaced_by", item.ReplacedBy) + " "))
+		}
+		if snippet, ok := m.snippets[item.FullName]; ok {
+			b.WriteString(snippet)
+		} else if match, ok := m.matches[item.FullName]; ok && match.description != "" {
+			b.WriteString(highlightRunes(match.description, match.descMatch, descriptionStyle, matchStyle))
+		} else if item.Description != "" {
+			b.WriteString(RenderDescriptionGradient(item.Description))
 		}
 	}
 
@@ -1010,6 +1225,14 @@ func (m SkillsModel) UpdateSkills() []SkillItem {
 	return result
 }
 
+// noteSeen records the focused skill as seen if it carries the "new" badge.
+// The badge stays for the rest of the session and is dropped on exit.
+func (m SkillsModel) noteSeen() {
+	if m.cursor >= 0 && m.cursor < len(m.filtered) && m.filtered[m.cursor].New {
+		m.seen[m.filtered[m.cursor].FullName] = true
+	}
+}
+
 func (m SkillsModel) IsQuitting() bool {
 	return m.quitting
 }
@@ -1018,12 +1241,41 @@ func (m SkillsModel) IsGoBack() bool {
 	return m.goBack
 }
 
-// RunSkillsSelect runs the skills selection page, returns three action lists
-func RunSkillsSelect(product *products.Product, allSkills []SkillItem, version string, targetDir string) (install, uninstall, update []SkillItem, err error) {
+// dirOf returns the first target directory holding the installed skill,
+// which update checks and diffs compare against
+func (m SkillsModel) dirOf(item SkillItem) string {
+	for _, t := range m.targets {
+		if CheckSkillInstalled(item.Name, t.Dir) {
+			return t.Dir
+		}
+	}
+	return m.targetDir
+}
+
+// productNames lists the products of the selection, each once
+func (m SkillsModel) productNames() string {
+	if len(m.targets) == 0 {
+		return m.product.Name
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for _, t := range m.targets {
+		if !seen[t.Product.Name] {
+			seen[t.Product.Name] = true
+			names = append(names, t.Product.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// RunSkillsSelect runs the skills selection page for one or more targets,
+// returns three action lists
+func RunSkillsSelect(targets []products.Target, allSkills []SkillItem, version string) (install, uninstall, update []SkillItem, err error) {
 	// Sort with starred skills first, then alphabetical
 	SortSkills(allSkills)
 
-	m := NewSkillsModel(product, allSkills, version, targetDir)
+	m := NewSkillsModel(targets[0].Product, allSkills, version, targets[0].Dir)
+	m.targets = targets
 	p := tea.NewProgram(m)
 
 	finalModel, err := p.Run()
@@ -1032,6 +1284,8 @@ func RunSkillsSelect(product *products.Product, allSkills []SkillItem, version s
 	}
 
 	result := finalModel.(SkillsModel)
+	result.noteSeen()
+	_ = MarkSkillsSeen(result.seen)
 	if result.IsQuitting() {
 		return nil, nil, nil, fmt.Errorf("quit")
 	}
@@ -1041,3 +1295,29 @@ func RunSkillsSelect(product *products.Product, allSkills []SkillItem, version s
 
 	return result.InstallSkills(), result.UninstallSkills(), result.UpdateSkills(), nil
 }
+
+// renderDiffView renders the scrollable diff view below the logo
+func (m SkillsModel) renderDiffView() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(i18n.T("tui_diff_title")))
+	b.WriteString("\n")
+	b.WriteString(separatorStyle.Render(strings.Repeat("─", SeparatorWidth)))
+	b.WriteString("\n")
+
+	end := min(m.diffOffset+diffPageSize, len(m.diffView))
+	for _, line := range m.diffView[m.diffOffset:end] {
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	for i := end - m.diffOffset; i < diffPageSize; i++ {
+		b.WriteString("\n")
+	}
+
+	if len(m.diffView) > diffPageSize {
+		b.WriteString("\n")
+		b.WriteString(hintStyle.Render(fmt.Sprintf("%d-%d/%d", m.diffOffset+1, end, len(m.diffView))))
+	}
+	b.WriteString(RenderHint(i18n.T("tui_diff_hint")))
+	return b.String()
+}
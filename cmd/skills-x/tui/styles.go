@@ -91,6 +91,11 @@ var (
 			Foreground(whiteColor).
 			Background(lipgloss.Color("#333333"))
 
+	// 搜索匹配高亮样式
+	matchStyle = lipgloss.NewStyle().
+			Foreground(yellowColor).
+			Underline(true)
+
 	// 描述文本样式 (作为基础样式，会被渐变覆盖)
 	descriptionStyle = lipgloss.NewStyle().
 			Foreground(primaryColor)
@@ -3,7 +3,6 @@ package tui
 
 import (
 	"fmt"
-	"path/filepath"
 
 	"github.com/castle-x/skills-x/cmd/skills-x/i18n"
 	"github.com/castle-x/skills-x/pkg/products"
@@ -11,7 +10,7 @@ import (
 
 // ============================================================================
 // TUI Main Entry - Page Flow Orchestration
-// Level 1: Select Product -> Level 2: Select Skills -> Install
+// Level 1: Select Products -> Level 2: Select Scopes -> Level 3: Select Skills -> Install
 // ============================================================================
 
 // TUIOptions contains options for running the TUI
@@ -36,46 +35,48 @@ func RunTUI(opts TUIOptions) error {
 // runTUIFlow runs the TUI page flow (can be called recursively for "go back")
 func runTUIFlow(opts TUIOptions) error {
 	// =========================================================================
-	// Level 1: Select Product
+	// Level 1: Select Products (one or several)
 	// =========================================================================
 	fmt.Print(ClearScreen)
-	product, err := RunProductSelect(opts.Version, opts.TargetDir)
+	chosen, err := RunProductSelect(opts.Version, opts.TargetDir)
 	if err != nil {
 		return fmt.Errorf("%s: %w", i18n.T("error_product_select"), err)
 	}
-	if product == nil {
+	if len(chosen) == 0 {
 		return nil
 	}
 
 	// =========================================================================
-	// Level 2: Select Install Target (Global or Project)
+	// Level 2: Select Install Targets (Global, Project or both)
 	// =========================================================================
 	fmt.Print(ClearScreen)
-	installTarget, err := RunInstallTargetSelect(product, opts.TargetDir)
+	scopes, err := RunInstallTargetSelect(chosen, opts.TargetDir)
 	if err != nil {
 		return fmt.Errorf("%s: %w", i18n.T("error_install_target_select"), err)
 	}
-	if installTarget == "" {
+	if len(scopes) == 0 {
 		return nil
 	}
 
-	var targetDir string
-	if installTarget == "global" {
-		targetDir = product.GlobalPath()
-	} else {
-		targetDir = filepath.Join(opts.TargetDir, product.ProjectSkills)
+	names := make([]string, len(chosen))
+	for i, p := range chosen {
+		names[i] = p.Name
+	}
+	targets, err := products.ResolveTargets(names, scopes, opts.TargetDir)
+	if err != nil {
+		return err
 	}
 
 	// =========================================================================
 	// Level 3: Select Skills
 	// =========================================================================
-	allSkills, err := LoadSkillsForProduct(product, targetDir)
+	allSkills, err := LoadSkillsForTargets(targets)
 	if err != nil {
 		return fmt.Errorf("%s: %w", i18n.T("error_load_skills"), err)
 	}
 
 	fmt.Print(ClearScreen)
-	installSkills, uninstallSkills, updateSkills, err := RunSkillsSelect(product, allSkills, opts.Version, targetDir)
+	installSkills, uninstallSkills, updateSkills, err := RunSkillsSelect(targets, allSkills, opts.Version)
 	if err != nil {
 		if err.Error() == "quit" {
 			return nil
@@ -91,10 +92,10 @@ func runTUIFlow(opts TUIOptions) error {
 	}
 
 	// =========================================================================
-	// Level 4: Install/Update/Uninstall Skills
+	// Level 4: Install/Update/Uninstall Skills in every target
 	// =========================================================================
 	fmt.Print(ClearScreen)
-	completed, failed, err := RunInstaller(installSkills, uninstallSkills, updateSkills, targetDir)
+	completed, failed, err := RunInstaller(installSkills, uninstallSkills, updateSkills, targets)
 	if err != nil {
 		return fmt.Errorf("%s: %w", i18n.T("error_installation"), err)
 	}
@@ -104,17 +105,30 @@ func runTUIFlow(opts TUIOptions) error {
 	return nil
 }
 
-// LoadSkillsForProduct loads skills for a specific product
-func LoadSkillsForProduct(product *products.Product, targetDir string) ([]SkillItem, error) {
-	// Load skills from registry
-	skills, err := LoadSkillsFromRegistry(targetDir)
-	if err != nil {
-		return nil, err
+// LoadSkillsForTargets loads the skills for the chosen targets. A skill
+// counts as installed when any target has it, with the metadata of the
+// first such target; skills meant for none of the products are left out.
+func LoadSkillsForTargets(targets []products.Target) ([]SkillItem, error) {
+	var merged []SkillItem
+	index := make(map[string]int)
+	for _, t := range targets {
+		skills, err := loadSkills(t.Dir, t.Product.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range skills {
+			i, ok := index[s.FullName]
+			if !ok {
+				index[s.FullName] = len(merged)
+				merged = append(merged, s)
+				continue
+			}
+			if s.Installed && !merged[i].Installed {
+				merged[i].Installed = true
+				merged[i].Meta = s.Meta
+			}
+		}
 	}
-
-	// Filter skills for the selected product if needed
-	// For now, we return all skills - the user can filter in the UI
-
-	return skills, nil
+	SortSkills(merged)
+	return merged, nil
 }
-
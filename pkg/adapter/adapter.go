@@ -0,0 +1,174 @@
+// Package adapter converts installed skills into the rules formats of
+// products that do not read SKILL.md folders.
+//
+// The skill folder is still installed as usual and stays the source of
+// truth: updates, diffs and rollbacks work on it, and Apply regenerates the
+// converted output from it afterwards. Generated files carry a marker, so a
+// file the user wrote by hand is never overwritten or deleted.
+//
+// The adapter is chosen by the Format of the product a skills directory
+// belongs to (see products.ForDir). Output goes where the tool reads it,
+// relative to the project root:
+//
+//	cursor     .cursor/rules/<skill>.mdc
+//	windsurf   .windsurf/rules/<skill>.md
+//	copilot    .github/instructions/<skill>.instructions.md
+//	agents-md  a section of AGENTS.md
+//
+// Skills installed globally are not converted: these formats are read
+// from a project only.
+package adapter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/castle-x/skills-x/pkg/fsutil"
+	"github.com/castle-x/skills-x/pkg/products"
+	"gopkg.in/yaml.v3"
+)
+
+// Adapter writes a product's converted form of a project's skills
+type Adapter interface {
+	// Export writes or rewrites the converted form of s in the project at root
+	Export(root string, s *Skill) error
+	// Remove deletes the converted form of the named skill, if any
+	Remove(root string, name string) error
+}
+
+var adapters = map[string]Adapter{}
+
+// Register makes an adapter available under a format name
+func Register(format string, a Adapter) {
+	adapters[format] = a
+}
+
+// Get returns the adapter for format, or nil
+func Get(format string) Adapter {
+	return adapters[format]
+}
+
+// Formats returns the registered format names, sorted
+func Formats() []string {
+	names := make([]string, 0, len(adapters))
+	for name := range adapters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply brings the converted form of the skill name in targetDir up to
+// date: exported when the skill is installed, removed when it is not. It
+// does nothing for directories of products without a format, and for
+// global skills directories.
+func Apply(targetDir string, name string) error {
+	p := products.ForDir(targetDir)
+	if p == nil {
+		return nil
+	}
+	return applyFor(p, targetDir, name)
+}
+
+// applyFor is Apply for a skills directory of product p
+func applyFor(p *products.Product, targetDir string, name string) error {
+	if p.Format == "" {
+		return nil
+	}
+	a := Get(p.Format)
+	if a == nil {
+		return fmt.Errorf("unknown format %q for %s (want one of %s)", p.Format, p.Name, strings.Join(Formats(), ", "))
+	}
+	root, ok := p.ProjectRoot(targetDir)
+	if !ok {
+		return nil
+	}
+
+	skillDir := filepath.Join(targetDir, name)
+	if !fsutil.DirExists(skillDir) {
+		return a.Remove(root, name)
+	}
+	s, err := Load(skillDir)
+	if err != nil {
+		return err
+	}
+	return a.Export(root, s)
+}
+
+// Skill is the content of a SKILL.md
+type Skill struct {
+	Name        string
+	Description string
+	Globs       []string // Files the skill applies to; empty means any
+	Body        string   // Markdown after the frontmatter
+	Dir         string   // The installed skill directory
+}
+
+// frontmatter holds the SKILL.md fields adapters use
+type frontmatter struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Globs       globList `yaml:"globs"`
+}
+
+// globList accepts globs as a list or as one comma-separated string
+type globList []string
+
+func (g *globList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		for _, glob := range strings.Split(node.Value, ",") {
+			if glob = strings.TrimSpace(glob); glob != "" {
+				*g = append(*g, glob)
+			}
+		}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*g = list
+	return nil
+}
+
+// Load reads the SKILL.md of the skill in dir. The skill is named after its
+// directory when the frontmatter has no name.
+func Load(dir string) (*Skill, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		return nil, err
+	}
+	s := &Skill{Name: filepath.Base(dir), Dir: dir}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	rest, ok := strings.CutPrefix(text, "---\n")
+	if !ok {
+		s.Body = strings.TrimSpace(text)
+		return s, nil
+	}
+	head, body, ok := strings.Cut(rest, "\n---")
+	if !ok {
+		s.Body = strings.TrimSpace(text)
+		return s, nil
+	}
+	var fm frontmatter
+	if err := yaml.Unmarshal([]byte(head), &fm); err != nil {
+		return nil, fmt.Errorf("%s: invalid frontmatter: %w", filepath.Join(dir, "SKILL.md"), err)
+	}
+	if fm.Name != "" {
+		s.Name = fm.Name
+	}
+	s.Description = strings.TrimSpace(fm.Description)
+	s.Globs = fm.Globs
+	// Drop the rest of the closing --- line
+	if _, after, found := strings.Cut(body, "\n"); found {
+		body = after
+	} else {
+		body = ""
+	}
+	s.Body = strings.TrimSpace(body)
+	return s, nil
+}
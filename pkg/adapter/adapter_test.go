@@ -0,0 +1,217 @@
+package adapter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/products"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+const pdfSkill = `---
+name: pdf
+description: "Fill forms: extract text and tables from PDF files"
+globs: "*.pdf, docs/**/*.pdf"
+---
+
+# PDF
+
+Run scripts/extract.py on the file.
+`
+
+func TestLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pdf")
+	writeFile(t, filepath.Join(dir, "SKILL.md"), pdfSkill)
+	s, err := Load(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "pdf" || !strings.HasPrefix(s.Description, "Fill forms:") {
+		t.Errorf("Load = %+v", s)
+	}
+	if len(s.Globs) != 2 || s.Globs[1] != "docs/**/*.pdf" {
+		t.Errorf("globs = %q", s.Globs)
+	}
+	if !strings.HasPrefix(s.Body, "# PDF") {
+		t.Errorf("body = %q", s.Body)
+	}
+
+	writeFile(t, filepath.Join(dir, "SKILL.md"), "---\nglobs: [\"*.go\"]\n---\nBody\n")
+	if s, _ = Load(dir); s.Name != "pdf" || len(s.Globs) != 1 || s.Body != "Body" {
+		t.Errorf("Load(list globs) = %+v", s)
+	}
+}
+
+func TestApply_RulesFile(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	skills := filepath.Join(project, ".cursor", "skills")
+	writeFile(t, filepath.Join(skills, "pdf", "SKILL.md"), pdfSkill)
+	writeFile(t, filepath.Join(skills, "pdf", "scripts", "extract.py"), "print()\n")
+
+	if err := Apply(skills, "pdf"); err != nil {
+		t.Fatal(err)
+	}
+	rule := filepath.Join(project, ".cursor", "rules", "pdf.mdc")
+	got := readFile(t, rule)
+	for _, want := range []string{
+		"description: 'Fill forms: extract text and tables from PDF files'\n",
+		"globs: *.pdf,docs/**/*.pdf\n",
+		generatedMarker,
+		"> Skill files: `../skills/pdf`",
+		"# PDF\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("rule lacks %q:\n%s", want, got)
+		}
+	}
+
+	// An update regenerates the rule from the new SKILL.md
+	writeFile(t, filepath.Join(skills, "pdf", "SKILL.md"), strings.Replace(pdfSkill, "scripts/extract.py", "scripts/v2.py", 1))
+	if err := Apply(skills, "pdf"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, rule); !strings.Contains(got, "scripts/v2.py") {
+		t.Errorf("rule not regenerated:\n%s", got)
+	}
+
+	// Uninstalling removes it
+	if err := os.RemoveAll(filepath.Join(skills, "pdf")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(skills, "pdf"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(rule); !os.IsNotExist(err) {
+		t.Error("rule left behind after uninstall")
+	}
+}
+
+func TestApply_KeepsHandWrittenRules(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	skills := filepath.Join(project, ".windsurf", "skills")
+	rule := filepath.Join(project, ".windsurf", "rules", "pdf.md")
+	writeFile(t, rule, "my own rule\n")
+	writeFile(t, filepath.Join(skills, "pdf", "SKILL.md"), pdfSkill)
+
+	if err := Apply(skills, "pdf"); err == nil {
+		t.Error("Apply overwrote a hand-written rule")
+	}
+	os.RemoveAll(filepath.Join(skills, "pdf"))
+	if err := Apply(skills, "pdf"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readFile(t, rule); got != "my own rule\n" {
+		t.Errorf("hand-written rule changed: %q", got)
+	}
+}
+
+func TestApply_AgentsMD(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	skills := filepath.Join(project, ".aider", "skills")
+	agents := filepath.Join(project, "AGENTS.md")
+	writeFile(t, agents, "# Project rules\n\nUse tabs.\n")
+	writeFile(t, filepath.Join(skills, "pdf", "SKILL.md"), pdfSkill)
+	writeFile(t, filepath.Join(skills, "docx", "SKILL.md"), "---\nname: docx\ndescription: Word files\n---\n\n## Usage\n\nOpen it.\n")
+
+	for _, name := range []string{"pdf", "docx", "pdf"} {
+		if err := Apply(skills, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := readFile(t, agents)
+	if !strings.HasPrefix(got, "# Project rules\n\nUse tabs.\n\n"+sectionBegin("pdf")) {
+		t.Errorf("user text not kept in front:\n%s", got)
+	}
+	if strings.Count(got, sectionBegin("pdf")) != 1 {
+		t.Errorf("re-export duplicated the section:\n%s", got)
+	}
+	if !strings.Contains(got, "### PDF") || !strings.Contains(got, "#### Usage") {
+		t.Errorf("headings not nested under the section title:\n%s", got)
+	}
+
+	os.RemoveAll(filepath.Join(skills, "pdf"))
+	os.RemoveAll(filepath.Join(skills, "docx"))
+	for _, name := range []string{"pdf", "docx"} {
+		if err := Apply(skills, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := readFile(t, agents); got != "# Project rules\n\nUse tabs.\n" {
+		t.Errorf("AGENTS.md after removing both sections = %q", got)
+	}
+}
+
+func TestApply_SkillFormatProducts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	skills := filepath.Join(project, ".claude", "skills")
+	writeFile(t, filepath.Join(skills, "pdf", "SKILL.md"), pdfSkill)
+	if err := Apply(skills, "pdf"); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(project, ".claude"))
+	if len(entries) != 1 {
+		t.Errorf("Claude Code reads SKILL.md folders; got extra output %v", entries)
+	}
+}
+
+func TestApply_GlobalScopeIsNotConverted(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, dir := range []string{".cursor", ".aider"} {
+		skills := filepath.Join(home, dir, "skills")
+		writeFile(t, filepath.Join(skills, "pdf", "SKILL.md"), pdfSkill)
+		if err := Apply(skills, "pdf"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(home, ".cursor", "rules")); !os.IsNotExist(err) {
+		t.Error("global Cursor skills were converted into ~/.cursor/rules")
+	}
+	if _, err := os.Stat(filepath.Join(home, "AGENTS.md")); !os.IsNotExist(err) {
+		t.Error("global Aider skills were written to ~/AGENTS.md")
+	}
+}
+
+func TestApply_CustomProjectDepth(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	project := t.TempDir()
+	skills := filepath.Join(project, "tools", "agents", "skills")
+	writeFile(t, filepath.Join(skills, "pdf", "SKILL.md"), pdfSkill)
+
+	for format, out := range map[string]string{
+		"cursor":    filepath.Join(project, ".cursor", "rules", "pdf.mdc"),
+		"agents-md": filepath.Join(project, "AGENTS.md"),
+	} {
+		p := &products.Product{Name: "Tool", GlobalSkills: "~/.tool/skills/", ProjectSkills: "tools/agents/skills/", Format: format}
+		if err := applyFor(p, skills, "pdf"); err != nil {
+			t.Fatal(err)
+		}
+		if got := readFile(t, out); !strings.Contains(got, "# PDF") {
+			t.Errorf("%s output:\n%s", format, got)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package adapter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	Register("cursor", rulesFile{dir: ".cursor/rules", ext: ".mdc", head: cursorHead})
+	Register("windsurf", rulesFile{dir: ".windsurf/rules", ext: ".md", head: windsurfHead})
+	Register("copilot", rulesFile{dir: ".github/instructions", ext: ".instructions.md", head: copilotHead})
+	Register("agents-md", agentsMD{})
+}
+
+// generatedMarker starts the comment that marks a generated rules file
+const generatedMarker = "<!-- skills-x:generated"
+
+// rulesFile writes one rules file per skill: <root>/<dir>/<skill><ext>
+type rulesFile struct {
+	dir  string // Slash-separated, relative to the project root
+	ext  string
+	head func(s *Skill) string // Frontmatter lines
+}
+
+func (r rulesFile) path(root string, name string) string {
+	return filepath.Join(root, filepath.FromSlash(r.dir), name+r.ext)
+}
+
+func (r rulesFile) Export(root string, s *Skill) error {
+	path := r.path(root, s.key())
+	if err := checkGenerated(path); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	// Empty values are left blank, without a trailing space
+	b.WriteString(strings.ReplaceAll(r.head(s), ": \n", ":\n"))
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "%s from %s; edit the skill, not this file -->\n\n", generatedMarker, filepath.ToSlash(relTo(filepath.Dir(path), s.Dir)))
+	writeBody(&b, s, filepath.Dir(path), 0)
+	return writeIfChanged(path, b.String())
+}
+
+func (r rulesFile) Remove(root string, name string) error {
+	path := r.path(root, name)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(data), generatedMarker) {
+		return nil
+	}
+	return os.Remove(path)
+}
+
+// checkGenerated refuses to replace a file skills-x did not write
+func checkGenerated(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(data), generatedMarker) {
+		return fmt.Errorf("%s was not generated by skills-x; leaving it alone", path)
+	}
+	return nil
+}
+
+// cursorHead is the frontmatter of a Cursor .mdc rule: the agent picks the
+// rule by its description, or attaches it to files matching globs
+func cursorHead(s *Skill) string {
+	return fmt.Sprintf("description: %s\nglobs: %s\nalwaysApply: false\n", yamlValue(s.Description), strings.Join(s.Globs, ","))
+}
+
+// windsurfHead is the frontmatter of a Windsurf rule
+func windsurfHead(s *Skill) string {
+	if len(s.Globs) > 0 {
+		return fmt.Sprintf("trigger: glob\nglobs: %s\ndescription: %s\n", strings.Join(s.Globs, ","), yamlValue(s.Description))
+	}
+	return fmt.Sprintf("trigger: model_decision\ndescription: %s\n", yamlValue(s.Description))
+}
+
+// copilotHead is the frontmatter of a GitHub Copilot instructions file
+func copilotHead(s *Skill) string {
+	applyTo := "**"
+	if len(s.Globs) > 0 {
+		applyTo = strings.Join(s.Globs, ",")
+	}
+	return fmt.Sprintf("description: %s\napplyTo: %q\n", yamlValue(s.Description), applyTo)
+}
+
+// agentsMD keeps one section per skill in the project's AGENTS.md. Text
+// outside the sections is left as it is.
+type agentsMD struct{}
+
+func agentsPath(root string) string {
+	return filepath.Join(root, "AGENTS.md")
+}
+
+func sectionBegin(name string) string { return "<!-- skills-x:begin " + name + " -->" }
+func sectionEnd(name string) string   { return "<!-- skills-x:end " + name + " -->" }
+
+func (agentsMD) Export(root string, s *Skill) error {
+	path := agentsPath(root)
+	name := s.key()
+
+	var b strings.Builder
+	b.WriteString(sectionBegin(name) + "\n")
+	fmt.Fprintf(&b, "## %s\n\n", s.Name)
+	if s.Description != "" {
+		b.WriteString(s.Description + "\n\n")
+	}
+	writeBody(&b, s, filepath.Dir(path), 2)
+	b.WriteString(sectionEnd(name))
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	text, found := replaceSection(string(data), name, b.String())
+	if !found {
+		text = strings.TrimRight(string(data), "\n")
+		if text != "" {
+			text += "\n\n"
+		}
+		text += b.String() + "\n"
+	}
+	return writeIfChanged(path, text)
+}
+
+func (agentsMD) Remove(root string, name string) error {
+	path := agentsPath(root)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	text, found := replaceSection(string(data), name, "")
+	if !found {
+		return nil
+	}
+	// Collapse the blank lines the section leaves behind
+	for strings.Contains(text, "\n\n\n") {
+		text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+	}
+	if strings.TrimSpace(text) == "" {
+		return os.Remove(path)
+	}
+	return writeIfChanged(path, strings.Trim(text, "\n")+"\n")
+}
+
+// replaceSection replaces the named skill's section of text, markers
+// included, with section
+func replaceSection(text string, name string, section string) (string, bool) {
+	start := strings.Index(text, sectionBegin(name))
+	if start < 0 {
+		return text, false
+	}
+	end := strings.Index(text[start:], sectionEnd(name))
+	if end < 0 {
+		return text, false
+	}
+	end += start + len(sectionEnd(name))
+	return text[:start] + section + text[end:], true
+}
+
+// writeBody writes the skill's instructions, pointing to its folder when
+// it has files besides SKILL.md. Headings are demoted by shift levels so
+// they nest under a section title.
+func writeBody(b *strings.Builder, s *Skill, fromDir string, shift int) {
+	if hasSupportFiles(s.Dir) {
+		fmt.Fprintf(b, "> Skill files: `%s`\n\n", filepath.ToSlash(relTo(fromDir, s.Dir)))
+	}
+	if s.Body == "" {
+		return
+	}
+	b.WriteString(demoteHeadings(s.Body, shift) + "\n")
+}
+
+// demoteHeadings adds shift levels to every Markdown heading outside code
+// fences
+func demoteHeadings(body string, shift int) string {
+	if shift == 0 {
+		return body
+	}
+	lines := strings.Split(body, "\n")
+	inFence := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence && strings.HasPrefix(line, "#") {
+			lines[i] = strings.Repeat("#", shift) + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// hasSupportFiles reports whether the skill folder holds more than its
+// SKILL.md and skills-x bookkeeping
+func hasSupportFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if e.Name() != "SKILL.md" && !strings.HasPrefix(e.Name(), ".skills-x") {
+			return true
+		}
+	}
+	return false
+}
+
+// key is the name converted output is filed under: the skill's directory
+// name, which is what Apply and Remove are given
+func (s *Skill) key() string {
+	return filepath.Base(s.Dir)
+}
+
+// relTo returns target relative to dir, or target itself when it has none
+func relTo(dir string, target string) string {
+	rel, err := filepath.Rel(dir, target)
+	if err != nil {
+		return target
+	}
+	return rel
+}
+
+// yamlValue renders s as a single-line YAML scalar, quoted when needed
+func yamlValue(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if s == "" {
+		return ""
+	}
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Sprintf("%q", s)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// writeIfChanged writes content to path unless it holds it already, so
+// regenerating unchanged skills does not touch their files
+func writeIfChanged(path string, content string) error {
+	if data, err := os.ReadFile(path); err == nil && string(data) == content {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0644)
+}
@@ -251,3 +251,34 @@ func dirExists(path string) bool {
 	}
 	return info.IsDir()
 }
+
+// FindSkillInRepo searches a cloned repository for a skill by name.
+// Common locations are tried first, then a full discovery scan.
+// Returns nil (and no error) when the skill cannot be found.
+func FindSkillInRepo(repoPath string, skillName string) (*DiscoveredSkill, error) {
+	commonPaths := []string{
+		filepath.Join("skills", skillName),
+		filepath.Join("packages", "docs", "skills", skillName),
+		skillName,
+	}
+
+	for _, path := range commonPaths {
+		fullPath := filepath.Join(repoPath, path)
+		if isSkillDir(fullPath) {
+			return parseSkillDir(fullPath)
+		}
+	}
+
+	discovered, err := DiscoverSkills(repoPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, d := range discovered {
+		if strings.EqualFold(d.Name, skillName) {
+			return &d, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,107 @@
+// Package fsutil provides filesystem helpers shared by the install commands
+package fsutil
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DirExists checks if a directory exists
+func DirExists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return info.IsDir()
+}
+
+// FileExists checks if a regular file exists
+func FileExists(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir()
+}
+
+// CopyDir copies a directory from source to destination, replacing any
+// existing destination. Symlinks are followed so the actual content is copied;
+// broken symlinks and symlinks leading outside srcPath are skipped, so a
+// checkout cannot pull files from elsewhere on the host into the copy.
+func CopyDir(srcPath string, dstPath string) error {
+	os.RemoveAll(dstPath)
+
+	root, err := filepath.EvalSymlinks(srcPath)
+	if err != nil {
+		return err
+	}
+	return copyDir(srcPath, dstPath, root)
+}
+
+// copyDir copies srcPath to dstPath, following only the symlinks that
+// resolve inside root
+func copyDir(srcPath string, dstPath string, root string) error {
+	return filepath.WalkDir(srcPath, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(srcPath, path)
+		if err != nil {
+			return err
+		}
+
+		targetPath := filepath.Join(dstPath, relPath)
+
+		if d.Type()&os.ModeSymlink != 0 {
+			realPath, err := filepath.EvalSymlinks(path)
+			if err != nil || !within(root, realPath) {
+				return nil
+			}
+			info, err := os.Stat(realPath)
+			if err != nil {
+				return nil
+			}
+			if info.IsDir() {
+				return copyDir(realPath, targetPath, root)
+			}
+			return CopyFile(realPath, targetPath)
+		}
+
+		if d.IsDir() {
+			return os.MkdirAll(targetPath, 0755)
+		}
+
+		return CopyFile(path, targetPath)
+	})
+}
+
+// within reports whether path is root or below it
+func within(root string, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// CopyFile copies a single file, creating parent directories as needed
+func CopyFile(srcPath string, dstPath string) error {
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return err
+	}
+
+	srcFile, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	dstFile, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	defer dstFile.Close()
+
+	_, err = io.Copy(dstFile, srcFile)
+	return err
+}
@@ -0,0 +1,49 @@
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCopyDir_SkipsLinksOutOfSource(t *testing.T) {
+	base := t.TempDir()
+	secret := filepath.Join(base, "secret.txt")
+	if err := os.WriteFile(secret, []byte("host file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(base, "skill")
+	if err := os.MkdirAll(filepath.Join(src, "docs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("# skill\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for name, target := range map[string]string{
+		"abs":          secret,
+		"rel":          "../secret.txt",
+		"home":         base,
+		"README.md":    "SKILL.md",
+		"docs/skill":   "../SKILL.md",
+		"docs/missing": "nowhere",
+	} {
+		if err := os.Symlink(target, filepath.Join(src, name)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	dst := filepath.Join(base, "installed")
+	if err := CopyDir(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"abs", "rel", "home", "docs/missing"} {
+		if _, err := os.Lstat(filepath.Join(dst, name)); !os.IsNotExist(err) {
+			t.Errorf("%s was copied; links out of the source must be skipped", name)
+		}
+	}
+	for _, name := range []string{"README.md", "docs/skill"} {
+		if data, err := os.ReadFile(filepath.Join(dst, name)); err != nil || string(data) != "# skill\n" {
+			t.Errorf("%s = %q, %v; want the linked SKILL.md", name, data, err)
+		}
+	}
+}
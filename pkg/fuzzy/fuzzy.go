@@ -0,0 +1,246 @@
+// Package fuzzy implements fzf-style fuzzy matching with scoring, including
+// pinyin matching of Chinese text
+package fuzzy
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Scores, after fzf: every matched character earns scoreMatch, characters
+// at word starts and right after another match earn more, and skipped
+// characters between matches cost a little
+const (
+	scoreMatch        = 16
+	scoreGapStart     = -3
+	scoreGapExtension = -1
+
+	bonusBoundary    = 8
+	bonusCamel       = 7
+	bonusConsecutive = 4
+
+	// The first pattern character's bonus counts this many times, so
+	// matches that start at a word start rank first
+	bonusFirstCharMultiplier = 2
+)
+
+// Match is a successful match of a pattern in a text
+type Match struct {
+	Score     int
+	Positions []int // Indexes of the matched runes in the text, ascending
+}
+
+// Find matches pattern against text. Every whitespace-separated word of the
+// pattern must match, in any order; each matches as a subsequence of the
+// text, ignoring case. A Han character in the text also matches its pinyin
+// or any prefix of it, so "qd", "qiand" and "qianduan" all find "前端".
+//
+// Unlike fzf, a match scattered over the middle of words is rejected:
+// after the first run of matched characters, a word may only start a new
+// run away from a word start once per four characters. Long descriptions
+// would otherwise match nearly every short query.
+func Find(pattern, text string) (Match, bool) {
+	words := strings.Fields(pattern)
+	if len(words) == 0 {
+		return Match{}, false
+	}
+	runes := []rune(text)
+	var total Match
+	seen := make(map[int]bool)
+	for _, word := range words {
+		m, ok := findWord([]rune(strings.ToLower(word)), runes)
+		if !ok {
+			return Match{}, false
+		}
+		total.Score += m.Score
+		for _, p := range m.Positions {
+			if !seen[p] {
+				seen[p] = true
+				total.Positions = append(total.Positions, p)
+			}
+		}
+	}
+	sort.Ints(total.Positions)
+	return total, true
+}
+
+// step is how the best path reached a dp cell: the previous cell and, when
+// the text rune matched, how many pattern runes it took
+type step struct {
+	prevJ    int
+	prevLast bool
+	taken    int
+}
+
+// findWord finds the best-scoring alignment of pattern in text by dynamic
+// programming over (text runes seen, pattern runes matched, whether the
+// last text rune matched)
+func findWord(pattern, text []rune) (Match, bool) {
+	n, m := len(text), len(pattern)
+	if m == 0 || n == 0 {
+		return Match{}, false
+	}
+
+	const unreachable = math.MinInt32
+	score := make([][2][]int, n+1)
+	from := make([][2][]step, n+1)
+	for i := range score {
+		for last := 0; last < 2; last++ {
+			score[i][last] = make([]int, m+1)
+			from[i][last] = make([]step, m+1)
+			for j := range score[i][last] {
+				score[i][last][j] = unreachable
+			}
+		}
+	}
+	score[0][0][0] = 0
+
+	for i := 0; i < n; i++ {
+		bonus := boundaryBonus(text, i)
+		for last := 0; last < 2; last++ {
+			for j := 0; j <= m; j++ {
+				cur := score[i][last][j]
+				if cur == unreachable {
+					continue
+				}
+				// Skip text[i]; gaps only cost between the first and last match
+				skip := cur
+				if j > 0 && j < m {
+					if last == 1 {
+						skip += scoreGapStart
+					} else {
+						skip += scoreGapExtension
+					}
+				}
+				if skip > score[i+1][0][j] {
+					score[i+1][0][j] = skip
+					from[i+1][0][j] = step{prevJ: j, prevLast: last == 1}
+				}
+				// Match text[i] against the next k pattern runes
+				for _, k := range matchLengths(text[i], pattern[j:]) {
+					s := cur + k*scoreMatch
+					switch {
+					case j == 0:
+						s += bonus * bonusFirstCharMultiplier
+					case last == 1:
+						s += max(bonus, bonusConsecutive)
+					default:
+						s += bonus
+					}
+					if s > score[i+1][1][j+k] {
+						score[i+1][1][j+k] = s
+						from[i+1][1][j+k] = step{prevJ: j, prevLast: last == 1, taken: k}
+					}
+				}
+			}
+		}
+	}
+
+	best, bestLast := unreachable, 0
+	for last := 0; last < 2; last++ {
+		if score[n][last][m] > best {
+			best, bestLast = score[n][last][m], last
+		}
+	}
+	if best == unreachable {
+		return Match{}, false
+	}
+
+	var positions []int
+	j, last := m, bestLast == 1
+	for i := n; i > 0; i-- {
+		st := from[i][boolIndex(last)][j]
+		if st.taken > 0 {
+			positions = append(positions, i-1)
+		}
+		j, last = st.prevJ, st.prevLast
+	}
+	for l, r := 0, len(positions)-1; l < r; l, r = l+1, r-1 {
+		positions[l], positions[r] = positions[r], positions[l]
+	}
+
+	if breaks(text, positions) > m/4 {
+		return Match{}, false
+	}
+	return Match{Score: best, Positions: positions}, true
+}
+
+// matchLengths returns how many leading runes of pattern text rune r can
+// match: one for the same letter, or for a Han character the length of each
+// prefix of its pinyin that pattern starts with
+func matchLengths(r rune, pattern []rune) []int {
+	if len(pattern) == 0 {
+		return nil
+	}
+	if unicode.ToLower(r) == pattern[0] {
+		return []int{1}
+	}
+	var lengths []int
+	for _, reading := range Pinyin(r) {
+		for k := 1; k <= len(reading) && k <= len(pattern); k++ {
+			if rune(reading[k-1]) != pattern[k-1] {
+				break
+			}
+			if !containsInt(lengths, k) {
+				lengths = append(lengths, k)
+			}
+		}
+	}
+	return lengths
+}
+
+// boundaryBonus scores text[i] as the start of a word
+func boundaryBonus(text []rune, i int) int {
+	if !isWordRune(text[i]) {
+		return 0
+	}
+	if i == 0 || !isWordRune(text[i-1]) {
+		return bonusBoundary
+	}
+	prev, cur := text[i-1], text[i]
+	if unicode.IsLower(prev) && unicode.IsUpper(cur) ||
+		!unicode.IsDigit(prev) && unicode.IsDigit(cur) ||
+		!isHan(prev) && isHan(cur) {
+		return bonusCamel
+	}
+	return 0
+}
+
+// breaks counts the runs of matched positions after the first that start
+// inside a word
+func breaks(text []rune, positions []int) int {
+	count := 0
+	for k := 1; k < len(positions); k++ {
+		p := positions[k]
+		if p != positions[k-1]+1 && boundaryBonus(text, p) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isHan(r rune) bool {
+	return unicode.Is(unicode.Han, r)
+}
+
+func boolIndex(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func containsInt(list []int, v int) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package fuzzy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		want          bool
+		positions     []int
+	}{
+		{"react", "vercel/react-best-practices", true, []int{7, 8, 9, 10, 11}},
+		{"frntend", "anthropic/frontend-design", true, []int{10, 11, 13, 14, 15, 16, 17}},
+		{"fd", "frontend-design", true, []int{0, 9}},
+		{"DESIGN", "frontend-design", true, []int{9, 10, 11, 12, 13, 14}},
+		{"design front", "frontend-design", true, []int{0, 1, 2, 3, 4, 9, 10, 11, 12, 13, 14}},
+		{"art", "anthropic/frontend-design", false, nil},
+		{"react", "Frontend design best practices", false, nil},
+		{"xyz", "frontend-design", false, nil},
+		{"", "frontend-design", false, nil},
+	}
+	for _, tt := range tests {
+		m, ok := Find(tt.pattern, tt.text)
+		if ok != tt.want {
+			t.Errorf("Find(%q, %q) ok = %v, want %v", tt.pattern, tt.text, ok, tt.want)
+			continue
+		}
+		if ok && !reflect.DeepEqual(m.Positions, tt.positions) {
+			t.Errorf("Find(%q, %q) positions = %v, want %v", tt.pattern, tt.text, m.Positions, tt.positions)
+		}
+	}
+}
+
+func TestFindPinyin(t *testing.T) {
+	text := "现代前端设计指南"
+	for _, pattern := range []string{"qd", "qiand", "qianduan", "前端", "qd设计"} {
+		m, ok := Find(pattern, text)
+		if !ok {
+			t.Errorf("Find(%q, %q) did not match", pattern, text)
+			continue
+		}
+		if m.Positions[0] != 2 || m.Positions[1] != 3 {
+			t.Errorf("Find(%q, %q) positions = %v, want 前端 at 2-3", pattern, text, m.Positions)
+		}
+	}
+
+	// Initials scattered across the text are not a match
+	if _, ok := Find("xz", "现代前端设计指南"); ok {
+		t.Error("scattered initials should not match")
+	}
+	// Second readings count too
+	if _, ok := Find("yinhang", "银行"); !ok {
+		t.Error("行 should also read hang")
+	}
+}
+
+func TestFindRanksWordStartsFirst(t *testing.T) {
+	start, _ := Find("pdf", "pdf-tools")
+	inside, _ := Find("pdf", "updf-tools")
+	scattered, ok := Find("pdf", "pptx-docx-forms")
+	if !ok {
+		t.Fatal("word-start initials should match")
+	}
+	// As in fzf, word starts outrank a run inside a word
+	if !(start.Score > scattered.Score && scattered.Score > inside.Score) {
+		t.Errorf("scores start=%d scattered=%d inside=%d, want descending", start.Score, scattered.Score, inside.Score)
+	}
+}
+
+func TestPinyin(t *testing.T) {
+	if got := Pinyin('端'); !reflect.DeepEqual(got, []string{"duan"}) {
+		t.Errorf("Pinyin(端) = %v", got)
+	}
+	if got := Pinyin('女'); !reflect.DeepEqual(got, []string{"nv"}) {
+		t.Errorf("Pinyin(女) = %v", got)
+	}
+	if got := Pinyin('a'); got != nil {
+		t.Errorf("Pinyin(a) = %v, want nil", got)
+	}
+}
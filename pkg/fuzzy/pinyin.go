@@ -0,0 +1,39 @@
+package fuzzy
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// pinyinData holds the toneless readings of the GB2312 characters: one
+// syllable per line followed by its characters. It was derived from the
+// Unicode CLDR pinyin collation order; "ü" is written "v" as pinyin input
+// methods do. A few common second readings (行 hang, 长 chang, ...) are
+// listed as well.
+//
+//go:embed pinyin.txt
+var pinyinData string
+
+var (
+	pinyinOnce  sync.Once
+	pinyinTable map[rune][]string
+)
+
+// Pinyin returns the toneless pinyin readings of r, nil when r is not a
+// known Han character
+func Pinyin(r rune) []string {
+	pinyinOnce.Do(func() {
+		pinyinTable = make(map[rune][]string, 7000)
+		for _, line := range strings.Split(pinyinData, "\n") {
+			syllable, chars, ok := strings.Cut(line, " ")
+			if !ok {
+				continue
+			}
+			for _, c := range chars {
+				pinyinTable[c] = append(pinyinTable[c], syllable)
+			}
+		}
+	})
+	return pinyinTable[r]
+}
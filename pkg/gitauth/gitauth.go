@@ -0,0 +1,283 @@
+// Package gitauth finds credentials for private repositories and keeps them
+// out of error messages.
+//
+// Credentials are looked up per repository, first in
+// ~/.config/skills-x/credentials.yaml, then in environment variables:
+//
+//	# credentials.yaml: keys are a host or a host/path prefix, longest wins
+//	github.com:
+//	  token_env: GITHUB_TOKEN
+//	git.example.com/platform:
+//	  username: deploy
+//	  token: glpat-xxxx
+//
+//	SKILLS_X_TOKEN_GIT_EXAMPLE_COM=glpat-xxxx   # host, upper-cased, non-alphanumerics as _
+//	GITHUB_TOKEN / GH_TOKEN                     # github.com only
+//	GITLAB_TOKEN                                # gitlab.com only
+//
+// When none is configured, git's own credential helpers and SSH keys are used.
+package gitauth
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TokenEnvPrefix prefixes the per-host token variables
+const TokenEnvPrefix = "SKILLS_X_TOKEN_"
+
+// Credential is a token for one host or repository prefix
+type Credential struct {
+	Username string `yaml:"username,omitempty"`  // Empty picks a username the host accepts with tokens
+	Token    string `yaml:"token,omitempty"`     // The token itself
+	TokenEnv string `yaml:"token_env,omitempty"` // Or the environment variable holding it
+}
+
+// FilePath returns the path of the credentials file
+func FilePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(configDir, "skills-x", "credentials.yaml")
+}
+
+// Lookup returns the configured credential for a repository URL, or nil when
+// there is none. repo may be an https, ssh or scp-like (git@host:org/repo)
+// URL, or a bare github.com/owner/repo path.
+func Lookup(repo string) *Credential {
+	host, path := Split(repo)
+	if host == "" {
+		return nil
+	}
+	if c := lookupFile(host, path); c != nil {
+		return c
+	}
+	if tok := os.Getenv(HostEnv(host)); tok != "" {
+		return remember(&Credential{Token: tok})
+	}
+	var fallbacks []string
+	switch host {
+	case "github.com":
+		fallbacks = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+	case "gitlab.com":
+		fallbacks = []string{"GITLAB_TOKEN"}
+	}
+	for _, name := range fallbacks {
+		if tok := os.Getenv(name); tok != "" {
+			return remember(&Credential{Token: tok})
+		}
+	}
+	return nil
+}
+
+// lookupFile returns the credentials file entry whose key is the longest
+// prefix of host/path
+func lookupFile(host, path string) *Credential {
+	data, err := os.ReadFile(FilePath())
+	if err != nil {
+		return nil
+	}
+	var entries map[string]*Credential
+	if err := yaml.Unmarshal(data, &entries); err != nil {
+		return nil
+	}
+	target := strings.ToLower(host + "/" + path)
+	var best *Credential
+	bestLen := -1
+	for key, c := range entries {
+		if c == nil {
+			continue
+		}
+		k := strings.ToLower(strings.Trim(key, "/"))
+		if target != k && !strings.HasPrefix(target, k+"/") {
+			continue
+		}
+		if len(k) > bestLen {
+			best, bestLen = c, len(k)
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	c := *best
+	if c.Token == "" && c.TokenEnv != "" {
+		c.Token = os.Getenv(c.TokenEnv)
+	}
+	if c.Token == "" {
+		return nil
+	}
+	return remember(&c)
+}
+
+// HostEnv returns the token variable for host: "git.example.com:8443"
+// becomes SKILLS_X_TOKEN_GIT_EXAMPLE_COM
+func HostEnv(host string) string {
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	var b strings.Builder
+	b.WriteString(TokenEnvPrefix)
+	for _, r := range strings.ToUpper(host) {
+		if r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// Split returns the host (with any port) and the repository path of a
+// repository, archive or raw file URL. GitHub's archive, API and raw file
+// hosts map back to github.com and the owner/repo path.
+func Split(repo string) (host, path string) {
+	repo = strings.TrimSpace(repo)
+	switch {
+	case strings.Contains(repo, "://"):
+		u, err := url.Parse(repo)
+		if err != nil {
+			return "", ""
+		}
+		host, path = u.Host, u.Path
+	case IsSCP(repo):
+		at := strings.Index(repo, "@")
+		colon := strings.Index(repo[at+1:], ":") + at + 1
+		host, path = repo[at+1:colon], repo[colon+1:]
+	default:
+		parts := strings.SplitN(repo, "/", 2)
+		if len(parts) < 2 || !strings.Contains(parts[0], ".") || strings.HasPrefix(parts[0], ".") {
+			return "", ""
+		}
+		host, path = parts[0], parts[1]
+	}
+	host = strings.ToLower(host)
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+
+	switch host {
+	case "codeload.github.com":
+		host, path = "github.com", firstSegments(path, 2)
+	case "api.github.com":
+		host, path = "github.com", firstSegments(strings.TrimPrefix(path, "repos/"), 2)
+	case "raw.githubusercontent.com":
+		host, path = "github.com", firstSegments(path, 2)
+	}
+	return host, path
+}
+
+// IsSCP reports whether repo is an scp-like SSH address such as
+// git@github.com:org/repo.git
+func IsSCP(repo string) bool {
+	if strings.Contains(repo, "://") {
+		return false
+	}
+	at := strings.Index(repo, "@")
+	if at <= 0 {
+		return false
+	}
+	rest := repo[at+1:]
+	colon := strings.Index(rest, ":")
+	slash := strings.Index(rest, "/")
+	return colon > 0 && (slash < 0 || colon < slash) && colon < len(rest)-1
+}
+
+// IsSSH reports whether repo is cloned over SSH
+func IsSSH(repo string) bool {
+	return IsSCP(repo) || strings.HasPrefix(repo, "ssh://") || strings.HasPrefix(repo, "git+ssh://")
+}
+
+func firstSegments(path string, n int) string {
+	parts := strings.Split(path, "/")
+	if len(parts) > n {
+		parts = parts[:n]
+	}
+	return strings.Join(parts, "/")
+}
+
+// username returns the username to pair with c's token on host
+func (c *Credential) username(host string) string {
+	if c.Username != "" {
+		return c.Username
+	}
+	if isGitLab(host) {
+		return "oauth2"
+	}
+	return "x-access-token"
+}
+
+func isGitLab(host string) bool {
+	return strings.Contains(host, "gitlab")
+}
+
+// GitEnv returns the environment for a git command that talks to gitURL:
+// prompts are disabled so a missing credential fails instead of hanging, and
+// a configured token is sent as an HTTP header through GIT_CONFIG_* variables,
+// so it is never written to the clone's .git/config or shown in its remote URL.
+func GitEnv(gitURL string) []string {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	u, err := url.Parse(gitURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") {
+		return env
+	}
+	c := Lookup(gitURL)
+	if c == nil {
+		return env
+	}
+	host := strings.ToLower(u.Host)
+	basic := base64.StdEncoding.EncodeToString([]byte(c.username(host) + ":" + c.Token))
+	remember(&Credential{Token: basic})
+
+	n := 0
+	if v := os.Getenv("GIT_CONFIG_COUNT"); v != "" {
+		fmt.Sscanf(v, "%d", &n)
+	}
+	return append(env,
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=http.%s://%s/.extraHeader", n, u.Scheme, u.Host),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=Authorization: Basic %s", n, basic),
+		fmt.Sprintf("GIT_CONFIG_COUNT=%d", n+1),
+	)
+}
+
+// FromHelper asks git's configured credential helpers for rawURL's
+// credentials, as `git credential fill` does, without prompting. It returns
+// nil when git is missing or no helper knows the host.
+func FromHelper(rawURL string) *Credential {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	input := fmt.Sprintf("protocol=%s\nhost=%s\npath=%s\n\n", u.Scheme, u.Host, strings.TrimPrefix(u.Path, "/"))
+	cmd := exec.Command("git", "credential", "fill")
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS=")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return nil
+	}
+	c := &Credential{}
+	for _, line := range strings.Split(out.String(), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "username":
+			c.Username = value
+		case "password":
+			c.Token = value
+		}
+	}
+	if c.Token == "" {
+		return nil
+	}
+	return remember(c)
+}
@@ -0,0 +1,76 @@
+package gitauth
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Authorize adds c to req, an archive or file download, in the form req's
+// host expects. GitHub only serves private archives through its API, so those
+// requests are rewritten to the equivalent API tarball URL. A nil c returns
+// req unchanged.
+func Authorize(req *http.Request, c *Credential) *http.Request {
+	if c == nil {
+		return req
+	}
+	host := strings.ToLower(req.URL.Host)
+
+	switch {
+	case host == "github.com" || host == "codeload.github.com":
+		if api := githubAPIArchive(req.URL); api != nil {
+			req = req.Clone(req.Context())
+			req.URL, req.Host = api, api.Host
+		}
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	case host == "api.github.com":
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	case isGitLab(host) && c.Username == "":
+		req.Header.Set("PRIVATE-TOKEN", c.Token)
+	case c.Username != "":
+		req.SetBasicAuth(c.Username, c.Token)
+	default:
+		// Gitea, Forgejo and most other forges accept token auth
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+	return req
+}
+
+// HelperURL returns the URL git credential helpers know repo by, for
+// FromHelper
+func HelperURL(repo string) string {
+	if strings.HasPrefix(repo, "https://") || strings.HasPrefix(repo, "http://") {
+		u, err := url.Parse(repo)
+		if err != nil {
+			return ""
+		}
+		host, path := Split(repo)
+		return u.Scheme + "://" + host + "/" + path
+	}
+	host, path := Split(repo)
+	if host == "" {
+		return ""
+	}
+	return "https://" + host + "/" + path
+}
+
+// githubAPIArchive maps a github.com or codeload.github.com tarball URL to
+// the API URL serving the same archive, or nil for other URLs
+func githubAPIArchive(u *url.URL) *url.URL {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	var owner, repo, ref string
+	switch {
+	// codeload.github.com/owner/repo/tar.gz/ref
+	case u.Host == "codeload.github.com" && len(parts) >= 4 && parts[2] == "tar.gz":
+		owner, repo, ref = parts[0], parts[1], strings.Join(parts[3:], "/")
+	// github.com/owner/repo/archive/ref.tar.gz
+	case len(parts) >= 4 && parts[2] == "archive" && strings.HasSuffix(u.Path, ".tar.gz"):
+		owner, repo = parts[0], parts[1]
+		ref = strings.TrimSuffix(strings.Join(parts[3:], "/"), ".tar.gz")
+		ref = strings.TrimPrefix(ref, "refs/heads/")
+		ref = strings.TrimPrefix(ref, "refs/tags/")
+	default:
+		return nil
+	}
+	return &url.URL{Scheme: "https", Host: "api.github.com", Path: "/repos/" + owner + "/" + repo + "/tarball/" + ref}
+}
@@ -0,0 +1,277 @@
+// Package githost parses repository references on the git hosts skills-x
+// knows: GitHub, GitLab (including nested groups), Gitea and Forgejo,
+// Bitbucket, and any other host serving https://host/path.git. Besides
+// clone URLs it understands each host's browser URLs, so a link copied from
+// a file tree names the repository, ref and directory it points at.
+package githost
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/castle-x/skills-x/pkg/gitauth"
+)
+
+// Kind is the software a host runs
+type Kind string
+
+const (
+	GitHub    Kind = "github"
+	GitLab    Kind = "gitlab"
+	Gitea     Kind = "gitea" // Gitea and Forgejo, Codeberg included
+	Bitbucket Kind = "bitbucket"
+	Generic   Kind = "git" // Any other host; only clone URLs are understood
+)
+
+// Repo is a repository on a git host
+type Repo struct {
+	Kind   Kind
+	Scheme string // "https", "http" or "ssh"
+	Host   string // Lower-cased, with any port
+	Path   string // Without .git: "owner/repo", or "group/sub/project" on GitLab
+	SSH    string // The address as given, for repositories cloned over SSH
+
+	// dotGit records that a generic URL ended in .git, which such hosts
+	// may need to find the repository
+	dotGit bool
+}
+
+// Location is a repository plus what a browser URL pointed at inside it
+type Location struct {
+	Repo
+	Ref string // Branch, tag or commit of a tree or blob URL
+	Dir string // Path inside the repository
+}
+
+// Parse reads a repository reference: an https or http URL, an SSH URL
+// (git@host:path or ssh://), or a bare host/path such as
+// "gitlab.com/group/sub/project". Browser URLs are accepted as well:
+//
+//	https://github.com/owner/repo/tree/main/skills/pdf
+//	https://gitlab.com/group/sub/project/-/tree/main/skills/pdf
+//	https://codeberg.org/owner/repo/src/branch/main/skills/pdf
+//	https://bitbucket.org/workspace/repo/src/main/skills/pdf
+//
+// For GitHub, Gitea and Bitbucket, path segments after owner/repo that are
+// not a tree URL are returned as Dir. It reports false for anything that is
+// not a remote repository, such as a local path or "owner/repo" shorthand.
+func Parse(input string) (Location, bool) {
+	s := strings.TrimSpace(input)
+	if gitauth.IsSSH(s) {
+		host, path := gitauth.Split(s)
+		if host == "" || path == "" {
+			return Location{}, false
+		}
+		kind := hostKind(hostname(host))
+		if kind == "" {
+			kind = Generic
+		}
+		return Location{Repo: Repo{Kind: kind, Scheme: "ssh", Host: host, Path: path, SSH: strings.TrimRight(s, "/")}}, true
+	}
+
+	scheme, host, rest := "https", "", ""
+	if strings.Contains(s, "://") {
+		u, err := url.Parse(s)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return Location{}, false
+		}
+		scheme, host, rest = u.Scheme, u.Host, u.Path
+	} else {
+		first, after, ok := strings.Cut(s, "/")
+		if !ok || !strings.Contains(first, ".") || strings.HasPrefix(first, ".") {
+			return Location{}, false
+		}
+		host, rest = first, after
+	}
+	host = strings.ToLower(host)
+
+	var segs []string
+	for _, seg := range strings.Split(rest, "/") {
+		if seg != "" {
+			segs = append(segs, seg)
+		}
+	}
+	kind := hostKind(hostname(host))
+	if kind == "" {
+		kind = markerKind(segs)
+	}
+	loc, ok := split(kind, segs)
+	if !ok {
+		return Location{}, false
+	}
+	if kind == GitHub {
+		scheme = "https" // GitHub only serves HTTPS
+	}
+	loc.Kind, loc.Scheme, loc.Host = kind, scheme, host
+	return loc, true
+}
+
+// split divides path segments into the repository path and, for browser
+// URLs, the ref and directory
+func split(kind Kind, segs []string) (Location, bool) {
+	var loc Location
+	var rest []string
+	switch kind {
+	case GitLab:
+		// Groups nest, so the repository ends at the "-" separator of web
+		// URLs, at a .git suffix, or at the end of the path
+		end := len(segs)
+		for i, seg := range segs {
+			if seg == "-" || strings.HasSuffix(seg, ".git") {
+				end = i
+				if seg != "-" {
+					end++
+				}
+				break
+			}
+		}
+		loc.Path, rest = joinRepo(segs[:end]), segs[end:]
+		if len(rest) > 0 && rest[0] == "-" {
+			rest = rest[1:]
+			if len(rest) >= 2 && (rest[0] == "tree" || rest[0] == "blob") {
+				loc.Ref, rest = rest[1], rest[2:]
+			} else {
+				rest = nil
+			}
+		}
+	case Generic:
+		end := len(segs)
+		for i, seg := range segs {
+			if strings.HasSuffix(seg, ".git") {
+				end = i + 1
+				loc.dotGit = true
+				break
+			}
+		}
+		loc.Path, rest = joinRepo(segs[:end]), segs[end:]
+	default:
+		if len(segs) < 2 {
+			return loc, false
+		}
+		loc.Path, rest = joinRepo(segs[:2]), segs[2:]
+		switch {
+		case kind == GitHub && len(rest) >= 2 && (rest[0] == "tree" || rest[0] == "blob"):
+			loc.Ref, rest = rest[1], rest[2:]
+		case kind == Gitea && len(rest) >= 3 && (rest[0] == "src" || rest[0] == "raw") &&
+			(rest[1] == "branch" || rest[1] == "tag" || rest[1] == "commit"):
+			loc.Ref, rest = rest[2], rest[3:]
+		case kind == Bitbucket && len(rest) >= 2 && rest[0] == "src":
+			loc.Ref, rest = rest[1], rest[2:]
+		}
+	}
+	if loc.Path == "" {
+		return loc, false
+	}
+	loc.Dir = strings.Join(rest, "/")
+	return loc, true
+}
+
+func joinRepo(segs []string) string {
+	return strings.TrimSuffix(strings.Join(segs, "/"), ".git")
+}
+
+// hostKind recognizes well-known hosts and hosts named after their
+// software, returning "" for others
+func hostKind(host string) Kind {
+	switch {
+	case host == "github.com" || host == "www.github.com":
+		return GitHub
+	case host == "bitbucket.org" || host == "www.bitbucket.org":
+		return Bitbucket
+	case host == "codeberg.org" || strings.Contains(host, "gitea") || strings.Contains(host, "forgejo"):
+		return Gitea
+	case strings.Contains(host, "gitlab"):
+		return GitLab
+	}
+	return ""
+}
+
+// markerKind recognizes self-hosted GitLab and Gitea instances by the shape
+// of their browser URLs
+func markerKind(segs []string) Kind {
+	for i, seg := range segs {
+		if seg == "-" && i+1 < len(segs) && (segs[i+1] == "tree" || segs[i+1] == "blob") {
+			return GitLab
+		}
+		if seg == "src" && i == 2 && i+1 < len(segs) &&
+			(segs[i+1] == "branch" || segs[i+1] == "tag" || segs[i+1] == "commit") {
+			return Gitea
+		}
+	}
+	return Generic
+}
+
+// hostname strips the port from host
+func hostname(host string) string {
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host[i:], "]") {
+		return host[:i]
+	}
+	return host
+}
+
+// Canonical returns the repo as a registry entry writes it: the address as
+// given for SSH, github.com/owner/repo for GitHub, and a full URL otherwise
+func (r Repo) Canonical() string {
+	switch {
+	case r.SSH != "":
+		return r.SSH
+	case r.Kind == GitHub:
+		return "github.com/" + r.Path
+	}
+	return r.WebURL() + r.suffix()
+}
+
+// CloneURL returns the URL to clone the repository from
+func (r Repo) CloneURL() string {
+	if r.SSH != "" {
+		return r.SSH
+	}
+	if r.Kind == Generic {
+		return r.WebURL() + r.suffix()
+	}
+	return r.WebURL() + ".git"
+}
+
+func (r Repo) suffix() string {
+	if r.dotGit {
+		return ".git"
+	}
+	return ""
+}
+
+// WebURL returns the repository's page. SSH repositories are assumed to be
+// served over HTTPS on the same host name.
+func (r Repo) WebURL() string {
+	if r.SSH != "" {
+		return "https://" + hostname(r.Host) + "/" + r.Path
+	}
+	return r.Scheme + "://" + r.Host + "/" + r.Path
+}
+
+// ShortName returns a display name: owner/repo on GitHub, host/path elsewhere
+func (r Repo) ShortName() string {
+	if r.Kind == GitHub {
+		return r.Path
+	}
+	return hostname(r.Host) + "/" + r.Path
+}
+
+// Name returns the last segment of the repository path
+func (r Repo) Name() string {
+	return r.Path[strings.LastIndex(r.Path, "/")+1:]
+}
+
+// ArchiveURL returns the URL of the host's source tarball of ref, or ""
+// for generic hosts, which have no standard archive URL
+func (r Repo) ArchiveURL(ref string) string {
+	web := r.WebURL()
+	switch r.Kind {
+	case GitHub, Gitea:
+		return web + "/archive/" + ref + ".tar.gz"
+	case GitLab:
+		return web + "/-/archive/" + ref + "/" + r.Name() + "-" + strings.ReplaceAll(ref, "/", "-") + ".tar.gz"
+	case Bitbucket:
+		return web + "/get/" + ref + ".tar.gz"
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package githost
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input     string
+		kind      Kind
+		canonical string
+		clone     string
+		ref, dir  string
+	}{
+		// GitHub
+		{"github.com/anthropics/skills", GitHub, "github.com/anthropics/skills", "https://github.com/anthropics/skills.git", "", ""},
+		{"https://github.com/anthropics/skills.git", GitHub, "github.com/anthropics/skills", "https://github.com/anthropics/skills.git", "", ""},
+		{"https://github.com/anthropics/skills/tree/main/skills/pdf", GitHub, "github.com/anthropics/skills", "https://github.com/anthropics/skills.git", "main", "skills/pdf"},
+		{"https://github.com/anthropics/skills/blob/v1.0/skills/pdf/SKILL.md", GitHub, "github.com/anthropics/skills", "https://github.com/anthropics/skills.git", "v1.0", "skills/pdf/SKILL.md"},
+		{"github.com/anthropics/skills/pdf", GitHub, "github.com/anthropics/skills", "https://github.com/anthropics/skills.git", "", "pdf"},
+		{"git@github.com:anthropics/skills.git", GitHub, "git@github.com:anthropics/skills.git", "git@github.com:anthropics/skills.git", "", ""},
+
+		// GitLab, nested groups included
+		{"https://gitlab.com/group/sub/project", GitLab, "https://gitlab.com/group/sub/project", "https://gitlab.com/group/sub/project.git", "", ""},
+		{"gitlab.com/group/sub/project.git", GitLab, "https://gitlab.com/group/sub/project", "https://gitlab.com/group/sub/project.git", "", ""},
+		{"https://gitlab.com/group/sub/project/-/tree/main/skills/pdf?ref_type=heads", GitLab, "https://gitlab.com/group/sub/project", "https://gitlab.com/group/sub/project.git", "main", "skills/pdf"},
+		{"https://gitlab.com/group/project/-/blob/dev/SKILL.md", GitLab, "https://gitlab.com/group/project", "https://gitlab.com/group/project.git", "dev", "SKILL.md"},
+		{"https://gitlab.com/group/project/-/merge_requests/3", GitLab, "https://gitlab.com/group/project", "https://gitlab.com/group/project.git", "", ""},
+		{"https://code.example.com/a/b/c/-/tree/main/skills", GitLab, "https://code.example.com/a/b/c", "https://code.example.com/a/b/c.git", "main", "skills"},
+
+		// Gitea and Forgejo
+		{"https://codeberg.org/me/skills/src/branch/main/skills/pdf", Gitea, "https://codeberg.org/me/skills", "https://codeberg.org/me/skills.git", "main", "skills/pdf"},
+		{"https://git.example.com/me/skills/src/tag/v2/pdf", Gitea, "https://git.example.com/me/skills", "https://git.example.com/me/skills.git", "v2", "pdf"},
+		{"https://gitea.example.com/me/skills", Gitea, "https://gitea.example.com/me/skills", "https://gitea.example.com/me/skills.git", "", ""},
+
+		// Bitbucket
+		{"https://bitbucket.org/team/skills/src/main/skills/pdf/", Bitbucket, "https://bitbucket.org/team/skills", "https://bitbucket.org/team/skills.git", "main", "skills/pdf"},
+		{"https://user@bitbucket.org/team/skills.git", Bitbucket, "https://bitbucket.org/team/skills", "https://bitbucket.org/team/skills.git", "", ""},
+
+		// Anything else serving git over HTTP
+		{"https://git.example.com/scm/team/skills.git", Generic, "https://git.example.com/scm/team/skills.git", "https://git.example.com/scm/team/skills.git", "", ""},
+		{"http://127.0.0.1:8080/private.git/skills/pdf", Generic, "http://127.0.0.1:8080/private.git", "http://127.0.0.1:8080/private.git", "", "skills/pdf"},
+		{"https://git.example.com/team/skills", Generic, "https://git.example.com/team/skills", "https://git.example.com/team/skills", "", ""},
+	}
+	for _, tt := range tests {
+		loc, ok := Parse(tt.input)
+		if !ok {
+			t.Errorf("Parse(%q) failed", tt.input)
+			continue
+		}
+		if loc.Kind != tt.kind || loc.Canonical() != tt.canonical || loc.CloneURL() != tt.clone || loc.Ref != tt.ref || loc.Dir != tt.dir {
+			t.Errorf("Parse(%q) = %s %s %s ref=%q dir=%q; want %s %s %s ref=%q dir=%q", tt.input,
+				loc.Kind, loc.Canonical(), loc.CloneURL(), loc.Ref, loc.Dir,
+				tt.kind, tt.canonical, tt.clone, tt.ref, tt.dir)
+		}
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, input := range []string{
+		"owner/repo",
+		"/srv/git/skills",
+		"./skills/pdf",
+		"~/skills",
+		"github.com/owner",
+		"ftp://example.com/repo.git",
+		"https://example.com",
+	} {
+		if loc, ok := Parse(input); ok {
+			t.Errorf("Parse(%q) = %+v; want failure", input, loc)
+		}
+	}
+}
+
+func TestRepoNames(t *testing.T) {
+	tests := []struct{ input, short, archive string }{
+		{"github.com/anthropics/skills", "anthropics/skills", "https://github.com/anthropics/skills/archive/v1.tar.gz"},
+		{"https://gitlab.com/group/sub/project", "gitlab.com/group/sub/project", "https://gitlab.com/group/sub/project/-/archive/v1/project-v1.tar.gz"},
+		{"git@codeberg.org:me/skills.git", "codeberg.org/me/skills", "https://codeberg.org/me/skills/archive/v1.tar.gz"},
+		{"https://bitbucket.org/team/skills", "bitbucket.org/team/skills", "https://bitbucket.org/team/skills/get/v1.tar.gz"},
+		{"https://git.example.com:8443/team/skills.git", "git.example.com/team/skills", ""},
+	}
+	for _, tt := range tests {
+		loc, _ := Parse(tt.input)
+		if got := loc.ShortName(); got != tt.short {
+			t.Errorf("ShortName(%q) = %q; want %q", tt.input, got, tt.short)
+		}
+		if got := loc.ArchiveURL("v1"); got != tt.archive {
+			t.Errorf("ArchiveURL(%q) = %q; want %q", tt.input, got, tt.archive)
+		}
+	}
+}
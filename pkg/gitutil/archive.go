@@ -0,0 +1,573 @@
+package gitutil
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/castle-x/skills-x/pkg/gitauth"
+	"github.com/castle-x/skills-x/pkg/githost"
+)
+
+// ErrNoHistory is returned for operations that need commit history on a
+// checkout made by the native backend, which only downloads a snapshot
+var ErrNoHistory = errors.New("commit history is not available without git")
+
+// errUnsafeArchive marks an archive that tries to write or link outside its
+// checkout. It is not retried.
+var errUnsafeArchive = errors.New("unsafe archive")
+
+// archiveFetcher is the pure-Go Fetcher. It downloads the source archive
+// that GitHub, GitLab, Gitea and Forgejo serve for any ref and extracts it
+// into the cache. The commit is read from the pax header git archive writes,
+// and tree hashes are computed locally, so checkouts look the same to callers
+// as git clones apart from having no history.
+type archiveFetcher struct {
+	client     *http.Client
+	retryDelay time.Duration
+}
+
+func newArchiveFetcher() *archiveFetcher {
+	return &archiveFetcher{
+		client:     &http.Client{Timeout: CloneTimeout},
+		retryDelay: RetryDelay,
+	}
+}
+
+func (f *archiveFetcher) Name() string { return BackendNative }
+
+// archiveRecord describes an archive checkout. It is stored next to the
+// checkout directory rather than inside it, so it is never copied along
+// with a skill.
+type archiveRecord struct {
+	URL     string   `json:"url"`
+	Archive string   `json:"archive,omitempty"` // Set when downloaded from an explicit archive URL
+	Repo    string   `json:"repo"`
+	Ref     string   `json:"ref"`
+	Commit  string   `json:"commit,omitempty"`
+	Paths   []string `json:"paths,omitempty"`
+}
+
+// archiveRecordPath returns the record file of the checkout in dir. It
+// shares the checkout's name prefix, so CleanupAllSkillsDirs removes it too.
+func archiveRecordPath(dir string) string {
+	return filepath.Clean(dir) + ".fetch.json"
+}
+
+// readArchiveRecord loads the record of an archive checkout
+func readArchiveRecord(dir string) (*archiveRecord, error) {
+	data, err := os.ReadFile(archiveRecordPath(dir))
+	if os.IsNotExist(err) || !dirExists(dir) {
+		return nil, fmt.Errorf("%s is neither a git clone nor an archive checkout", dir)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rec archiveRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("invalid fetch record for %s: %w", dir, err)
+	}
+	return &rec, nil
+}
+
+func (f *archiveFetcher) Clone(gitURL string, repoName string, branch string, refresh bool) (*CloneResult, error) {
+	ref := branch
+	if ref == "" {
+		ref = "HEAD"
+	}
+	dirs := []string{getTempDir(repoName + branchSuffix(branch)), getUserTempDir(repoName + branchSuffix(branch))}
+	src := archiveRecord{URL: gitURL, Repo: repoName, Ref: ref}
+	return f.checkout(src, dirs, func(*archiveRecord) bool { return !refresh })
+}
+
+func (f *archiveFetcher) SparseClone(gitURL string, repoName string, branch string, sparsePaths []string) (*CloneResult, error) {
+	ref := branch
+	if ref == "" {
+		ref = "HEAD"
+	}
+	dirs := []string{getTempDirSparse(repoName+branchSuffix(branch), sparsePaths), getUserTempDirSparse(repoName, sparsePaths)}
+	src := archiveRecord{URL: gitURL, Repo: repoName, Ref: ref, Paths: sparsePaths}
+	return f.checkout(src, dirs, func(*archiveRecord) bool { return true })
+}
+
+func (f *archiveFetcher) CloneAtCommit(gitURL string, repoName string, commit string) (*CloneResult, error) {
+	if commit == "" {
+		return nil, fmt.Errorf("commit is required")
+	}
+	dirs := []string{getTempDir(repoName + "#" + commit), getUserTempDir(repoName + "#" + commit)}
+	src := archiveRecord{URL: gitURL, Repo: repoName, Ref: commit}
+	return f.checkout(src, dirs, func(rec *archiveRecord) bool {
+		return strings.HasPrefix(rec.Commit, commit)
+	})
+}
+
+func (f *archiveFetcher) CloneAtRef(gitURL string, repoName string, ref string) (*CloneResult, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("ref is required")
+	}
+	if IsCommitSHA(ref) {
+		return f.CloneAtCommit(gitURL, repoName, ref)
+	}
+	dirs := []string{getTempDir(repoName + "#" + ref), getUserTempDir(repoName + "#" + ref)}
+	src := archiveRecord{URL: gitURL, Repo: repoName, Ref: ref}
+	return f.checkout(src, dirs, func(*archiveRecord) bool { return true })
+}
+
+// checkout returns the first cached checkout in dirs that reuse accepts, or
+// downloads src into dirs[0] (dirs[1] when dirs[0] cannot be replaced, e.g.
+// because another user owns it). The archive URL is derived from src.URL
+// unless src.Archive is set.
+func (f *archiveFetcher) checkout(src archiveRecord, dirs []string, reuse func(*archiveRecord) bool) (*CloneResult, error) {
+	for _, dir := range dirs {
+		if rec, err := readArchiveRecord(dir); err == nil && reuse(rec) {
+			return &CloneResult{TempDir: dir, Repo: src.Repo}, nil
+		}
+	}
+
+	archive := src.Archive
+	if archive == "" {
+		var err error
+		if archive, err = archiveURL(src.URL, src.Ref); err != nil {
+			return nil, &CloneError{URL: src.URL, Message: err.Error()}
+		}
+	}
+
+	dir := dirs[0]
+	if err := os.RemoveAll(dir); err != nil {
+		dir = dirs[1]
+		os.RemoveAll(dir)
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= MaxRetries; attempt++ {
+		commit, err := f.download(src.URL, archive, src.Paths, dir)
+		if err == nil {
+			rec := src
+			rec.Commit = commit
+			if commit == "" && len(src.Ref) == 40 && IsCommitSHA(src.Ref) {
+				rec.Commit = src.Ref
+			}
+			if err := writeArchiveRecord(dir, rec); err != nil {
+				os.RemoveAll(dir)
+				return nil, err
+			}
+			return &CloneResult{TempDir: dir, Repo: src.Repo}, nil
+		}
+
+		lastErr = err
+		if cloneErr, ok := err.(*CloneError); (ok && cloneErr.permanent()) || errors.Is(err, errUnsafeArchive) {
+			return nil, err
+		}
+		if attempt < MaxRetries {
+			time.Sleep(f.retryDelay)
+		}
+	}
+	return nil, lastErr
+}
+
+// download extracts the archive at archive into dir and returns the commit
+// it was made from. The archive is extracted into a staging directory first,
+// so a failed download never leaves a partial checkout behind.
+func (f *archiveFetcher) download(gitURL string, archive string, paths []string, dir string) (string, error) {
+	resp, err := f.get(gitURL, archive)
+	if err != nil {
+		return "", httpError(gitURL, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return "", &CloneError{
+			URL:         gitURL,
+			Message:     fmt.Sprintf("Authentication failed for %s. %s", gitURL, authHint(gitURL)),
+			IsAuthError: true,
+		}
+	case resp.StatusCode == http.StatusNotFound:
+		return "", &CloneError{
+			URL:        gitURL,
+			Message:    fmt.Sprintf("Repository or ref not found: %s. Private repositories also need credentials: %s", gitURL, authHint(gitURL)),
+			IsNotFound: true,
+		}
+	case resp.StatusCode != http.StatusOK:
+		return "", &CloneError{URL: gitURL, Message: fmt.Sprintf("Failed to download %s: HTTP %d", archive, resp.StatusCode)}
+	}
+
+	stage, err := os.MkdirTemp(filepath.Dir(dir), filepath.Base(dir)+".staging-")
+	if err != nil {
+		return "", err
+	}
+	commit, err := extractArchive(resp.Body, stage, paths)
+	if err != nil {
+		os.RemoveAll(stage)
+		if errors.Is(err, errUnsafeArchive) {
+			return "", fmt.Errorf("%s: %w", archive, err)
+		}
+		return "", httpError(gitURL, err)
+	}
+	os.RemoveAll(dir)
+	if err := os.Rename(stage, dir); err != nil {
+		os.RemoveAll(stage)
+		return "", err
+	}
+	return commit, nil
+}
+
+// get requests archive with the credentials configured for gitURL. When
+// none are configured and the host refuses the request (private GitHub
+// repositories answer 404), it is retried with what git's credential
+// helpers know.
+func (f *archiveFetcher) get(gitURL string, archive string) (*http.Response, error) {
+	cred := gitauth.Lookup(gitURL)
+	resp, err := f.send(archive, cred)
+	if err != nil || cred != nil || !refused(resp.StatusCode) {
+		return resp, err
+	}
+	helper := gitauth.FromHelper(gitauth.HelperURL(gitURL))
+	if helper == nil {
+		return resp, nil
+	}
+	resp.Body.Close()
+	return f.send(archive, helper)
+}
+
+func (f *archiveFetcher) send(archive string, cred *gitauth.Credential) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, archive, nil)
+	if err != nil {
+		return nil, err
+	}
+	return f.client.Do(gitauth.Authorize(req, cred))
+}
+
+func refused(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusNotFound
+}
+
+// httpError wraps a transport or read failure, flagging timeouts
+func httpError(gitURL string, err error) *CloneError {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return &CloneError{
+			URL:       gitURL,
+			Message:   fmt.Sprintf("Download timed out after %v. Check your network.", CloneTimeout),
+			IsTimeout: true,
+		}
+	}
+	return &CloneError{URL: gitURL, Message: fmt.Sprintf("Failed to download %s: %v", gitURL, err)}
+}
+
+func writeArchiveRecord(dir string, rec archiveRecord) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(archiveRecordPath(dir), data, 0644)
+}
+
+// extractArchive unpacks an archive made by git archive into dest, dropping
+// the top-level "<repo>-<ref>/" directory. Gzipped tarballs are streamed;
+// zip archives need random access and are spooled to a temporary file first.
+// When paths is non-empty only entries under those paths are written. It
+// returns the commit git archive recorded ("" if there is none).
+func extractArchive(r io.Reader, dest string, paths []string) (string, error) {
+	br := bufio.NewReader(r)
+	extract := extractTarGz
+	if magic, _ := br.Peek(4); bytes.Equal(magic, []byte("PK\x03\x04")) {
+		extract = extractZip
+	}
+	commit, err := extract(br, dest, paths)
+	if err != nil {
+		return "", err
+	}
+	return commit, checkArchiveLinks(dest)
+}
+
+// extractTarGz unpacks a gzipped tarball; the commit is in the pax global header
+func extractTarGz(r io.Reader, dest string, paths []string) (string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return "", fmt.Errorf("invalid archive: %w", err)
+	}
+	defer gz.Close()
+
+	var commit string
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return commit, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeXGlobalHeader:
+			if c := hdr.PAXRecords["comment"]; len(c) == 40 && IsCommitSHA(c) {
+				commit = c
+			}
+		case tar.TypeDir:
+			err = writeArchiveEntry(dest, hdr.Name, paths, os.ModeDir, "", nil)
+		case tar.TypeReg:
+			err = writeArchiveEntry(dest, hdr.Name, paths, os.FileMode(hdr.Mode)&0777, "", tr)
+		case tar.TypeSymlink:
+			err = writeArchiveEntry(dest, hdr.Name, paths, os.ModeSymlink, hdr.Linkname, nil)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// extractZip unpacks a zip archive; the commit is in the archive comment
+func extractZip(r io.Reader, dest string, paths []string) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".archive-*.zip")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		return "", err
+	}
+	zr, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return "", fmt.Errorf("invalid archive: %w", err)
+	}
+
+	for _, f := range zr.File {
+		mode := f.Mode()
+		var link string
+		var body io.ReadCloser
+		switch {
+		case mode.IsDir():
+			mode = os.ModeDir
+		case mode&os.ModeSymlink != 0:
+			rc, err := f.Open()
+			if err != nil {
+				return "", err
+			}
+			target, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return "", err
+			}
+			mode, link = os.ModeSymlink, string(target)
+		case mode.IsRegular():
+			if body, err = f.Open(); err != nil {
+				return "", err
+			}
+		default:
+			continue
+		}
+		err := writeArchiveEntry(dest, f.Name, paths, mode, link, body)
+		if body != nil {
+			body.Close()
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	commit := strings.TrimSpace(zr.Comment)
+	if len(commit) != 40 || !IsCommitSHA(commit) {
+		commit = ""
+	}
+	return commit, nil
+}
+
+// writeArchiveEntry writes one archive entry below dest. mode is os.ModeDir,
+// os.ModeSymlink (with link as the target) or the permission bits of a
+// regular file whose content is read from body. Entries outside paths are
+// skipped. Entries escaping dest, symlinks pointing out of it and entries
+// written through a symlink are rejected.
+func writeArchiveEntry(dest string, name string, paths []string, mode os.FileMode, link string, body io.Reader) error {
+	rel := archiveEntryPath(name)
+	if rel == "" || !inSparsePaths(rel, paths) {
+		return nil
+	}
+	if escapesDir(rel) {
+		return fmt.Errorf("%w: entry escapes the checkout: %s", errUnsafeArchive, name)
+	}
+	if mode&os.ModeSymlink != 0 {
+		l := filepath.ToSlash(link)
+		if path.IsAbs(l) || filepath.IsAbs(link) || filepath.VolumeName(link) != "" || escapesDir(path.Join(path.Dir(rel), l)) {
+			return fmt.Errorf("%w: symlink points outside the checkout: %s -> %s", errUnsafeArchive, name, link)
+		}
+	}
+	// A symlink extracted earlier must not redirect this entry
+	for p := rel; p != "."; p = path.Dir(p) {
+		info, err := os.Lstat(filepath.Join(dest, filepath.FromSlash(p)))
+		if err == nil && info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%w: entry is written through a symlink: %s", errUnsafeArchive, name)
+		}
+	}
+	target := filepath.Join(dest, filepath.FromSlash(rel))
+
+	switch {
+	case mode&os.ModeDir != 0:
+		return os.MkdirAll(target, 0755)
+	case mode&os.ModeSymlink != 0:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.Symlink(link, target); err != nil {
+			// No symlink support (e.g. Windows without developer mode):
+			// keep the link text, as git does with core.symlinks=false
+			return os.WriteFile(target, []byte(link), 0644)
+		}
+		return nil
+	}
+
+	perm := os.FileMode(0644)
+	if mode&0111 != 0 {
+		perm = 0755
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, body); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// escapesDir reports whether a cleaned slash-separated relative path leaves
+// the directory it is relative to
+func escapesDir(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, "../")
+}
+
+// checkArchiveLinks rejects an extracted checkout holding a symlink that
+// resolves outside dest. Each link was checked as written, but a link can
+// still lead out through another one (a -> "..", b -> "a/.."). Dangling
+// links are left alone; nothing follows them.
+func checkArchiveLinks(dest string) error {
+	root, err := filepath.EvalSymlinks(dest)
+	if err != nil {
+		return err
+	}
+	return filepath.WalkDir(dest, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.Type()&os.ModeSymlink == 0 {
+			return err
+		}
+		resolved, err := filepath.EvalSymlinks(p)
+		if err != nil {
+			return nil
+		}
+		if rel, err := filepath.Rel(root, resolved); err != nil || escapesDir(filepath.ToSlash(rel)) {
+			rel, _ := filepath.Rel(dest, p)
+			return fmt.Errorf("%w: symlink points outside the checkout: %s", errUnsafeArchive, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+}
+
+// archiveEntryPath strips the top-level directory from an entry name and
+// cleans it; it returns "" for the top-level directory itself
+func archiveEntryPath(name string) string {
+	name = strings.TrimPrefix(name, "./")
+	i := strings.Index(name, "/")
+	if i < 0 {
+		return ""
+	}
+	rel := path.Clean(name[i+1:])
+	if rel == "." || rel == "/" {
+		return ""
+	}
+	return strings.TrimPrefix(rel, "/")
+}
+
+// inSparsePaths reports whether rel is inside one of paths (all paths when empty)
+func inSparsePaths(rel string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		p = strings.Trim(filepath.ToSlash(p), "/")
+		if p == "" || rel == p || strings.HasPrefix(rel, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveURL returns the source archive URL of ref for a repository URL,
+// in the form its host serves (see githost.Repo.ArchiveURL). Unknown hosts
+// are assumed to serve /archive/ like Gitea. SSH URLs are mapped to HTTPS
+// on the same host.
+func archiveURL(gitURL string, ref string) (string, error) {
+	loc, ok := githost.Parse(gitURL)
+	if !ok {
+		return "", fmt.Errorf("the native git backend cannot fetch %s; install git or use an https URL", gitURL)
+	}
+	if archive := loc.ArchiveURL(ref); archive != "" {
+		return archive, nil
+	}
+	return loc.WebURL() + "/archive/" + ref + ".tar.gz", nil
+}
+
+// readFileAtCommit reads path from the archive of commit, reusing the
+// per-commit cache of CloneAtCommit
+func (f *archiveFetcher) readFileAtCommit(rec *archiveRecord, commit string, p string) ([]byte, error) {
+	if rec.Archive != "" {
+		// An explicit archive URL cannot be pointed at another commit
+		return nil, ErrNoHistory
+	}
+	res, err := f.CloneAtCommit(rec.URL, rec.Repo, commit)
+	if err != nil {
+		return nil, err
+	}
+	p = strings.Trim(filepath.ToSlash(p), "/")
+	data, err := os.ReadFile(filepath.Join(res.TempDir, filepath.FromSlash(p)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", p, commit, err)
+	}
+	return data, nil
+}
+
+// FetchArchive downloads a source archive (a gzipped tarball or zip made by
+// git archive, such as codeload.github.com/<owner>/<repo>/tar.gz/<ref>) and
+// extracts only the entries under paths, all of them when paths is empty.
+// It works without git whichever backend is selected. ref names what the
+// archive was made from and is returned by ArchiveRef. A cached extraction
+// is reused unless refresh is set.
+func FetchArchive(archive string, repoName string, ref string, paths []string, refresh bool) (*CloneResult, error) {
+	key := append([]string{archive}, paths...)
+	dirs := []string{getTempDirSparse(repoName+"#archive", key), getUserTempDirSparse(repoName+"#archive", key)}
+	src := archiveRecord{URL: archive, Archive: archive, Repo: repoName, Ref: ref, Paths: paths}
+	return newArchiveFetcher().checkout(src, dirs, func(*archiveRecord) bool { return !refresh })
+}
+
+// ArchiveRef returns the ref an archive checkout was downloaded at, or ""
+// for git clones
+func ArchiveRef(repoDir string) string {
+	if hasGitContent(repoDir) {
+		return ""
+	}
+	rec, err := readArchiveRecord(repoDir)
+	if err != nil {
+		return ""
+	}
+	return rec.Ref
+}
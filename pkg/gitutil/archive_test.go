@@ -0,0 +1,420 @@
+package gitutil
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testCommit = "0123456789abcdef0123456789abcdef01234567"
+
+// makeArchive builds a tarball shaped like the ones git archive produces
+func makeArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	write := func(hdr *tar.Header, body string) {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		if body != "" {
+			if _, err := tw.Write([]byte(body)); err != nil {
+				t.Fatalf("write body: %v", err)
+			}
+		}
+	}
+	write(&tar.Header{
+		Typeflag:   tar.TypeXGlobalHeader,
+		Name:       "pax_global_header",
+		PAXRecords: map[string]string{"comment": testCommit},
+		Format:     tar.FormatPAX,
+	}, "")
+	write(&tar.Header{Typeflag: tar.TypeDir, Name: "repo-main/", Mode: 0755}, "")
+	for name, body := range files {
+		mode := int64(0644)
+		if filepath.Ext(name) == ".sh" {
+			mode = 0755
+		}
+		write(&tar.Header{Typeflag: tar.TypeReg, Name: "repo-main/" + name, Mode: mode, Size: int64(len(body))}, body)
+	}
+	write(&tar.Header{Typeflag: tar.TypeSymlink, Name: "repo-main/skills/pdf/README.md", Linkname: "SKILL.md"}, "")
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func newTestArchiveFetcher() *archiveFetcher {
+	f := newArchiveFetcher()
+	f.retryDelay = 0
+	return f
+}
+
+func TestArchiveFetcher_CloneAndSparse(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	archive := makeArchive(t, map[string]string{
+		"skills/pdf/SKILL.md":       "# pdf\n",
+		"skills/pdf/scripts/run.sh": "#!/bin/sh\n",
+		"skills/docx/SKILL.md":      "# docx\n",
+	})
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path != "/owner/repo/archive/HEAD.tar.gz" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(archive)
+	}))
+	defer srv.Close()
+
+	f := newTestArchiveFetcher()
+	gitURL := srv.URL + "/owner/repo.git"
+
+	res, err := f.Clone(gitURL, "owner/repo", "", false)
+	if err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(res.TempDir, "skills", "pdf", "SKILL.md")); err != nil || string(data) != "# pdf\n" {
+		t.Fatalf("SKILL.md = %q, %v", data, err)
+	}
+	if info, err := os.Stat(filepath.Join(res.TempDir, "skills", "pdf", "scripts", "run.sh")); err != nil || info.Mode()&0100 == 0 {
+		t.Fatalf("run.sh should be executable: %v %v", info, err)
+	}
+	if commit, err := GetRepoHeadCommit(res.TempDir); err != nil || commit != testCommit[:7] {
+		t.Fatalf("GetRepoHeadCommit = %q, %v", commit, err)
+	}
+	if _, err := GetTreeHash(res.TempDir, "skills/pdf"); err != nil {
+		t.Fatalf("GetTreeHash: %v", err)
+	}
+	if _, err := GetLogSubjects(res.TempDir, "abc1234", ""); !errors.Is(err, ErrNoHistory) {
+		t.Fatalf("GetLogSubjects error = %v; want ErrNoHistory", err)
+	}
+
+	// A cached checkout is reused without a request; refresh downloads again
+	if _, err := f.Clone(gitURL, "owner/repo", "", false); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Fatalf("cached clone made a request (%d total)", requests)
+	}
+	if _, err := f.Clone(gitURL, "owner/repo", "", true); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 {
+		t.Fatalf("refresh did not download again (%d total)", requests)
+	}
+
+	sparse, err := f.SparseClone(gitURL, "owner/repo", "", []string{"skills/docx"})
+	if err != nil {
+		t.Fatalf("SparseClone: %v", err)
+	}
+	if !dirExists(filepath.Join(sparse.TempDir, "skills", "docx")) || dirExists(filepath.Join(sparse.TempDir, "skills", "pdf")) {
+		t.Fatal("sparse checkout should contain only skills/docx")
+	}
+}
+
+func TestArchiveFetcher_TypedErrors(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/private/repo/archive/HEAD.tar.gz":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/slow/repo/archive/HEAD.tar.gz":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	f := newTestArchiveFetcher()
+	f.client.Timeout = 50 * time.Millisecond
+
+	tests := []struct {
+		repo string
+		want error
+	}{
+		{"private/repo", ErrAuth},
+		{"missing/repo", ErrNotFound},
+		{"slow/repo", ErrTimeout},
+	}
+	for _, tt := range tests {
+		_, err := f.Clone(srv.URL+"/"+tt.repo, tt.repo, "", false)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: error = %v; want %v", tt.repo, err, tt.want)
+		}
+	}
+}
+
+func TestArchiveURL(t *testing.T) {
+	tests := []struct {
+		gitURL string
+		ref    string
+		want   string
+	}{
+		{"https://github.com/anthropics/skills.git", "HEAD", "https://github.com/anthropics/skills/archive/HEAD.tar.gz"},
+		{"git@github.com:anthropics/skills.git", "v1.0", "https://github.com/anthropics/skills/archive/v1.0.tar.gz"},
+		{"ssh://git@codeberg.org/me/skills", "main", "https://codeberg.org/me/skills/archive/main.tar.gz"},
+		{"https://gitlab.com/group/skills", "main", "https://gitlab.com/group/skills/-/archive/main/skills-main.tar.gz"},
+	}
+	for _, tt := range tests {
+		got, err := archiveURL(tt.gitURL, tt.ref)
+		if err != nil || got != tt.want {
+			t.Errorf("archiveURL(%q, %q) = %q, %v; want %q", tt.gitURL, tt.ref, got, err, tt.want)
+		}
+	}
+	if _, err := archiveURL("/srv/git/skills", "HEAD"); err == nil {
+		t.Error("local paths should be rejected")
+	}
+}
+
+func TestArchiveEntryPath(t *testing.T) {
+	tests := map[string]string{
+		"repo-main/":            "",
+		"repo-main/SKILL.md":    "SKILL.md",
+		"repo-main/a/b/":        "a/b",
+		"repo-main/a/../../etc": "../etc",
+		"pax_global_header":     "",
+	}
+	for in, want := range tests {
+		if got := archiveEntryPath(in); got != want {
+			t.Errorf("archiveEntryPath(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
+
+// makeRawArchive builds a tarball from hand-written headers, for archives
+// git archive would never produce
+func makeRawArchive(t *testing.T, hdrs ...*tar.Header) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, hdr := range hdrs {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatalf("write header: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			tw.Write(make([]byte, hdr.Size))
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractArchive_RejectsEscapingLinks(t *testing.T) {
+	link := func(name, target string) *tar.Header {
+		return &tar.Header{Typeflag: tar.TypeSymlink, Name: "repo-main/" + name, Linkname: target}
+	}
+	file := func(name string) *tar.Header {
+		return &tar.Header{Typeflag: tar.TypeReg, Name: "repo-main/" + name, Mode: 0644, Size: 4}
+	}
+	tests := map[string][]*tar.Header{
+		"absolute link":         {link("skills/pdf/passwd", "/etc/passwd")},
+		"link out of checkout":  {link("skills/pdf/up", "../../..")},
+		"write through link":    {link("skills/lnk", "pdf"), file("skills/lnk/SKILL.md")},
+		"write through outlink": {link("skills/pdf/home", "../../.."), file("skills/pdf/home/.bashrc")},
+		"overwrite a link":      {link("skills/pdf/README.md", "SKILL.md"), file("skills/pdf/README.md")},
+		"chained links":         {link("a/b", ".."), link("c", "a/b/..")},
+	}
+	for name, hdrs := range tests {
+		parent := t.TempDir()
+		dest := filepath.Join(parent, "out")
+		if err := os.Mkdir(dest, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := extractArchive(bytes.NewReader(makeRawArchive(t, hdrs...)), dest, nil); err == nil {
+			t.Errorf("%s: extractArchive succeeded; want an error", name)
+		}
+		if entries, _ := os.ReadDir(parent); len(entries) != 1 {
+			t.Errorf("%s: files were written next to the checkout: %v", name, entries)
+		}
+	}
+
+	// Relative links inside the checkout are kept
+	dest := t.TempDir()
+	archive := makeRawArchive(t, file("skills/pdf/SKILL.md"), link("skills/pdf/README.md", "SKILL.md"), link("skills/docs", "pdf"))
+	if _, err := extractArchive(bytes.NewReader(archive), dest, nil); err != nil {
+		t.Fatalf("extractArchive: %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(dest, "skills", "pdf", "README.md")); err != nil || target != "SKILL.md" {
+		t.Fatalf("README.md link = %q, %v", target, err)
+	}
+}
+
+func makeZipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, body := range files {
+		w, err := zw.Create("repo-v1/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	if err := zw.SetComment(testCommit); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestFetchArchive_OnlySkillPath(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	files := map[string]string{
+		"skills/pdf/SKILL.md":  "# pdf\n",
+		"skills/docx/SKILL.md": "# docx\n",
+		"README.md":            "root\n",
+	}
+	tarball := makeArchive(t, files)
+	zipball := makeZipArchive(t, files)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/owner/repo/tar.gz/main":
+			w.Write(tarball)
+		case "/owner/repo/zip/v1":
+			w.Write(zipball)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	for _, tt := range []struct{ url, ref string }{
+		{srv.URL + "/owner/repo/tar.gz/main", "main"},
+		{srv.URL + "/owner/repo/zip/v1", "v1"},
+	} {
+		res, err := FetchArchive(tt.url, "owner/repo", tt.ref, []string{"skills/pdf"}, false)
+		if err != nil {
+			t.Fatalf("FetchArchive(%s): %v", tt.url, err)
+		}
+		if !dirExists(filepath.Join(res.TempDir, "skills", "pdf")) {
+			t.Fatalf("%s: skill path missing", tt.url)
+		}
+		if dirExists(filepath.Join(res.TempDir, "skills", "docx")) || fileExistsForTest(filepath.Join(res.TempDir, "README.md")) {
+			t.Fatalf("%s: entries outside the skill path were extracted", tt.url)
+		}
+		if got := ArchiveRef(res.TempDir); got != tt.ref {
+			t.Errorf("%s: ArchiveRef = %q; want %q", tt.url, got, tt.ref)
+		}
+		if commit, err := GetRepoHeadCommitFull(res.TempDir); err != nil || commit != testCommit {
+			t.Errorf("%s: commit = %q, %v", tt.url, commit, err)
+		}
+		if _, err := ReadFileAtCommit(res.TempDir, "abc1234", "skills/pdf/SKILL.md"); !errors.Is(err, ErrNoHistory) {
+			t.Errorf("%s: ReadFileAtCommit error = %v; want ErrNoHistory", tt.url, err)
+		}
+	}
+
+	if _, err := FetchArchive(srv.URL+"/owner/repo/tar.gz/gone", "owner/repo", "gone", nil, false); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing archive error = %v; want ErrNotFound", err)
+	}
+}
+
+func fileExistsForTest(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestFetchArchive_RejectsEscapingLinks(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	var zbuf bytes.Buffer
+	zw := zip.NewWriter(&zbuf)
+	hdr := &zip.FileHeader{Name: "repo-v1/skills/pdf/key"}
+	hdr.SetMode(os.ModeSymlink | 0777)
+	w, err := zw.CreateHeader(hdr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("/home/user/.ssh/id_ed25519"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tarball := makeRawArchive(t,
+		&tar.Header{Typeflag: tar.TypeSymlink, Name: "repo-main/skills/pdf/etc", Linkname: "../../../../../etc"},
+		&tar.Header{Typeflag: tar.TypeReg, Name: "repo-main/skills/pdf/etc/profile", Mode: 0644, Size: 4},
+	)
+
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		switch r.URL.Path {
+		case "/owner/repo/zip/v1":
+			w.Write(zbuf.Bytes())
+		case "/owner/repo/tar.gz/main":
+			w.Write(tarball)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	for _, tt := range []struct{ url, ref string }{
+		{srv.URL + "/owner/repo/zip/v1", "v1"},
+		{srv.URL + "/owner/repo/tar.gz/main", "main"},
+	} {
+		// Run twice: a rejected archive must not be cached and reused
+		for i := 0; i < 2; i++ {
+			if _, err := FetchArchive(tt.url, "owner/repo", tt.ref, []string{"skills/pdf"}, false); err == nil {
+				t.Fatalf("FetchArchive(%s) succeeded; want an error", tt.url)
+			}
+		}
+	}
+	if requests != 4 {
+		t.Fatalf("requests = %d; want 4 (no cached checkout reused)", requests)
+	}
+}
+
+func TestGetLockPin(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "checkout")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	const url = "https://example.com/repo/archive/main.tar.gz"
+
+	// The archive recorded its commit: pinned to it
+	if err := writeArchiveRecord(dir, archiveRecord{URL: url, Ref: "main", Commit: testCommit}); err != nil {
+		t.Fatal(err)
+	}
+	if commit, archive, err := GetLockPin(dir); err != nil || commit != testCommit || archive != "" {
+		t.Fatalf("GetLockPin = %q, %q, %v; want the commit", commit, archive, err)
+	}
+
+	// It did not: pinned to the archive URL
+	if err := writeArchiveRecord(dir, archiveRecord{URL: url, Ref: "main"}); err != nil {
+		t.Fatal(err)
+	}
+	if commit, archive, err := GetLockPin(dir); err != nil || commit != "" || archive != url {
+		t.Fatalf("GetLockPin = %q, %q, %v; want the archive URL", commit, archive, err)
+	}
+
+	// Not a checkout at all
+	if _, _, err := GetLockPin(t.TempDir()); err == nil {
+		t.Fatal("GetLockPin should fail for a directory that is no checkout")
+	}
+}
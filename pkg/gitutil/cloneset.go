@@ -0,0 +1,71 @@
+package gitutil
+
+import (
+	"strings"
+	"sync"
+)
+
+// CloneSet deduplicates checkouts within one batch of installs. Each
+// distinct repository checkout is fetched once; concurrent callers asking
+// for the same checkout wait for the first one and share its result.
+// A CloneSet is safe for concurrent use.
+type CloneSet struct {
+	mu    sync.Mutex
+	calls map[string]*cloneCall
+}
+
+type cloneCall struct {
+	done   chan struct{}
+	result *CloneResult
+	err    error
+}
+
+// NewCloneSet creates an empty CloneSet
+func NewCloneSet() *CloneSet {
+	return &CloneSet{calls: make(map[string]*cloneCall)}
+}
+
+// CloneRepoWithRefresh is CloneRepoWithRefresh, once per repository and
+// branch. The refresh flag of the first call wins.
+func (s *CloneSet) CloneRepoWithRefresh(gitURL string, repoName string, branch string, refresh bool) (*CloneResult, error) {
+	return s.do("branch\x00"+repoName+"\x00"+branch, func() (*CloneResult, error) {
+		return CloneRepoWithRefresh(gitURL, repoName, branch, refresh)
+	})
+}
+
+// SparseCloneRepo is SparseCloneRepo, once per repository, branch and path set
+func (s *CloneSet) SparseCloneRepo(gitURL string, repoName string, branch string, sparsePaths []string) (*CloneResult, error) {
+	return s.do("sparse\x00"+repoName+"\x00"+branch+"\x00"+strings.Join(sparsePaths, "\x00"), func() (*CloneResult, error) {
+		return SparseCloneRepo(gitURL, repoName, branch, sparsePaths)
+	})
+}
+
+// CloneRepoAtRef is CloneRepoAtRef, once per repository and ref
+func (s *CloneSet) CloneRepoAtRef(gitURL string, repoName string, ref string) (*CloneResult, error) {
+	return s.do("ref\x00"+repoName+"\x00"+ref, func() (*CloneResult, error) {
+		return CloneRepoAtRef(gitURL, repoName, ref)
+	})
+}
+
+// FetchArchive is FetchArchive, once per archive URL and path set
+func (s *CloneSet) FetchArchive(archive string, repoName string, ref string, paths []string, refresh bool) (*CloneResult, error) {
+	return s.do("archive\x00"+archive+"\x00"+strings.Join(paths, "\x00"), func() (*CloneResult, error) {
+		return FetchArchive(archive, repoName, ref, paths, refresh)
+	})
+}
+
+func (s *CloneSet) do(key string, clone func() (*CloneResult, error)) (*CloneResult, error) {
+	s.mu.Lock()
+	if c, ok := s.calls[key]; ok {
+		s.mu.Unlock()
+		<-c.done
+		return c.result, c.err
+	}
+	c := &cloneCall{done: make(chan struct{})}
+	s.calls[key] = c
+	s.mu.Unlock()
+
+	c.result, c.err = clone()
+	close(c.done)
+	return c.result, c.err
+}
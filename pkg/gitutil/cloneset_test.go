@@ -0,0 +1,44 @@
+package gitutil
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCloneSet_ClonesEachKeyOnce(t *testing.T) {
+	s := NewCloneSet()
+	var calls int32
+
+	var wg sync.WaitGroup
+	results := make([]*CloneResult, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = s.do("repo", func() (*CloneResult, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(5 * time.Millisecond)
+				return &CloneResult{TempDir: "/tmp/repo"}, nil
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("clone ran %d times; want 1", calls)
+	}
+	for i, r := range results {
+		if r == nil || r.TempDir != "/tmp/repo" {
+			t.Fatalf("result %d = %+v", i, r)
+		}
+	}
+
+	if _, err := s.do("other", func() (*CloneResult, error) {
+		atomic.AddInt32(&calls, 1)
+		return &CloneResult{}, nil
+	}); err != nil || calls != 2 {
+		t.Fatalf("a different key should clone again (calls=%d, err=%v)", calls, err)
+	}
+}
@@ -0,0 +1,560 @@
+package gitutil
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/castle-x/skills-x/pkg/gitauth"
+)
+
+// execFetcher is the Fetcher backed by the git binary. It keeps shallow
+// clones in the cache, so history can be deepened later for diffs and merges.
+type execFetcher struct{}
+
+func (execFetcher) Name() string { return BackendGit }
+
+// Clone shallow-clones a repository into the cache, reusing a cached clone
+// unless refresh is set, in which case the cache is fetched and reset
+func (execFetcher) Clone(gitURL string, repoName string, branch string, refresh bool) (*CloneResult, error) {
+	tempDir := getTempDir(repoName + branchSuffix(branch))
+
+	if dirExists(tempDir) {
+		if hasGitContent(tempDir) {
+			if refresh {
+				if err := updateShallowRepo(tempDir, branch); err != nil {
+					os.RemoveAll(tempDir)
+				} else {
+					return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
+				}
+			} else {
+				return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
+			}
+		} else {
+			// Cache exists but is corrupted — try to remove
+			if err := os.RemoveAll(tempDir); err != nil {
+				// Cannot remove (e.g. owned by another user), use fallback path
+				tempDir = getUserTempDir(repoName + branchSuffix(branch))
+				if dirExists(tempDir) && hasGitContent(tempDir) {
+					if !refresh {
+						return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
+					}
+					os.RemoveAll(tempDir)
+				} else if dirExists(tempDir) {
+					os.RemoveAll(tempDir)
+				}
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(tempDir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= MaxRetries; attempt++ {
+		result, err := cloneWithTimeout(gitURL, tempDir, branch)
+		if err == nil {
+			return result, nil
+		}
+
+		lastErr = err
+
+		if cloneErr, ok := err.(*CloneError); ok && cloneErr.permanent() {
+			return nil, err
+		}
+
+		os.RemoveAll(tempDir)
+
+		if attempt < MaxRetries {
+			time.Sleep(RetryDelay)
+		}
+	}
+
+	return nil, lastErr
+}
+
+// cloneWithTimeout performs a single clone attempt with timeout
+func cloneWithTimeout(gitURL string, tempDir string, branch string) (*CloneResult, error) {
+	args := []string{"clone", "--depth", "1"}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	args = append(args, gitURL, tempDir)
+	cmd := remoteGit(gitURL, args...)
+
+	// Set up timeout
+	done := make(chan error, 1)
+	go func() {
+		done <- runCapturingStderr(cmd)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, parseGitError(err, gitURL)
+		}
+	case <-time.After(CloneTimeout):
+		// Kill the process on timeout
+		if cmd.Process != nil {
+			cmd.Process.Kill()
+		}
+		return nil, &CloneError{
+			URL:       gitURL,
+			Message:   fmt.Sprintf("Clone timed out after %v. Check your network or credentials.", CloneTimeout),
+			IsTimeout: true,
+		}
+	}
+
+	return &CloneResult{
+		TempDir: tempDir,
+		Repo:    filepath.Base(tempDir),
+	}, nil
+}
+
+// SparseClone fetches the repository with depth 1 and checks out only
+// sparsePaths through git's sparse checkout
+func (execFetcher) SparseClone(gitURL string, repoName string, branch string, sparsePaths []string) (*CloneResult, error) {
+	tempDir := getTempDirSparse(repoName+branchSuffix(branch), sparsePaths)
+
+	if dirExists(tempDir) {
+		if hasGitContent(tempDir) {
+			return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
+		}
+		if err := os.RemoveAll(tempDir); err != nil {
+			tempDir = getUserTempDirSparse(repoName, sparsePaths)
+			if dirExists(tempDir) && hasGitContent(tempDir) {
+				return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
+			}
+			if dirExists(tempDir) {
+				os.RemoveAll(tempDir)
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(tempDir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	// Step 1: Initialize empty repo with sparse checkout
+	if err := runWithTimeout(exec.Command("git", "init", tempDir), SparseCloneTimeout); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, fmt.Errorf("failed to init repo: %w", err)
+	}
+
+	// Step 2: Add remote
+	if err := runWithTimeout(exec.Command("git", "-C", tempDir, "remote", "add", "origin", gitURL), SparseCloneTimeout); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, fmt.Errorf("failed to add remote: %w", err)
+	}
+
+	// Step 3: Enable sparse checkout
+	if err := runWithTimeout(exec.Command("git", "-C", tempDir, "config", "core.sparseCheckout", "true"), SparseCloneTimeout); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, fmt.Errorf("failed to enable sparse checkout: %w", err)
+	}
+
+	// Step 4: Configure sparse checkout paths
+	sparseCheckoutFile := filepath.Join(tempDir, ".git", "info", "sparse-checkout")
+	if err := os.MkdirAll(filepath.Dir(sparseCheckoutFile), 0755); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, fmt.Errorf("failed to create sparse-checkout dir: %w", err)
+	}
+
+	// Write sparse paths to config
+	sparseContent := strings.Join(sparsePaths, "\n") + "\n"
+	if err := os.WriteFile(sparseCheckoutFile, []byte(sparseContent), 0644); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, fmt.Errorf("failed to write sparse-checkout config: %w", err)
+	}
+
+	// Step 5: Fetch only the needed data (depth 1)
+	fetchRef := "HEAD"
+	if branch != "" {
+		fetchRef = branch
+	}
+	fetchCmd := remoteGit(gitURL, "-C", tempDir, "fetch", "--depth", "1", "origin", fetchRef)
+	if err := runWithTimeout(fetchCmd, CloneTimeout); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, parseGitError(err, gitURL)
+	}
+
+	// Step 6: Checkout
+	checkoutCmd := exec.Command("git", "-C", tempDir, "checkout", "FETCH_HEAD")
+	if err := runWithTimeout(checkoutCmd, SparseCloneTimeout); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, fmt.Errorf("failed to checkout: %w", err)
+	}
+
+	return &CloneResult{
+		TempDir: tempDir,
+		Repo:    repoName,
+	}, nil
+}
+
+// runWithTimeout runs a command with a timeout
+func runWithTimeout(cmd *exec.Cmd, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- runCapturingStderr(cmd)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		if cmd.Process != nil {
+			cmd.Process.Kill()
+		}
+		return &CloneError{
+			Message:   fmt.Sprintf("command timed out after %v", timeout),
+			IsTimeout: true,
+		}
+	}
+}
+
+// remoteGit returns a git command that talks to gitURL. Prompts are
+// disabled, so missing credentials fail instead of waiting for input, and
+// configured credentials are passed through the environment.
+func remoteGit(gitURL string, args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Env = gitauth.GitEnv(gitURL)
+	return cmd
+}
+
+// originURL returns the URL of a clone's origin remote
+func originURL(dir string) string {
+	out, err := exec.Command("git", "-C", dir, "remote", "get-url", "origin").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// runCapturingStderr runs cmd and appends its stderr, with tokens hidden, to
+// the returned error, so parseGitError can tell auth and not-found failures
+// apart
+func runCapturingStderr(cmd *exec.Cmd) error {
+	if cmd.Stderr != nil {
+		return cmd.Run()
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := gitauth.Redact(strings.TrimSpace(stderr.String())); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// pullRepo pulls latest changes in a repo (for non-shallow clones)
+func pullRepo(dir string) error {
+	cmd := remoteGit(originURL(dir), "-C", dir, "pull", "--ff-only")
+	return cmd.Run()
+}
+
+// updateShallowRepo updates a shallow clone by fetching latest and resetting
+// This is the correct way to update a --depth 1 clone
+func updateShallowRepo(dir string, branch string) error {
+	// Fetch latest with depth 1
+	fetchArgs := []string{"-C", dir, "fetch", "--depth", "1", "origin"}
+	if branch != "" {
+		fetchArgs = append(fetchArgs, branch)
+	}
+	fetchCmd := remoteGit(originURL(dir), fetchArgs...)
+	if err := runWithTimeout(fetchCmd, CloneTimeout); err != nil {
+		return err
+	}
+
+	if branch != "" {
+		resetCmd := exec.Command("git", "-C", dir, "reset", "--hard", "origin/"+branch)
+		return runWithTimeout(resetCmd, SparseCloneTimeout)
+	}
+
+	// Get the default branch name
+	branchCmd := exec.Command("git", "-C", dir, "symbolic-ref", "refs/remotes/origin/HEAD", "--short")
+	output, err := branchCmd.Output()
+	if err != nil {
+		// Fallback: try to reset to origin/main or origin/master
+		resetCmd := exec.Command("git", "-C", dir, "reset", "--hard", "origin/HEAD")
+		return runWithTimeout(resetCmd, SparseCloneTimeout)
+	}
+
+	// Reset to the fetched head
+	defaultBranch := strings.TrimSpace(string(output))
+	resetCmd := exec.Command("git", "-C", dir, "reset", "--hard", defaultBranch)
+	return runWithTimeout(resetCmd, SparseCloneTimeout)
+}
+
+// parseGitError converts git errors to CloneError, classifying them from
+// the stderr text git printed
+func parseGitError(err error, url string) *CloneError {
+	if cloneErr, ok := err.(*CloneError); ok {
+		cloneErr.URL = url
+		return cloneErr
+	}
+	msg := err.Error()
+
+	isAuth := strings.Contains(msg, "Authentication failed") ||
+		strings.Contains(msg, "could not read Username") ||
+		strings.Contains(msg, "terminal prompts disabled") ||
+		strings.Contains(msg, "Permission denied") ||
+		strings.Contains(msg, "Host key verification failed") ||
+		strings.Contains(msg, "The requested URL returned error: 401") ||
+		strings.Contains(msg, "The requested URL returned error: 403")
+
+	if isAuth {
+		return &CloneError{
+			URL:         url,
+			Message:     fmt.Sprintf("Authentication failed for %s. %s", url, authHint(url)),
+			IsAuthError: true,
+		}
+	}
+
+	lower := strings.ToLower(msg)
+	isNotFound := strings.Contains(lower, "not found") ||
+		strings.Contains(msg, "couldn't find remote ref") ||
+		strings.Contains(msg, "does not appear to be a git repository")
+
+	if isNotFound {
+		return &CloneError{
+			URL:        url,
+			Message:    fmt.Sprintf("Repository or ref not found: %s. Private repositories also need credentials: %s", url, authHint(url)),
+			IsNotFound: true,
+		}
+	}
+
+	return &CloneError{
+		URL:     url,
+		Message: fmt.Sprintf("Failed to clone %s: %v", url, err),
+	}
+}
+
+// execHeadCommit returns the short HEAD commit hash of a git clone
+func execHeadCommit(repoDir string) (string, error) {
+	cmd := exec.Command("git", "-C", repoDir, "rev-parse", "--short", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// execHeadCommitFull returns the full HEAD commit hash of a git clone
+func execHeadCommitFull(repoDir string) (string, error) {
+	cmd := exec.Command("git", "-C", repoDir, "rev-parse", "HEAD")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// execTreeHash asks git for the tree hash of path at HEAD
+func execTreeHash(repoDir string, path string) (string, error) {
+	spec := "HEAD^{tree}"
+	if p := strings.Trim(filepath.ToSlash(path), "/"); p != "" && p != "." {
+		spec = "HEAD:" + p
+	}
+	cmd := exec.Command("git", "-C", repoDir, "rev-parse", spec)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// execLogSubjects returns "<short sha> <subject>" lines, newest first, for the
+// commits after since up to HEAD. When path is non-empty only commits touching
+// it are listed. Shallow clones are deepened until since is reachable.
+func execLogSubjects(repoDir string, since string, path string) ([]string, error) {
+	if err := deepenUntil(repoDir, since); err != nil {
+		return nil, err
+	}
+
+	args := []string{"-C", repoDir, "log", "--format=%h %s", since + "..HEAD"}
+	if p := strings.Trim(filepath.ToSlash(path), "/"); p != "" && p != "." {
+		args = append(args, "--", p)
+	}
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// execReadFileAtCommit returns the content of path as it was at commit, fetching
+// more history into a shallow clone when the commit is not present yet.
+func execReadFileAtCommit(repoDir string, commit string, path string) ([]byte, error) {
+	if err := deepenUntil(repoDir, commit); err != nil {
+		return nil, err
+	}
+	p := strings.Trim(filepath.ToSlash(path), "/")
+	out, err := exec.Command("git", "-C", repoDir, "show", commit+":"+p).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", p, commit, err)
+	}
+	return out, nil
+}
+
+// deepenUntil fetches more history into a shallow clone until commit exists
+func deepenUntil(repoDir string, commit string) error {
+	for _, deepen := range []string{"--deepen=50", "--deepen=500", "--unshallow"} {
+		if hasCommit(repoDir, commit) {
+			return nil
+		}
+		out, err := exec.Command("git", "-C", repoDir, "rev-parse", "--is-shallow-repository").Output()
+		if err != nil || strings.TrimSpace(string(out)) != "true" {
+			break
+		}
+		if err := runWithTimeout(remoteGit(originURL(repoDir), "-C", repoDir, "fetch", "--quiet", deepen, "origin"), CloneTimeout); err != nil {
+			return fmt.Errorf("failed to fetch history: %w", err)
+		}
+	}
+	if hasCommit(repoDir, commit) {
+		return nil
+	}
+	return fmt.Errorf("commit %s not found in repository history", commit)
+}
+
+// hasCommit reports whether a commit object is present locally
+func hasCommit(repoDir string, commit string) bool {
+	return exec.Command("git", "-C", repoDir, "cat-file", "-e", commit+"^{commit}").Run() == nil
+}
+
+// CloneAtCommit checks out a repository at an exact commit.
+// The commit is fetched shallowly by SHA; servers that refuse to serve
+// unadvertised objects fall back to a full clone followed by a checkout.
+// Each commit gets its own cache directory, so a pinned checkout is never
+// moved by a later refresh of the branch cache.
+func (execFetcher) CloneAtCommit(gitURL string, repoName string, commit string) (*CloneResult, error) {
+	if commit == "" {
+		return nil, fmt.Errorf("commit is required")
+	}
+
+	tempDir := getTempDir(repoName + "#" + commit)
+	if dirExists(tempDir) {
+		if hasGitContent(tempDir) {
+			if head, err := execHeadCommitFull(tempDir); err == nil && strings.HasPrefix(head, commit) {
+				return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
+			}
+		}
+		if err := os.RemoveAll(tempDir); err != nil {
+			tempDir = getUserTempDir(repoName + "#" + commit)
+			os.RemoveAll(tempDir)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(tempDir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	if err := fetchCommitShallow(gitURL, tempDir, commit); err == nil {
+		return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
+	} else if cloneErr, ok := err.(*CloneError); ok && cloneErr.IsAuthError {
+		os.RemoveAll(tempDir)
+		return nil, err
+	}
+	os.RemoveAll(tempDir)
+
+	// Fallback: full clone, then check out the requested commit.
+	cloneCmd := remoteGit(gitURL, "clone", "--no-checkout", gitURL, tempDir)
+	if err := runWithTimeout(cloneCmd, CloneTimeout); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, parseGitError(err, gitURL)
+	}
+	checkoutCmd := exec.Command("git", "-C", tempDir, "checkout", "--detach", commit)
+	if err := runWithTimeout(checkoutCmd, SparseCloneTimeout); err != nil {
+		os.RemoveAll(tempDir)
+		return nil, fmt.Errorf("commit %s not found in %s: %w", commit, gitURL, err)
+	}
+
+	return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
+}
+
+// fetchCommitShallow initializes tempDir and fetches a single commit with depth 1
+func fetchCommitShallow(gitURL string, tempDir string, commit string) error {
+	if err := runWithTimeout(exec.Command("git", "init", tempDir), SparseCloneTimeout); err != nil {
+		return fmt.Errorf("failed to init repo: %w", err)
+	}
+	if err := runWithTimeout(exec.Command("git", "-C", tempDir, "remote", "add", "origin", gitURL), SparseCloneTimeout); err != nil {
+		return fmt.Errorf("failed to add remote: %w", err)
+	}
+	fetchCmd := remoteGit(gitURL, "-C", tempDir, "fetch", "--depth", "1", "origin", commit)
+	if err := runWithTimeout(fetchCmd, CloneTimeout); err != nil {
+		return parseGitError(err, gitURL)
+	}
+	checkoutCmd := exec.Command("git", "-C", tempDir, "checkout", "--detach", "FETCH_HEAD")
+	if err := runWithTimeout(checkoutCmd, SparseCloneTimeout); err != nil {
+		return fmt.Errorf("failed to checkout: %w", err)
+	}
+	return nil
+}
+
+// CloneAtRef checks out a repository at a pinned ref: a tag, a branch or
+// a commit SHA. Commit SHAs go through CloneAtCommit; tags and branches
+// are shallow-cloned with --branch. Each ref gets its own cache directory that
+// is reused as-is, so refs are expected to be immutable (use a source branch
+// to track a moving tip instead).
+func (f execFetcher) CloneAtRef(gitURL string, repoName string, ref string) (*CloneResult, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("ref is required")
+	}
+	if IsCommitSHA(ref) {
+		return f.CloneAtCommit(gitURL, repoName, ref)
+	}
+
+	tempDir := getTempDir(repoName + "#" + ref)
+	if dirExists(tempDir) {
+		if hasGitContent(tempDir) {
+			return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
+		}
+		if err := os.RemoveAll(tempDir); err != nil {
+			tempDir = getUserTempDir(repoName + "#" + ref)
+			if dirExists(tempDir) && hasGitContent(tempDir) {
+				return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
+			}
+			os.RemoveAll(tempDir)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(tempDir), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= MaxRetries; attempt++ {
+		result, err := cloneWithTimeout(gitURL, tempDir, ref)
+		if err == nil {
+			result.Repo = repoName
+			return result, nil
+		}
+
+		lastErr = err
+
+		if cloneErr, ok := err.(*CloneError); ok && cloneErr.permanent() {
+			return nil, err
+		}
+
+		os.RemoveAll(tempDir)
+
+		if attempt < MaxRetries {
+			time.Sleep(RetryDelay)
+		}
+	}
+
+	return nil, lastErr
+}
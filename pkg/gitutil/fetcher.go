@@ -0,0 +1,204 @@
+package gitutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// Backend names, as accepted by BackendEnv
+const (
+	BackendGit    = "git"    // Shell out to the git binary
+	BackendNative = "native" // Pure Go: download source archives over HTTPS
+)
+
+// BackendEnv overrides automatic backend selection ("git" or "native")
+const BackendEnv = "SKILLS_GIT_BACKEND"
+
+// Fetcher checks out repositories into the local cache. Every method returns
+// a directory that the other package functions (GetRepoHeadCommit,
+// GetTreeHash, ...) understand, whichever backend produced it.
+type Fetcher interface {
+	// Name returns the backend name (BackendGit or BackendNative)
+	Name() string
+	// Clone checks out branch ("" for the default branch). A cached checkout
+	// is reused as-is unless refresh is set.
+	Clone(gitURL string, repoName string, branch string, refresh bool) (*CloneResult, error)
+	// SparseClone checks out only sparsePaths of branch
+	SparseClone(gitURL string, repoName string, branch string, sparsePaths []string) (*CloneResult, error)
+	// CloneAtCommit checks out an exact commit
+	CloneAtCommit(gitURL string, repoName string, commit string) (*CloneResult, error)
+	// CloneAtRef checks out a tag, branch or commit SHA that is not expected to move
+	CloneAtRef(gitURL string, repoName string, ref string) (*CloneResult, error)
+}
+
+var (
+	fetcherMu sync.Mutex
+	fetcher   Fetcher
+)
+
+// DefaultFetcher returns the backend used by the package functions. It is
+// chosen on first use: BackendEnv if set, otherwise git when the binary is on
+// PATH and the native backend when it is not.
+func DefaultFetcher() Fetcher {
+	fetcherMu.Lock()
+	defer fetcherMu.Unlock()
+	if fetcher == nil {
+		fetcher = selectFetcher(os.Getenv(BackendEnv), hasGitBinary())
+	}
+	return fetcher
+}
+
+// SetFetcher replaces the backend used by the package functions
+func SetFetcher(f Fetcher) {
+	fetcherMu.Lock()
+	defer fetcherMu.Unlock()
+	fetcher = f
+}
+
+// NewFetcher returns the backend with the given name
+func NewFetcher(name string) (Fetcher, error) {
+	switch name {
+	case BackendGit:
+		return execFetcher{}, nil
+	case BackendNative:
+		return newArchiveFetcher(), nil
+	}
+	return nil, fmt.Errorf("unknown git backend %q (want %s or %s)", name, BackendGit, BackendNative)
+}
+
+// selectFetcher picks a backend from the override and git availability.
+// An unknown override falls back to automatic selection.
+func selectFetcher(override string, haveGit bool) Fetcher {
+	if f, err := NewFetcher(override); err == nil {
+		return f
+	}
+	if haveGit {
+		return execFetcher{}
+	}
+	return newArchiveFetcher()
+}
+
+func hasGitBinary() bool {
+	_, err := exec.LookPath("git")
+	return err == nil
+}
+
+// CloneRepoWithRefresh clones a git repository with optional cache refresh
+// If refresh is true, it will fetch the latest changes even if cache exists
+// branch can be empty to use the repository's default branch
+func CloneRepoWithRefresh(gitURL string, repoName string, branch string, refresh bool) (*CloneResult, error) {
+	return DefaultFetcher().Clone(gitURL, repoName, branch, refresh)
+}
+
+// SparseCloneRepo clones only specific directories from a git repository
+// This is much faster for large repositories when you only need specific paths
+// branch can be empty to use the repository's default branch
+func SparseCloneRepo(gitURL string, repoName string, branch string, sparsePaths []string) (*CloneResult, error) {
+	return DefaultFetcher().SparseClone(gitURL, repoName, branch, sparsePaths)
+}
+
+// CloneRepoAtCommit checks out a repository at an exact commit. Each commit
+// gets its own cache directory, so a pinned checkout is never moved by a
+// later refresh of the branch cache.
+func CloneRepoAtCommit(gitURL string, repoName string, commit string) (*CloneResult, error) {
+	return DefaultFetcher().CloneAtCommit(gitURL, repoName, commit)
+}
+
+// CloneRepoAtRef checks out a repository at a pinned ref: a tag, a branch or
+// a commit SHA. Each ref gets its own cache directory that is reused as-is,
+// so refs are expected to be immutable (use a source branch to track a
+// moving tip instead).
+func CloneRepoAtRef(gitURL string, repoName string, ref string) (*CloneResult, error) {
+	return DefaultFetcher().CloneAtRef(gitURL, repoName, ref)
+}
+
+// GetRepoHeadCommit returns the short HEAD commit hash of a checkout
+func GetRepoHeadCommit(repoDir string) (string, error) {
+	if hasGitContent(repoDir) {
+		return execHeadCommit(repoDir)
+	}
+	full, err := GetRepoHeadCommitFull(repoDir)
+	if err != nil {
+		return "", err
+	}
+	return shortCommit(full), nil
+}
+
+// GetRepoHeadCommitFull returns the full 40-character HEAD commit hash of a checkout
+func GetRepoHeadCommitFull(repoDir string) (string, error) {
+	if hasGitContent(repoDir) {
+		return execHeadCommitFull(repoDir)
+	}
+	rec, err := readArchiveRecord(repoDir)
+	if err != nil {
+		return "", err
+	}
+	if rec.Commit == "" {
+		return "", fmt.Errorf("commit of %s is unknown: the archive did not record it", rec.URL)
+	}
+	return rec.Commit, nil
+}
+
+// GetLockPin returns what skills-x.lock pins a checkout to: its full HEAD
+// commit, or the URL of an archive that did not record its commit. Only git
+// clones whose commit cannot be read return an error.
+func GetLockPin(repoDir string) (commit string, archive string, err error) {
+	commit, err = GetRepoHeadCommitFull(repoDir)
+	if err == nil || hasGitContent(repoDir) {
+		return commit, "", err
+	}
+	rec, recErr := readArchiveRecord(repoDir)
+	if recErr != nil {
+		return "", "", err
+	}
+	return "", rec.URL, nil
+}
+
+// GetTreeHash returns the git tree hash of path (relative to the repository
+// root) at HEAD. Unlike the HEAD commit it only changes when something inside
+// path changes, so it identifies a skill's content independently of unrelated
+// commits elsewhere in a monorepo. An empty path returns the root tree.
+func GetTreeHash(repoDir string, path string) (string, error) {
+	if hasGitContent(repoDir) {
+		return execTreeHash(repoDir, path)
+	}
+	if _, err := readArchiveRecord(repoDir); err != nil {
+		return "", err
+	}
+	return treeHash(repoDir, path)
+}
+
+// GetLogSubjects returns "<short sha> <subject>" lines, newest first, for the
+// commits after since up to HEAD. When path is non-empty only commits touching
+// it are listed. Shallow clones are deepened until since is reachable.
+// Archive checkouts carry no history and return ErrNoHistory.
+func GetLogSubjects(repoDir string, since string, path string) ([]string, error) {
+	if !hasGitContent(repoDir) {
+		return nil, ErrNoHistory
+	}
+	return execLogSubjects(repoDir, since, path)
+}
+
+// ReadFileAtCommit returns the content of path as it was at commit. Git
+// clones fetch more history when the commit is not present yet; archive
+// checkouts download the archive of that commit.
+func ReadFileAtCommit(repoDir string, commit string, path string) ([]byte, error) {
+	if hasGitContent(repoDir) {
+		return execReadFileAtCommit(repoDir, commit, path)
+	}
+	rec, err := readArchiveRecord(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	return newArchiveFetcher().readFileAtCommit(rec, commit, path)
+}
+
+// shortCommit abbreviates a full SHA the way git rev-parse --short does by default
+func shortCommit(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
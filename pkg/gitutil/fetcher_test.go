@@ -0,0 +1,102 @@
+package gitutil
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSelectFetcher(t *testing.T) {
+	tests := []struct {
+		override string
+		haveGit  bool
+		want     string
+	}{
+		{"", true, BackendGit},
+		{"", false, BackendNative},
+		{BackendNative, true, BackendNative},
+		{BackendGit, false, BackendGit},
+		{"bogus", true, BackendGit},
+	}
+	for _, tt := range tests {
+		if got := selectFetcher(tt.override, tt.haveGit).Name(); got != tt.want {
+			t.Errorf("selectFetcher(%q, %v) = %s; want %s", tt.override, tt.haveGit, got, tt.want)
+		}
+	}
+}
+
+func TestParseGitError_Typed(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   error
+	}{
+		{"fatal: Authentication failed for 'https://example.com/x.git/'", ErrAuth},
+		{"remote: Repository not found.", ErrNotFound},
+		{"fatal: Remote branch nope not found in upstream origin", ErrNotFound},
+		{"fatal: couldn't find remote ref nope", ErrNotFound},
+	}
+	for _, tt := range tests {
+		err := parseGitError(errors.New("exit status 128: "+tt.stderr), "https://example.com/x.git")
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%q classified as %+v; want %v", tt.stderr, err, tt.want)
+		}
+	}
+
+	timeout := parseGitError(&CloneError{Message: "command timed out", IsTimeout: true}, "u")
+	if !errors.Is(timeout, ErrTimeout) || timeout.URL != "u" {
+		t.Errorf("timeout lost its type: %+v", timeout)
+	}
+}
+
+// TestTreeHash_MatchesGit checks the pure-Go tree hash against git itself
+func TestTreeHash_MatchesGit(t *testing.T) {
+	if !hasGitBinary() {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	write := func(rel string, content string, mode os.FileMode) {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("README.md", "root\n", 0644)
+	write("skills/pdf/SKILL.md", "# pdf\n", 0644)
+	write("skills/pdf/scripts/run.sh", "#!/bin/sh\n", 0755)
+	write("skills/pdf-extra.md", "sorts between pdf and pdf/\n", 0644)
+	if err := os.Symlink("SKILL.md", filepath.Join(dir, "skills", "pdf", "LINK.md")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=t", "-c", "user.email=t@t", "-c", "commit.gpgsign=false"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "init")
+
+	for _, p := range []string{"", "skills", "skills/pdf"} {
+		want, err := execTreeHash(dir, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := treeHash(dir, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("treeHash(%q) = %s; git says %s", p, got, want)
+		}
+	}
+}
@@ -3,12 +3,16 @@ package gitutil
 
 import (
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/castle-x/skills-x/pkg/gitauth"
 )
 
 const (
@@ -24,16 +28,57 @@ const (
 	RetryDelay = 2 * time.Second
 )
 
+// Typed fetch failures. Every backend reports them through CloneError, so
+// callers can test with errors.Is whichever backend did the fetch.
+var (
+	ErrAuth     = errors.New("authentication failed")
+	ErrNotFound = errors.New("repository or ref not found")
+	ErrTimeout  = errors.New("fetch timed out")
+)
+
 // CloneError represents a git clone error
 type CloneError struct {
 	URL         string
 	Message     string
 	IsTimeout   bool
 	IsAuthError bool
+	IsNotFound  bool
 }
 
+// Error returns the message with any token hidden
 func (e *CloneError) Error() string {
-	return e.Message
+	return gitauth.Redact(e.Message)
+}
+
+// Is matches ErrAuth, ErrNotFound and ErrTimeout
+func (e *CloneError) Is(target error) bool {
+	switch target {
+	case ErrAuth:
+		return e.IsAuthError
+	case ErrNotFound:
+		return e.IsNotFound
+	case ErrTimeout:
+		return e.IsTimeout
+	}
+	return false
+}
+
+// authHint tells the user how to give skills-x access to a repository
+func authHint(gitURL string) string {
+	if gitauth.IsSSH(gitURL) {
+		return "Check that an SSH key with access to the repository is loaded (ssh-add -l)."
+	}
+	host, _ := gitauth.Split(gitURL)
+	if host == "" {
+		return "Check your credentials or repository access."
+	}
+	return fmt.Sprintf("Set %s, add a token for %s to %s, or configure a git credential helper.",
+		gitauth.HostEnv(host), host, gitauth.FilePath())
+}
+
+// permanent reports whether retrying the fetch cannot help
+func (e *CloneError) permanent() bool {
+	return e.IsAuthError || e.IsNotFound
 }
 
 // CloneResult contains the result of a clone operation
@@ -51,207 +96,6 @@ func CloneRepo(gitURL string, repoName string, branch string) (*CloneResult, err
 	return CloneRepoWithRefresh(gitURL, repoName, branch, false)
 }
 
-// CloneRepoWithRefresh clones a git repository with optional cache refresh
-// If refresh is true, it will fetch the latest changes even if cache exists
-// branch can be empty to use the repository's default branch
-func CloneRepoWithRefresh(gitURL string, repoName string, branch string, refresh bool) (*CloneResult, error) {
-	tempDir := getTempDir(repoName + branchSuffix(branch))
-
-	if dirExists(tempDir) {
-		if hasGitContent(tempDir) {
-			if refresh {
-				if err := updateShallowRepo(tempDir, branch); err != nil {
-					os.RemoveAll(tempDir)
-				} else {
-					return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
-				}
-			} else {
-				return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
-			}
-		} else {
-			// Cache exists but is corrupted — try to remove
-			if err := os.RemoveAll(tempDir); err != nil {
-				// Cannot remove (e.g. owned by another user), use fallback path
-				tempDir = getUserTempDir(repoName + branchSuffix(branch))
-				if dirExists(tempDir) && hasGitContent(tempDir) {
-					if !refresh {
-						return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
-					}
-					os.RemoveAll(tempDir)
-				} else if dirExists(tempDir) {
-					os.RemoveAll(tempDir)
-				}
-			}
-		}
-	}
-
-	if err := os.MkdirAll(filepath.Dir(tempDir), 0755); err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
-	}
-
-	var lastErr error
-	for attempt := 1; attempt <= MaxRetries; attempt++ {
-		result, err := cloneWithTimeout(gitURL, tempDir, branch)
-		if err == nil {
-			return result, nil
-		}
-
-		lastErr = err
-
-		if cloneErr, ok := err.(*CloneError); ok && cloneErr.IsAuthError {
-			return nil, err
-		}
-
-		os.RemoveAll(tempDir)
-
-		if attempt < MaxRetries {
-			time.Sleep(RetryDelay)
-		}
-	}
-
-	return nil, lastErr
-}
-
-// cloneWithTimeout performs a single clone attempt with timeout
-func cloneWithTimeout(gitURL string, tempDir string, branch string) (*CloneResult, error) {
-	args := []string{"clone", "--depth", "1"}
-	if branch != "" {
-		args = append(args, "--branch", branch)
-	}
-	args = append(args, gitURL, tempDir)
-	cmd := exec.Command("git", args...)
-
-	// Set up timeout
-	done := make(chan error, 1)
-	go func() {
-		done <- cmd.Run()
-	}()
-
-	select {
-	case err := <-done:
-		if err != nil {
-			return nil, parseGitError(err, gitURL)
-		}
-	case <-time.After(CloneTimeout):
-		// Kill the process on timeout
-		if cmd.Process != nil {
-			cmd.Process.Kill()
-		}
-		return nil, &CloneError{
-			URL:       gitURL,
-			Message:   fmt.Sprintf("Clone timed out after %v. Check your network or credentials.", CloneTimeout),
-			IsTimeout: true,
-		}
-	}
-
-	return &CloneResult{
-		TempDir: tempDir,
-		Repo:    filepath.Base(tempDir),
-	}, nil
-}
-
-// SparseCloneRepo clones only specific directories from a git repository
-// This is much faster for large repositories when you only need specific paths
-// branch can be empty to use the repository's default branch
-func SparseCloneRepo(gitURL string, repoName string, branch string, sparsePaths []string) (*CloneResult, error) {
-	tempDir := getTempDirSparse(repoName+branchSuffix(branch), sparsePaths)
-
-	if dirExists(tempDir) {
-		if hasGitContent(tempDir) {
-			return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
-		}
-		if err := os.RemoveAll(tempDir); err != nil {
-			tempDir = getUserTempDirSparse(repoName, sparsePaths)
-			if dirExists(tempDir) && hasGitContent(tempDir) {
-				return &CloneResult{TempDir: tempDir, Repo: repoName}, nil
-			}
-			if dirExists(tempDir) {
-				os.RemoveAll(tempDir)
-			}
-		}
-	}
-
-	if err := os.MkdirAll(filepath.Dir(tempDir), 0755); err != nil {
-		return nil, fmt.Errorf("failed to create temp directory: %w", err)
-	}
-
-	// Step 1: Initialize empty repo with sparse checkout
-	if err := runWithTimeout(exec.Command("git", "init", tempDir), SparseCloneTimeout); err != nil {
-		os.RemoveAll(tempDir)
-		return nil, fmt.Errorf("failed to init repo: %w", err)
-	}
-
-	// Step 2: Add remote
-	if err := runWithTimeout(exec.Command("git", "-C", tempDir, "remote", "add", "origin", gitURL), SparseCloneTimeout); err != nil {
-		os.RemoveAll(tempDir)
-		return nil, fmt.Errorf("failed to add remote: %w", err)
-	}
-
-	// Step 3: Enable sparse checkout
-	if err := runWithTimeout(exec.Command("git", "-C", tempDir, "config", "core.sparseCheckout", "true"), SparseCloneTimeout); err != nil {
-		os.RemoveAll(tempDir)
-		return nil, fmt.Errorf("failed to enable sparse checkout: %w", err)
-	}
-
-	// Step 4: Configure sparse checkout paths
-	sparseCheckoutFile := filepath.Join(tempDir, ".git", "info", "sparse-checkout")
-	if err := os.MkdirAll(filepath.Dir(sparseCheckoutFile), 0755); err != nil {
-		os.RemoveAll(tempDir)
-		return nil, fmt.Errorf("failed to create sparse-checkout dir: %w", err)
-	}
-
-	// Write sparse paths to config
-	sparseContent := strings.Join(sparsePaths, "\n") + "\n"
-	if err := os.WriteFile(sparseCheckoutFile, []byte(sparseContent), 0644); err != nil {
-		os.RemoveAll(tempDir)
-		return nil, fmt.Errorf("failed to write sparse-checkout config: %w", err)
-	}
-
-	// Step 5: Fetch only the needed data (depth 1)
-	fetchRef := "HEAD"
-	if branch != "" {
-		fetchRef = branch
-	}
-	fetchCmd := exec.Command("git", "-C", tempDir, "fetch", "--depth", "1", "origin", fetchRef)
-	if err := runWithTimeout(fetchCmd, CloneTimeout); err != nil {
-		os.RemoveAll(tempDir)
-		return nil, parseGitError(err, gitURL)
-	}
-
-	// Step 6: Checkout
-	checkoutCmd := exec.Command("git", "-C", tempDir, "checkout", "FETCH_HEAD")
-	if err := runWithTimeout(checkoutCmd, SparseCloneTimeout); err != nil {
-		os.RemoveAll(tempDir)
-		return nil, fmt.Errorf("failed to checkout: %w", err)
-	}
-
-	return &CloneResult{
-		TempDir: tempDir,
-		Repo:    repoName,
-	}, nil
-}
-
-// runWithTimeout runs a command with a timeout
-func runWithTimeout(cmd *exec.Cmd, timeout time.Duration) error {
-	done := make(chan error, 1)
-	go func() {
-		done <- cmd.Run()
-	}()
-
-	select {
-	case err := <-done:
-		return err
-	case <-time.After(timeout):
-		if cmd.Process != nil {
-			cmd.Process.Kill()
-		}
-		return &CloneError{
-			Message:   fmt.Sprintf("command timed out after %v", timeout),
-			IsTimeout: true,
-		}
-	}
-}
-
 // CleanupTempDir removes a temporary directory
 // Only removes directories within the system temp directory for safety
 func CleanupTempDir(dir string) error {
@@ -285,7 +129,7 @@ func CleanupAllSkillsDirs() error {
 	}
 
 	for _, entry := range entries {
-		if entry.IsDir() && strings.HasPrefix(entry.Name(), TempDirPrefix) {
+		if strings.HasPrefix(entry.Name(), TempDirPrefix) {
 			path := filepath.Join(tmpDir, entry.Name())
 			os.RemoveAll(path)
 		}
@@ -302,6 +146,7 @@ func getTempDir(repoName string) string {
 	// Replace special characters
 	safeName := strings.ReplaceAll(repoName, "/", "-")
 	safeName = strings.ReplaceAll(safeName, ".", "-")
+	safeName = strings.ReplaceAll(safeName, ":", "-")
 
 	return filepath.Join(os.TempDir(), TempDirPrefix+safeName+"-"+shortHash)
 }
@@ -316,6 +161,7 @@ func getTempDirSparse(repoName string, sparsePaths []string) string {
 	// Replace special characters
 	safeName := strings.ReplaceAll(repoName, "/", "-")
 	safeName = strings.ReplaceAll(safeName, ".", "-")
+	safeName = strings.ReplaceAll(safeName, ":", "-")
 
 	return filepath.Join(os.TempDir(), TempDirPrefix+safeName+"-sparse-"+shortHash)
 }
@@ -335,6 +181,7 @@ func getUserTempDir(repoName string) string {
 	shortHash := fmt.Sprintf("%x", hash[:4])
 	safeName := strings.ReplaceAll(repoName, "/", "-")
 	safeName = strings.ReplaceAll(safeName, ".", "-")
+	safeName = strings.ReplaceAll(safeName, ":", "-")
 	return filepath.Join(userCacheDir(), TempDirPrefix+safeName+"-"+shortHash)
 }
 
@@ -345,6 +192,7 @@ func getUserTempDirSparse(repoName string, sparsePaths []string) string {
 	shortHash := fmt.Sprintf("%x", hash[:4])
 	safeName := strings.ReplaceAll(repoName, "/", "-")
 	safeName = strings.ReplaceAll(safeName, ".", "-")
+	safeName = strings.ReplaceAll(safeName, ":", "-")
 	return filepath.Join(userCacheDir(), TempDirPrefix+safeName+"-sparse-"+shortHash)
 }
 
@@ -376,68 +224,6 @@ func hasGitContent(path string) bool {
 	return !info.IsDir()
 }
 
-// pullRepo pulls latest changes in a repo (for non-shallow clones)
-func pullRepo(dir string) error {
-	cmd := exec.Command("git", "-C", dir, "pull", "--ff-only")
-	return cmd.Run()
-}
-
-// updateShallowRepo updates a shallow clone by fetching latest and resetting
-// This is the correct way to update a --depth 1 clone
-func updateShallowRepo(dir string, branch string) error {
-	// Fetch latest with depth 1
-	fetchArgs := []string{"-C", dir, "fetch", "--depth", "1", "origin"}
-	if branch != "" {
-		fetchArgs = append(fetchArgs, branch)
-	}
-	fetchCmd := exec.Command("git", fetchArgs...)
-	if err := runWithTimeout(fetchCmd, CloneTimeout); err != nil {
-		return err
-	}
-
-	if branch != "" {
-		resetCmd := exec.Command("git", "-C", dir, "reset", "--hard", "origin/"+branch)
-		return runWithTimeout(resetCmd, SparseCloneTimeout)
-	}
-
-	// Get the default branch name
-	branchCmd := exec.Command("git", "-C", dir, "symbolic-ref", "refs/remotes/origin/HEAD", "--short")
-	output, err := branchCmd.Output()
-	if err != nil {
-		// Fallback: try to reset to origin/main or origin/master
-		resetCmd := exec.Command("git", "-C", dir, "reset", "--hard", "origin/HEAD")
-		return runWithTimeout(resetCmd, SparseCloneTimeout)
-	}
-
-	// Reset to the fetched head
-	defaultBranch := strings.TrimSpace(string(output))
-	resetCmd := exec.Command("git", "-C", dir, "reset", "--hard", defaultBranch)
-	return runWithTimeout(resetCmd, SparseCloneTimeout)
-}
-
-// parseGitError converts git errors to CloneError
-func parseGitError(err error, url string) *CloneError {
-	msg := err.Error()
-
-	isAuth := strings.Contains(msg, "Authentication failed") ||
-		strings.Contains(msg, "could not read Username") ||
-		strings.Contains(msg, "Permission denied") ||
-		strings.Contains(msg, "Repository not found")
-
-	if isAuth {
-		return &CloneError{
-			URL:         url,
-			Message:     fmt.Sprintf("Authentication failed for %s. Check your credentials or repository access.", url),
-			IsAuthError: true,
-		}
-	}
-
-	return &CloneError{
-		URL:     url,
-		Message: fmt.Sprintf("Failed to clone %s: %v", url, err),
-	}
-}
-
 // GetCachedDir returns the cached directory path if it exists
 func GetCachedDir(repoName string) (string, bool) {
 	tempDir := getTempDir(repoName)
@@ -464,12 +250,54 @@ func GetCachedDirSparse(repoName string, sparsePaths []string) (string, bool) {
 	return "", false
 }
 
-// GetRepoHeadCommit returns the short HEAD commit hash of a git repository
-func GetRepoHeadCommit(repoDir string) (string, error) {
-	cmd := exec.Command("git", "-C", repoDir, "rev-parse", "--short", "HEAD")
-	out, err := cmd.Output()
-	if err != nil {
-		return "", err
+// CachedCheckouts returns every cached checkout of repoName, whichever
+// backend, branch, ref or sparse path set produced it, most recently
+// modified first. Nothing is fetched.
+func CachedCheckouts(repoName string) []string {
+	safeName := strings.ReplaceAll(repoName, "/", "-")
+	safeName = strings.ReplaceAll(safeName, ".", "-")
+	safeName = strings.ReplaceAll(safeName, ":", "-")
+	// The cache key may carry "@branch" or "#ref" after the repo name
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(TempDirPrefix+safeName) + `([@#][^/]*)?(-sparse)?-[0-9a-f]{8}$`)
+
+	type checkout struct {
+		path    string
+		modTime time.Time
+	}
+	var found []checkout
+	for _, base := range []string{os.TempDir(), userCacheDir()} {
+		entries, err := os.ReadDir(base)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || !pattern.MatchString(entry.Name()) {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			found = append(found, checkout{filepath.Join(base, entry.Name()), info.ModTime()})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].modTime.After(found[j].modTime) })
+	dirs := make([]string, len(found))
+	for i, c := range found {
+		dirs[i] = c.path
+	}
+	return dirs
+}
+
+// IsCommitSHA reports whether ref looks like an abbreviated or full commit SHA
+func IsCommitSHA(ref string) bool {
+	if len(ref) < 7 || len(ref) > 40 {
+		return false
+	}
+	for _, c := range ref {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
 	}
-	return strings.TrimSpace(string(out)), nil
+	return true
 }
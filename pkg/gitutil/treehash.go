@@ -0,0 +1,116 @@
+package gitutil
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// emptyTreeHash is git's hash of a tree with no entries
+const emptyTreeHash = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// treeHash computes the git tree hash of path inside a checkout from the
+// files on disk, giving the same result as `git rev-parse HEAD:<path>` on an
+// unmodified clone. Empty directories are skipped, as git does not track them.
+func treeHash(repoDir string, path string) (string, error) {
+	dir := repoDir
+	if p := strings.Trim(filepath.ToSlash(path), "/"); p != "" && p != "." {
+		dir = filepath.Join(repoDir, filepath.FromSlash(p))
+	}
+	if !dirExists(dir) {
+		return "", fmt.Errorf("path %s does not exist in %s", path, repoDir)
+	}
+	sum, err := hashTree(dir)
+	if err != nil {
+		return "", err
+	}
+	if sum == nil {
+		return emptyTreeHash, nil
+	}
+	return hex.EncodeToString(sum), nil
+}
+
+// treeEntry is one line of a git tree object
+type treeEntry struct {
+	mode string
+	name string
+	sum  []byte
+}
+
+// sortKey orders entries like git: directories compare as if named "name/"
+func (e treeEntry) sortKey() string {
+	if e.mode == "40000" {
+		return e.name + "/"
+	}
+	return e.name
+}
+
+// hashTree returns the tree object hash of dir, or nil when dir holds no files
+func hashTree(dir string) ([]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var tree []treeEntry
+	for _, e := range entries {
+		if e.Name() == ".git" {
+			continue
+		}
+		p := filepath.Join(dir, e.Name())
+		switch {
+		case e.Type()&os.ModeSymlink != 0:
+			target, err := os.Readlink(p)
+			if err != nil {
+				return nil, err
+			}
+			tree = append(tree, treeEntry{"120000", e.Name(), hashObject("blob", []byte(filepath.ToSlash(target)))})
+		case e.IsDir():
+			sum, err := hashTree(p)
+			if err != nil {
+				return nil, err
+			}
+			if sum != nil {
+				tree = append(tree, treeEntry{"40000", e.Name(), sum})
+			}
+		default:
+			info, err := e.Info()
+			if err != nil {
+				return nil, err
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return nil, err
+			}
+			mode := "100644"
+			if info.Mode()&0111 != 0 {
+				mode = "100755"
+			}
+			tree = append(tree, treeEntry{mode, e.Name(), hashObject("blob", data)})
+		}
+	}
+	if len(tree) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(tree, func(i, j int) bool { return tree[i].sortKey() < tree[j].sortKey() })
+	var buf bytes.Buffer
+	for _, e := range tree {
+		fmt.Fprintf(&buf, "%s %s\x00", e.mode, e.name)
+		buf.Write(e.sum)
+	}
+	return hashObject("tree", buf.Bytes()), nil
+}
+
+// hashObject returns the raw SHA-1 git assigns to an object
+func hashObject(kind string, data []byte) []byte {
+	h := sha1.New()
+	fmt.Fprintf(h, "%s %d\x00", kind, len(data))
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,356 @@
+// Package installtx installs skill directories atomically.
+//
+// Each install is staged into a temporary directory next to the destination
+// and swapped in with a rename, so an interrupted copy never leaves a
+// half-written skill behind. The version being replaced is moved to
+// <target>/.skills-x-backup/<name> together with its skills-x.lock entry.
+// A Tx restores every backup it made when the batch is aborted; after a
+// successful batch the backups stay as the "last version" for Rollback.
+package installtx
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/castle-x/skills-x/pkg/fsutil"
+	"github.com/castle-x/skills-x/pkg/lockfile"
+	"gopkg.in/yaml.v3"
+)
+
+// BackupDirName is the directory, inside the install target, holding the
+// previous version of each replaced or removed skill
+const BackupDirName = ".skills-x-backup"
+
+// ErrNoBackup is returned by Rollback when a skill has no backup
+var ErrNoBackup = errors.New("no backup found")
+
+// ErrClosed is returned when a Tx is used after Commit or Abort
+var ErrClosed = errors.New("install transaction already finished")
+
+// change is one swap made by a Tx, undone in reverse order on Abort
+type change struct {
+	skillDir string
+	backedUp bool // false when the skill did not exist before
+}
+
+// Tx groups the installs of one batch
+type Tx struct {
+	mu      sync.Mutex
+	changes []change
+	closed  bool
+}
+
+// New starts a batch
+func New() *Tx {
+	return &Tx{}
+}
+
+// BackupPath returns where the previous version of skillDir is kept
+func BackupPath(skillDir string) string {
+	return filepath.Join(filepath.Dir(skillDir), BackupDirName, filepath.Base(skillDir))
+}
+
+// HasBackup reports whether skillDir has a previous version to roll back to
+func HasBackup(skillDir string) bool {
+	return fsutil.DirExists(BackupPath(skillDir))
+}
+
+// Install fills a staging directory through fill and swaps it in at
+// skillDir. If fill fails the destination is left untouched and fill's
+// error is returned as is. Installs of different skills may run
+// concurrently; only the swap itself is serialized.
+func (tx *Tx) Install(skillDir string, fill func(stageDir string) error) error {
+	if tx.isClosed() {
+		return ErrClosed
+	}
+
+	parent := filepath.Dir(skillDir)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+	stage, err := os.MkdirTemp(parent, "."+filepath.Base(skillDir)+".staging-")
+	if err != nil {
+		return err
+	}
+	if err := fill(stage); err != nil {
+		os.RemoveAll(stage)
+		return err
+	}
+
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.closed {
+		// The batch was aborted while this install was being staged
+		os.RemoveAll(stage)
+		return ErrClosed
+	}
+
+	backedUp, err := backup(skillDir)
+	if err != nil {
+		os.RemoveAll(stage)
+		return err
+	}
+	if err := os.Rename(stage, skillDir); err != nil {
+		os.RemoveAll(stage)
+		if backedUp {
+			_ = restore(skillDir)
+		}
+		return fmt.Errorf("failed to move staged skill into place: %w", err)
+	}
+
+	tx.changes = append(tx.changes, change{skillDir: skillDir, backedUp: backedUp})
+	return nil
+}
+
+func (tx *Tx) isClosed() bool {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	return tx.closed
+}
+
+// Remove moves skillDir to its backup location instead of deleting it
+func (tx *Tx) Remove(skillDir string) error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.closed {
+		return ErrClosed
+	}
+
+	backedUp, err := backup(skillDir)
+	if err != nil || !backedUp {
+		return err
+	}
+	tx.changes = append(tx.changes, change{skillDir: skillDir, backedUp: true})
+	return nil
+}
+
+// Commit ends the batch and keeps the backups for Rollback
+func (tx *Tx) Commit() {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	tx.closed = true
+	tx.changes = nil
+}
+
+// Abort undoes every change of the batch, newest first: replaced and
+// removed skills are restored from their backups (with their lockfile
+// entries) and newly installed skills are deleted. Installs still being
+// staged are discarded when they finish.
+func (tx *Tx) Abort() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	if tx.closed {
+		return nil
+	}
+	tx.closed = true
+
+	var errs []error
+	for i := len(tx.changes) - 1; i >= 0; i-- {
+		c := tx.changes[i]
+		if c.backedUp {
+			if err := restore(c.skillDir); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		if err := os.RemoveAll(c.skillDir); err != nil {
+			errs = append(errs, err)
+		}
+		if err := lockfile.Forget(filepath.Dir(c.skillDir), filepath.Base(c.skillDir)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	tx.changes = nil
+	return errors.Join(errs...)
+}
+
+// AbortOnInterrupt aborts the batch and exits when the user presses Ctrl+C
+// or the process is terminated, so skills already replaced are restored.
+// The returned function stops watching.
+func (tx *Tx) AbortOnInterrupt() func() {
+	sig := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sig:
+			fmt.Fprintf(os.Stderr, "\nInterrupted, restoring previous versions...\n")
+			if err := tx.Abort(); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ rollback failed: %v\n", err)
+			}
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(sig)
+		close(done)
+	}
+}
+
+// Rollback swaps skillDir with its backup, so the previous version is
+// installed again and the current one becomes the backup. Running it twice
+// returns to where it started.
+func Rollback(skillDir string) error {
+	backupDir := BackupPath(skillDir)
+	if !fsutil.DirExists(backupDir) {
+		return ErrNoBackup
+	}
+	targetDir := filepath.Dir(skillDir)
+	name := filepath.Base(skillDir)
+
+	current, err := lockEntry(targetDir, name)
+	if err != nil {
+		return err
+	}
+	previous, err := readSidecar(backupDir)
+	if err != nil {
+		return err
+	}
+
+	if fsutil.DirExists(skillDir) {
+		tmp, err := os.MkdirTemp(filepath.Dir(backupDir), "."+name+".rollback-")
+		if err != nil {
+			return err
+		}
+		os.Remove(tmp)
+		if err := os.Rename(skillDir, tmp); err != nil {
+			return err
+		}
+		if err := os.Rename(backupDir, skillDir); err != nil {
+			_ = os.Rename(tmp, skillDir)
+			return err
+		}
+		if err := os.Rename(tmp, backupDir); err != nil {
+			return err
+		}
+	} else if err := os.Rename(backupDir, skillDir); err != nil {
+		return err
+	}
+
+	if err := writeSidecar(backupDir, current); err != nil {
+		return err
+	}
+	return setLockEntry(targetDir, name, previous)
+}
+
+// backup moves skillDir (and its lockfile entry) to the backup location,
+// replacing an older backup. Returns false when there was nothing to move.
+func backup(skillDir string) (bool, error) {
+	if !fsutil.DirExists(skillDir) {
+		return false, nil
+	}
+	backupDir := BackupPath(skillDir)
+	entry, err := lockEntry(filepath.Dir(skillDir), filepath.Base(skillDir))
+	if err != nil {
+		return false, err
+	}
+
+	if err := os.RemoveAll(backupDir); err != nil {
+		return false, err
+	}
+	if err := ensureBackupRoot(filepath.Dir(backupDir)); err != nil {
+		return false, err
+	}
+	if err := os.Rename(skillDir, backupDir); err != nil {
+		return false, fmt.Errorf("failed to back up %s: %w", filepath.Base(skillDir), err)
+	}
+	if err := writeSidecar(backupDir, entry); err != nil {
+		_ = os.Rename(backupDir, skillDir)
+		return false, err
+	}
+	return true, nil
+}
+
+// ensureBackupRoot creates the backup directory with a .gitignore, so
+// backups are not committed along with the installed skills
+func ensureBackupRoot(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	ignore := filepath.Join(dir, ".gitignore")
+	if fsutil.FileExists(ignore) {
+		return nil
+	}
+	return os.WriteFile(ignore, []byte("*\n"), 0644)
+}
+
+// restore moves the backup of skillDir back into place, along with the
+// lockfile entry it had
+func restore(skillDir string) error {
+	backupDir := BackupPath(skillDir)
+	entry, err := readSidecar(backupDir)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(skillDir); err != nil {
+		return err
+	}
+	if err := os.Rename(backupDir, skillDir); err != nil {
+		return err
+	}
+	os.Remove(sidecarPath(backupDir))
+	return setLockEntry(filepath.Dir(skillDir), filepath.Base(skillDir), entry)
+}
+
+// sidecarPath is the file next to a backup holding its lockfile entry
+func sidecarPath(backupDir string) string {
+	return backupDir + ".lock.yaml"
+}
+
+func writeSidecar(backupDir string, entry *lockfile.Entry) error {
+	path := sidecarPath(backupDir)
+	if entry == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := yaml.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func readSidecar(backupDir string) (*lockfile.Entry, error) {
+	data, err := os.ReadFile(sidecarPath(backupDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry lockfile.Entry
+	if err := yaml.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func lockEntry(targetDir string, name string) (*lockfile.Entry, error) {
+	if !lockfile.Exists(targetDir) {
+		return nil, nil
+	}
+	lf, err := lockfile.Load(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	if e := lf.Get(name); e != nil {
+		copied := *e
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func setLockEntry(targetDir string, name string, entry *lockfile.Entry) error {
+	if entry == nil {
+		return lockfile.Forget(targetDir, name)
+	}
+	return lockfile.Record(targetDir, *entry)
+}
@@ -0,0 +1,135 @@
+package installtx
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/lockfile"
+)
+
+func writeSkill(t *testing.T, dir string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(content), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func readSkill(t *testing.T, dir string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return string(data)
+}
+
+func fillWith(content string) func(string) error {
+	return func(stage string) error {
+		return os.WriteFile(filepath.Join(stage, "SKILL.md"), []byte(content), 0644)
+	}
+}
+
+func TestInstall_FailedFillLeavesSkillUntouched(t *testing.T) {
+	target := t.TempDir()
+	skill := filepath.Join(target, "pdf")
+	writeSkill(t, skill, "v1")
+
+	tx := New()
+	boom := errors.New("boom")
+	if err := tx.Install(skill, func(string) error { return boom }); !errors.Is(err, boom) {
+		t.Fatalf("err = %v; want fill error", err)
+	}
+	if got := readSkill(t, skill); got != "v1" {
+		t.Fatalf("SKILL.md = %q; want v1", got)
+	}
+	entries, _ := os.ReadDir(target)
+	if len(entries) != 1 {
+		t.Fatalf("staging directory left behind: %v", entries)
+	}
+}
+
+func TestAbort_RestoresBatch(t *testing.T) {
+	target := t.TempDir()
+	pdf := filepath.Join(target, "pdf")
+	docx := filepath.Join(target, "docx")
+	fresh := filepath.Join(target, "fresh")
+	writeSkill(t, pdf, "pdf v1")
+	writeSkill(t, docx, "docx v1")
+	if err := lockfile.Record(target, lockfile.Entry{Name: "pdf", Repo: "r", Commit: "old"}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+
+	tx := New()
+	if err := tx.Install(pdf, fillWith("pdf v2")); err != nil {
+		t.Fatalf("install pdf: %v", err)
+	}
+	_ = lockfile.Record(target, lockfile.Entry{Name: "pdf", Repo: "r", Commit: "new"})
+	if err := tx.Install(fresh, fillWith("fresh")); err != nil {
+		t.Fatalf("install fresh: %v", err)
+	}
+	if err := tx.Remove(docx); err != nil {
+		t.Fatalf("remove docx: %v", err)
+	}
+
+	if err := tx.Abort(); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+	if got := readSkill(t, pdf); got != "pdf v1" {
+		t.Errorf("pdf = %q; want pdf v1", got)
+	}
+	if got := readSkill(t, docx); got != "docx v1" {
+		t.Errorf("docx = %q; want docx v1", got)
+	}
+	if _, err := os.Stat(fresh); !os.IsNotExist(err) {
+		t.Errorf("fresh install should be removed, stat err = %v", err)
+	}
+	lf, _ := lockfile.Load(target)
+	if e := lf.Get("pdf"); e == nil || e.Commit != "old" {
+		t.Errorf("lockfile entry not restored: %+v", e)
+	}
+	if err := tx.Install(pdf, fillWith("x")); !errors.Is(err, ErrClosed) {
+		t.Errorf("Install after Abort = %v; want ErrClosed", err)
+	}
+}
+
+func TestRollback_SwapsWithBackup(t *testing.T) {
+	target := t.TempDir()
+	pdf := filepath.Join(target, "pdf")
+	writeSkill(t, pdf, "v1")
+	_ = lockfile.Record(target, lockfile.Entry{Name: "pdf", Repo: "r", Commit: "c1"})
+
+	if err := Rollback(pdf); !errors.Is(err, ErrNoBackup) {
+		t.Fatalf("Rollback without backup = %v; want ErrNoBackup", err)
+	}
+
+	tx := New()
+	if err := tx.Install(pdf, fillWith("v2")); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	_ = lockfile.Record(target, lockfile.Entry{Name: "pdf", Repo: "r", Commit: "c2"})
+	tx.Commit()
+
+	if err := Rollback(pdf); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if got := readSkill(t, pdf); got != "v1" {
+		t.Fatalf("after rollback SKILL.md = %q; want v1", got)
+	}
+	lf, _ := lockfile.Load(target)
+	if e := lf.Get("pdf"); e == nil || e.Commit != "c1" {
+		t.Fatalf("lockfile after rollback = %+v; want c1", e)
+	}
+
+	// A second rollback returns to the newer version
+	if err := Rollback(pdf); err != nil {
+		t.Fatalf("second Rollback: %v", err)
+	}
+	if got := readSkill(t, pdf); got != "v2" {
+		t.Fatalf("after second rollback SKILL.md = %q; want v2", got)
+	}
+}
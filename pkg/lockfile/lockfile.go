@@ -0,0 +1,187 @@
+// Package lockfile reads and writes skills-x.lock, the project lockfile that
+// pins every installed skill to the exact commit it was installed from.
+//
+// The lockfile lives next to the installed skills (the install target
+// directory) so it can be committed together with them. "skills-x install"
+// reads it back and restores byte-identical skills on another machine.
+package lockfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the lockfile inside the target directory
+const FileName = "skills-x.lock"
+
+// CurrentVersion is the lockfile format version written by this build
+const CurrentVersion = 1
+
+const fileHeader = `# This file is generated by skills-x. Do not edit it by hand.
+# Commit it and run "skills-x install" to restore exactly these skills.
+`
+
+// Entry pins one installed skill to a source commit.
+type Entry struct {
+	Name    string `yaml:"name"`              // Installed skill name (directory name)
+	Source  string `yaml:"source"`            // Registry source name (e.g. "anthropic")
+	Repo    string `yaml:"repo"`              // Repository (e.g. "github.com/anthropics/skills")
+	Path    string `yaml:"path,omitempty"`    // Skill path inside the repository
+	Branch  string `yaml:"branch,omitempty"`  // Branch the commit was taken from (empty = default)
+	Ref     string `yaml:"ref,omitempty"`     // Tag or commit the skill was pinned to, if any
+	Commit  string `yaml:"commit,omitempty"`  // Full commit SHA
+	Archive string `yaml:"archive,omitempty"` // Archive URL, when the archive did not record its commit
+}
+
+// Lockfile holds the contents of skills-x.lock.
+type Lockfile struct {
+	Version int     `yaml:"version"`
+	Skills  []Entry `yaml:"skills"`
+}
+
+// FilePath returns the lockfile path for a target directory.
+func FilePath(dir string) string {
+	return filepath.Join(dir, FileName)
+}
+
+// Exists reports whether a lockfile is present in dir.
+func Exists(dir string) bool {
+	info, err := os.Stat(FilePath(dir))
+	return err == nil && !info.IsDir()
+}
+
+// Load reads the lockfile from dir.
+// Returns an empty lockfile (not an error) when the file does not exist yet.
+func Load(dir string) (*Lockfile, error) {
+	lf := &Lockfile{Version: CurrentVersion}
+
+	data, err := os.ReadFile(FilePath(dir))
+	if os.IsNotExist(err) {
+		return lf, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading lockfile: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, lf); err != nil {
+		return nil, fmt.Errorf("parsing lockfile: %w", err)
+	}
+	if lf.Version > CurrentVersion {
+		return nil, fmt.Errorf("lockfile version %d is newer than supported version %d, please upgrade skills-x", lf.Version, CurrentVersion)
+	}
+	if lf.Version == 0 {
+		lf.Version = CurrentVersion
+	}
+	return lf, nil
+}
+
+// Save writes the lockfile to dir with entries sorted by name, so the file
+// produces stable diffs in code review.
+func (lf *Lockfile) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating lockfile dir: %w", err)
+	}
+
+	lf.Version = CurrentVersion
+	sort.Slice(lf.Skills, func(i, j int) bool {
+		return strings.ToLower(lf.Skills[i].Name) < strings.ToLower(lf.Skills[j].Name)
+	})
+
+	data, err := yaml.Marshal(lf)
+	if err != nil {
+		return fmt.Errorf("encoding lockfile: %w", err)
+	}
+
+	// Write to a temporary file and rename it, so concurrent readers never
+	// see a half-written lockfile
+	tmp, err := os.CreateTemp(dir, "."+FileName+"-*")
+	if err != nil {
+		return fmt.Errorf("writing lockfile: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(append([]byte(fileHeader), data...)); err != nil {
+		tmp.Close()
+		return fmt.Errorf("writing lockfile: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("writing lockfile: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("writing lockfile: %w", err)
+	}
+	return os.Rename(tmp.Name(), FilePath(dir))
+}
+
+// Get returns the entry for a skill name (case-insensitive), or nil.
+func (lf *Lockfile) Get(name string) *Entry {
+	for i := range lf.Skills {
+		if strings.EqualFold(lf.Skills[i].Name, name) {
+			return &lf.Skills[i]
+		}
+	}
+	return nil
+}
+
+// Set adds an entry or replaces the existing entry with the same name.
+func (lf *Lockfile) Set(entry Entry) {
+	if existing := lf.Get(entry.Name); existing != nil {
+		*existing = entry
+		return
+	}
+	lf.Skills = append(lf.Skills, entry)
+}
+
+// Remove deletes the entry for a skill name. Returns false if it was not present.
+func (lf *Lockfile) Remove(name string) bool {
+	for i := range lf.Skills {
+		if strings.EqualFold(lf.Skills[i].Name, name) {
+			lf.Skills = append(lf.Skills[:i], lf.Skills[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// updateMu serializes Record and Forget, so parallel installs in one
+// process do not lose each other's entries.
+var updateMu sync.Mutex
+
+// Record loads the lockfile in dir, sets entry and saves it again.
+// It is safe for concurrent use.
+func Record(dir string, entry Entry) error {
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
+	lf, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	lf.Set(entry)
+	return lf.Save(dir)
+}
+
+// Forget removes a skill from the lockfile in dir.
+// It is a no-op when there is no lockfile or the skill is not listed.
+// It is safe for concurrent use.
+func Forget(dir string, name string) error {
+	updateMu.Lock()
+	defer updateMu.Unlock()
+
+	if !Exists(dir) {
+		return nil
+	}
+	lf, err := Load(dir)
+	if err != nil {
+		return err
+	}
+	if !lf.Remove(name) {
+		return nil
+	}
+	return lf.Save(dir)
+}
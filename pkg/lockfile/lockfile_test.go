@@ -0,0 +1,108 @@
+package lockfile
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoad_MissingReturnsEmpty(t *testing.T) {
+	lf, err := Load(t.TempDir())
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(lf.Skills) != 0 {
+		t.Fatalf("expected empty lockfile, got %d entries", len(lf.Skills))
+	}
+	if lf.Version != CurrentVersion {
+		t.Fatalf("Version = %d; want %d", lf.Version, CurrentVersion)
+	}
+}
+
+func TestRecord_RoundTripSorted(t *testing.T) {
+	dir := t.TempDir()
+
+	entries := []Entry{
+		{Name: "pdf", Source: "anthropic", Repo: "github.com/anthropics/skills", Path: "skills/pdf", Commit: strings.Repeat("a", 40)},
+		{Name: "brainstorming", Source: "superpowers", Repo: "github.com/obra/superpowers", Path: "skills/brainstorming", Commit: strings.Repeat("b", 40)},
+	}
+	for _, e := range entries {
+		if err := Record(dir, e); err != nil {
+			t.Fatalf("Record(%s): %v", e.Name, err)
+		}
+	}
+
+	lf, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(lf.Skills) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(lf.Skills))
+	}
+	if lf.Skills[0].Name != "brainstorming" || lf.Skills[1].Name != "pdf" {
+		t.Fatalf("entries not sorted by name: %+v", lf.Skills)
+	}
+
+	data, err := os.ReadFile(FilePath(dir))
+	if err != nil {
+		t.Fatalf("read lockfile: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "# This file is generated by skills-x") {
+		t.Fatalf("expected generated header, got:\n%s", data)
+	}
+}
+
+func TestRecord_ReplacesExistingEntry(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := Record(dir, Entry{Name: "pdf", Repo: "github.com/anthropics/skills", Commit: "old"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := Record(dir, Entry{Name: "PDF", Repo: "github.com/anthropics/skills", Commit: "new"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	lf, _ := Load(dir)
+	if len(lf.Skills) != 1 {
+		t.Fatalf("expected 1 entry after replace, got %d", len(lf.Skills))
+	}
+	if lf.Skills[0].Commit != "new" {
+		t.Fatalf("Commit = %q; want %q", lf.Skills[0].Commit, "new")
+	}
+}
+
+func TestForget(t *testing.T) {
+	dir := t.TempDir()
+
+	// No lockfile: Forget must not create one.
+	if err := Forget(dir, "pdf"); err != nil {
+		t.Fatalf("Forget without lockfile: %v", err)
+	}
+	if Exists(dir) {
+		t.Fatal("Forget should not create a lockfile")
+	}
+
+	_ = Record(dir, Entry{Name: "pdf", Commit: "a"})
+	_ = Record(dir, Entry{Name: "docx", Commit: "b"})
+	if err := Forget(dir, "pdf"); err != nil {
+		t.Fatalf("Forget: %v", err)
+	}
+
+	lf, _ := Load(dir)
+	if lf.Get("pdf") != nil {
+		t.Fatal("pdf should have been removed")
+	}
+	if lf.Get("docx") == nil {
+		t.Fatal("docx should remain")
+	}
+}
+
+func TestLoad_RejectsNewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(FilePath(dir), []byte("version: 99\nskills: []\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := Load(dir); err == nil {
+		t.Fatal("expected error for unsupported lockfile version")
+	}
+}
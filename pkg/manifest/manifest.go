@@ -0,0 +1,159 @@
+// Package manifest parses skills.yaml, the checked-in project manifest that
+// declares which skills a repository needs and for which products.
+//
+// Example:
+//
+//	products: [Claude Code, Cursor]
+//	skills:
+//	  - pdf
+//	  - name: brainstorming
+//	    source: superpowers
+//	  - name: frontend-design
+//	    products: [Cursor]
+//	    commit: 0123abcd
+//
+// "skills-x sync" reconciles the project against this file.
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/castle-x/skills-x/pkg/products"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the default manifest file name in the project root
+const FileName = "skills.yaml"
+
+var commitRe = regexp.MustCompile(`^[0-9a-fA-F]{7,40}$`)
+
+// Skill declares one skill the project needs.
+type Skill struct {
+	Name     string   `yaml:"name"`
+	Source   string   `yaml:"source,omitempty"`   // Registry source name, required when the name is ambiguous
+	Products []string `yaml:"products,omitempty"` // Overrides the manifest-level products
+	Branch   string   `yaml:"branch,omitempty"`   // Branch to track instead of the source default
+	Commit   string   `yaml:"commit,omitempty"`   // Pin to an exact commit
+}
+
+// UnmarshalYAML accepts either a bare skill name or a full mapping.
+func (s *Skill) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		s.Name = strings.TrimSpace(node.Value)
+		return nil
+	}
+	type plain Skill
+	var p plain
+	if err := node.Decode(&p); err != nil {
+		return err
+	}
+	*s = Skill(p)
+	return nil
+}
+
+// Manifest holds the contents of skills.yaml.
+type Manifest struct {
+	Products []string `yaml:"products,omitempty"` // Default products for every skill
+	Skills   []Skill  `yaml:"skills"`
+}
+
+// Load reads and validates a manifest file.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses and validates manifest content.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", FileName, err)
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks names, products and pins.
+func (m *Manifest) Validate() error {
+	for _, p := range m.Products {
+		if products.GetProductByName(p) == nil {
+			return fmt.Errorf("unknown product %q", p)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, s := range m.Skills {
+		if s.Name == "" {
+			return fmt.Errorf("skills[%d]: missing name", i)
+		}
+		key := strings.ToLower(s.Name)
+		if seen[key] {
+			return fmt.Errorf("skill %q is listed more than once", s.Name)
+		}
+		seen[key] = true
+
+		for _, p := range s.Products {
+			if products.GetProductByName(p) == nil {
+				return fmt.Errorf("skill %q: unknown product %q", s.Name, p)
+			}
+		}
+		if len(m.ProductsFor(s)) == 0 {
+			return fmt.Errorf("skill %q: no products configured (set top-level products or per-skill products)", s.Name)
+		}
+		if s.Commit != "" && !commitRe.MatchString(s.Commit) {
+			return fmt.Errorf("skill %q: invalid commit %q", s.Name, s.Commit)
+		}
+	}
+	return nil
+}
+
+// ProductsFor returns the product names a skill should be installed into.
+func (m *Manifest) ProductsFor(s Skill) []string {
+	if len(s.Products) > 0 {
+		return s.Products
+	}
+	return m.Products
+}
+
+// AllProducts returns every product referenced by the manifest, deduplicated
+// case-insensitively and in first-seen order.
+func (m *Manifest) AllProducts() []string {
+	var out []string
+	seen := make(map[string]bool)
+	add := func(names []string) {
+		for _, n := range names {
+			key := strings.ToLower(n)
+			if !seen[key] {
+				seen[key] = true
+				out = append(out, n)
+			}
+		}
+	}
+	add(m.Products)
+	for _, s := range m.Skills {
+		add(s.Products)
+	}
+	return out
+}
+
+// SkillsFor returns the skills that should be installed into a product.
+func (m *Manifest) SkillsFor(product string) []Skill {
+	var out []Skill
+	for _, s := range m.Skills {
+		for _, p := range m.ProductsFor(s) {
+			if strings.EqualFold(p, product) {
+				out = append(out, s)
+				break
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package manifest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_ScalarAndMappingEntries(t *testing.T) {
+	data := `
+products: [Claude Code]
+skills:
+  - pdf
+  - name: brainstorming
+    source: superpowers
+    products: [Cursor]
+    commit: 0123abcd
+`
+	m, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(m.Skills) != 2 {
+		t.Fatalf("expected 2 skills, got %d", len(m.Skills))
+	}
+	if m.Skills[0].Name != "pdf" {
+		t.Errorf("Skills[0].Name = %q; want pdf", m.Skills[0].Name)
+	}
+	if m.Skills[1].Source != "superpowers" || m.Skills[1].Commit != "0123abcd" {
+		t.Errorf("unexpected mapping entry: %+v", m.Skills[1])
+	}
+
+	claude := m.SkillsFor("claude code")
+	if len(claude) != 1 || claude[0].Name != "pdf" {
+		t.Errorf("SkillsFor(claude code) = %+v; want [pdf]", claude)
+	}
+	cursor := m.SkillsFor("Cursor")
+	if len(cursor) != 1 || cursor[0].Name != "brainstorming" {
+		t.Errorf("SkillsFor(Cursor) = %+v; want [brainstorming]", cursor)
+	}
+
+	all := m.AllProducts()
+	if len(all) != 2 {
+		t.Errorf("AllProducts = %v; want 2 products", all)
+	}
+}
+
+func TestParse_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name:    "unknown product",
+			data:    "products: [Notepad]\nskills: [pdf]\n",
+			wantErr: "unknown product",
+		},
+		{
+			name:    "duplicate skill",
+			data:    "products: [Cursor]\nskills: [pdf, PDF]\n",
+			wantErr: "more than once",
+		},
+		{
+			name:    "no products",
+			data:    "skills: [pdf]\n",
+			wantErr: "no products",
+		},
+		{
+			name:    "bad commit",
+			data:    "products: [Cursor]\nskills:\n  - name: pdf\n    commit: not-a-sha\n",
+			wantErr: "invalid commit",
+		},
+		{
+			name:    "missing name",
+			data:    "products: [Cursor]\nskills:\n  - source: anthropic\n",
+			wantErr: "missing name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Parse error = %v; want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,10 +1,31 @@
-// Package products provides AI tool product configuration
+// Package products provides AI tool product configuration.
+//
+// The built-in products can be changed in ~/.config/skills-x/products.yaml:
+//
+//	products:
+//	  - name: Team Agents            # a new product
+//	    global: ~/.agents/skills
+//	    project: .agents/skills
+//	    detect:
+//	      files: [~/.agents]         # marker files or directories
+//	      binaries: [agents]         # executables looked up in PATH
+//	    format: agents-md            # also export skills as AGENTS.md sections
+//	  - name: Claude Code            # override a built-in product's paths
+//	    global: ~/work/claude-skills
+//	  - name: Aider                  # hide a built-in product
+//	    hidden: true
 package products
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Product represents an AI tool that supports skills
@@ -12,64 +33,98 @@ type Product struct {
 	Name         string // Display name
 	GlobalSkills string // Global skills directory (~ expansion)
 	ProjectSkills string // Project skills directory
+	Detect       Detect // Hints that the tool is in use on this machine
+	Format       string // Rules format installed skills are also converted to ("" = SKILL.md folders only)
+}
+
+// FormatSkill is the format value that turns conversion off in products.yaml
+const FormatSkill = "skill"
+
+// Detect lists the signs that a product is in use
+type Detect struct {
+	Files    []string `yaml:"files,omitempty"`    // Marker files or directories; ~ is expanded, relative paths are looked up in the project
+	Binaries []string `yaml:"binaries,omitempty"` // Executables looked up in PATH
 }
 
-// AllProducts returns all supported AI tools
-var AllProducts = []Product{
+// Builtin lists the AI tools skills-x knows without configuration; use
+// All for the list merged with products.yaml
+var Builtin = []Product{
 	{
 		Name:         "Claude Code",
 		GlobalSkills: "~/.claude/skills/",
 		ProjectSkills: ".claude/skills/",
+		Detect:       Detect{Files: []string{"~/.claude"}, Binaries: []string{"claude"}},
 	},
 	{
 		Name:         "Cursor",
 		GlobalSkills: "~/.cursor/skills/",
 		ProjectSkills: ".cursor/skills/",
+		Detect:       Detect{Files: []string{"~/.cursor"}, Binaries: []string{"cursor"}},
+		Format:       "cursor",
 	},
 	{
 		Name:         "Windsurf",
 		GlobalSkills: "~/.windsurf/skills/",
 		ProjectSkills: ".windsurf/skills/",
+		Detect:       Detect{Files: []string{"~/.codeium/windsurf"}, Binaries: []string{"windsurf"}},
+		Format:       "windsurf",
 	},
 	{
 		Name:         "Trae",
 		GlobalSkills: "~/.trae/skills/",
 		ProjectSkills: ".trae/skills/",
+		Detect:       Detect{Files: []string{"~/.trae"}, Binaries: []string{"trae"}},
 	},
 	{
 		Name:         "Qoder",
 		GlobalSkills: "~/.qoder/skills/",
 		ProjectSkills: ".qoder/skills/",
+		Detect:       Detect{Files: []string{"~/.qoder"}, Binaries: []string{"qoder"}},
 	},
 	{
 		Name:         "CodeX",
 		GlobalSkills: "~/.codex/skills/",
 		ProjectSkills: ".codex/skills/",
+		Detect:       Detect{Files: []string{"~/.codex"}, Binaries: []string{"codex"}},
 	},
 	{
 		Name:         "Kimi",
 		GlobalSkills: "~/.kimi/skills/",
 		ProjectSkills: ".kimi/skills/",
+		Detect:       Detect{Files: []string{"~/.kimi"}, Binaries: []string{"kimi"}},
 	},
 	{
 		Name:         "CodeBuddy",
 		GlobalSkills: "~/.codebuddy/skills/",
 		ProjectSkills: ".codebuddy/skills/",
+		Detect:       Detect{Files: []string{"~/.codebuddy"}, Binaries: []string{"codebuddy"}},
 	},
 	{
 		Name:         "Roo Code",
 		GlobalSkills: "~/.roocode/skills/",
 		ProjectSkills: ".roocode/skills/",
+		Detect:       Detect{Files: []string{"~/.roocode"}, Binaries: []string{"roo"}},
+		Format:       "agents-md",
 	},
 	{
 		Name:         "Opencode",
 		GlobalSkills: "~/.config/opencode/skills/",
 		ProjectSkills: ".agents/skills/",
+		Detect:       Detect{Files: []string{"~/.config/opencode"}, Binaries: []string{"opencode"}},
 	},
 	{
 		Name:         "Aider",
 		GlobalSkills: "~/.aider/skills/",
 		ProjectSkills: ".aider/skills/",
+		Detect:       Detect{Files: []string{"~/.aider.conf.yml"}, Binaries: []string{"aider"}},
+		Format:       "agents-md",
+	},
+	{
+		Name:         "GitHub Copilot",
+		GlobalSkills: "~/.copilot/skills/",
+		ProjectSkills: ".github/skills/",
+		Detect:       Detect{Files: []string{".github/copilot-instructions.md"}, Binaries: []string{"copilot"}},
+		Format:       "copilot",
 	},
 }
 
@@ -105,18 +160,269 @@ func (p *Product) IsInstalled() bool {
 	return info.IsDir()
 }
 
-// GetProductByName finds a product by name (case-insensitive)
+// Detected reports whether the product looks in use: its global skills
+// directory exists, or one of its marker files, or one of its binaries is
+// in PATH. Relative marker files are looked up in projectDir.
+func (p *Product) Detected(projectDir string) bool {
+	if p.IsInstalled() {
+		return true
+	}
+	for _, f := range p.Detect.Files {
+		path := ExpandPath(f)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectDir, path)
+		}
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	for _, bin := range p.Detect.Binaries {
+		if _, err := exec.LookPath(bin); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GetProductByName finds a product of All by name, ignoring case, spaces,
+// dashes and underscores ("claude-code" finds Claude Code)
 func GetProductByName(name string) *Product {
-	lowerName := strings.ToLower(name)
-	for i := range AllProducts {
-		if strings.ToLower(AllProducts[i].Name) == lowerName {
-			return &AllProducts[i]
+	return findProduct(All(), name)
+}
+
+// ForDir returns the product dir belongs to: the product whose global
+// skills directory it is, or whose project skills directory it ends in.
+// It returns nil for other directories.
+func ForDir(dir string) *Product {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil
+	}
+	list := All()
+	for i := range list {
+		if filepath.Clean(list[i].GlobalPath()) == abs {
+			return &list[i]
+		}
+	}
+	for i := range list {
+		project := filepath.Clean(list[i].ProjectSkills)
+		if strings.HasSuffix(abs, string(filepath.Separator)+project) {
+			return &list[i]
 		}
 	}
 	return nil
 }
 
+// ProjectRoot returns the project a skills directory of p belongs to: dir
+// with p's project skills directory stripped from its end. It returns false
+// for p's global skills directory and for directories not ending in the
+// project one.
+func (p *Product) ProjectRoot(dir string) (string, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil || abs == filepath.Clean(p.GlobalPath()) {
+		return "", false
+	}
+	project := string(filepath.Separator) + filepath.Clean(p.ProjectSkills)
+	if !strings.HasSuffix(abs, project) {
+		return "", false
+	}
+	root := strings.TrimSuffix(abs, project)
+	if root == "" {
+		root = string(filepath.Separator)
+	}
+	return root, true
+}
+
+func findProduct(list []Product, name string) *Product {
+	key := normalizeName(name)
+	for i := range list {
+		if normalizeName(list[i].Name) == key {
+			return &list[i]
+		}
+	}
+	return nil
+}
+
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// Scopes of a product's skills directory
+const (
+	ScopeGlobal  = "global"  // The user-wide directory, e.g. ~/.claude/skills
+	ScopeProject = "project" // The directory inside the project, e.g. .claude/skills
+)
+
+// Dir returns the product's skills directory at scope. Project directories
+// are resolved against projectDir.
+func (p *Product) Dir(scope string, projectDir string) string {
+	if scope == ScopeGlobal {
+		return p.GlobalPath()
+	}
+	return filepath.Join(projectDir, p.ProjectSkills)
+}
+
+// Target is one skills directory to install into: a product at a scope
+type Target struct {
+	Product *Product
+	Scope   string
+	Dir     string
+}
+
+// ResolveTargets returns a target for every combination of the named
+// products and scopes, in the order given and without duplicates. No scope
+// means the project scope.
+func ResolveTargets(names []string, scopes []string, projectDir string) ([]Target, error) {
+	if len(scopes) == 0 {
+		scopes = []string{ScopeProject}
+	}
+	for _, scope := range scopes {
+		if scope != ScopeGlobal && scope != ScopeProject {
+			return nil, fmt.Errorf("unknown scope %q (want global or project)", scope)
+		}
+	}
+
+	var targets []Target
+	seen := make(map[string]bool)
+	for _, name := range names {
+		p := GetProductByName(name)
+		if p == nil {
+			return nil, fmt.Errorf("unknown product %q", name)
+		}
+		for _, scope := range scopes {
+			dir := p.Dir(scope, projectDir)
+			// Products may share a directory (Opencode uses .agents/skills)
+			if seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			targets = append(targets, Target{Product: p, Scope: scope, Dir: dir})
+		}
+	}
+	return targets, nil
+}
+
 // GetProductCount returns the number of supported products
 func GetProductCount() int {
-	return len(AllProducts)
+	return len(All())
+}
+
+// ConfigFile is the name of the products file in the skills-x config dir
+const ConfigFile = "products.yaml"
+
+// FilePath returns the path of products.yaml
+func FilePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(configDir, "skills-x", ConfigFile)
+}
+
+// productConfig is one entry of products.yaml
+type productConfig struct {
+	Name    string  `yaml:"name"`
+	Global  string  `yaml:"global,omitempty"`
+	Project string  `yaml:"project,omitempty"`
+	Format  string  `yaml:"format,omitempty"`
+	Hidden  bool    `yaml:"hidden,omitempty"`
+	Detect  *Detect `yaml:"detect,omitempty"`
+}
+
+var (
+	loadOnce sync.Once
+	loaded   []Product
+	loadErr  error
+)
+
+// All returns the built-in products merged with products.yaml, read once.
+// When the file is invalid the built-in products are returned and
+// LoadError reports the problem.
+func All() []Product {
+	loadOnce.Do(func() {
+		loaded, loadErr = Load(FilePath())
+		if loadErr != nil {
+			loaded = append([]Product(nil), Builtin...)
+		}
+	})
+	return loaded
+}
+
+// LoadError returns why products.yaml could not be used, if it could not
+func LoadError() error {
+	All()
+	return loadErr
+}
+
+// Load merges the products file at path into the built-in products. Entries
+// naming a built-in product override its paths and detection hints or hide
+// it; other entries add products, which need both paths. A missing file
+// leaves the built-in products unchanged.
+func Load(path string) ([]Product, error) {
+	list := append([]Product(nil), Builtin...)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return list, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Products []productConfig `yaml:"products"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for _, c := range file.Products {
+		if strings.TrimSpace(c.Name) == "" {
+			return nil, fmt.Errorf("%s: product without a name", path)
+		}
+		p := findProduct(list, c.Name)
+		if p == nil {
+			if c.Hidden {
+				continue
+			}
+			if c.Global == "" || c.Project == "" {
+				return nil, fmt.Errorf("%s: new product %q needs both global and project paths", path, c.Name)
+			}
+			list = append(list, Product{Name: c.Name})
+			p = &list[len(list)-1]
+		}
+		if c.Hidden {
+			p.Name = ""
+			continue
+		}
+		if c.Global != "" {
+			p.GlobalSkills = c.Global
+		}
+		if c.Project != "" {
+			p.ProjectSkills = c.Project
+		}
+		if c.Detect != nil {
+			p.Detect = *c.Detect
+		}
+		switch c.Format {
+		case "":
+		case FormatSkill:
+			p.Format = ""
+		default:
+			p.Format = c.Format
+		}
+	}
+
+	// Drop the hidden products
+	merged := list[:0]
+	for _, p := range list {
+		if p.Name != "" {
+			merged = append(merged, p)
+		}
+	}
+	return merged, nil
 }
@@ -0,0 +1,151 @@
+package products
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetProductByName(t *testing.T) {
+	for _, name := range []string{"Claude Code", "claude-code", "CLAUDE_CODE", "claudecode"} {
+		if p := GetProductByName(name); p == nil || p.Name != "Claude Code" {
+			t.Errorf("GetProductByName(%q) = %v", name, p)
+		}
+	}
+	if p := GetProductByName("vim"); p != nil {
+		t.Errorf("GetProductByName(vim) = %v", p)
+	}
+}
+
+func TestResolveTargets(t *testing.T) {
+	t.Setenv("HOME", "/home/dev")
+	targets, err := ResolveTargets([]string{"claude-code", "cursor"}, []string{ScopeGlobal, ScopeProject}, "/work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join("/home/dev", ".claude", "skills"),
+		filepath.Join("/work", ".claude", "skills"),
+		filepath.Join("/home/dev", ".cursor", "skills"),
+		filepath.Join("/work", ".cursor", "skills"),
+	}
+	if len(targets) != len(want) {
+		t.Fatalf("got %d targets; want %d", len(targets), len(want))
+	}
+	for i, tg := range targets {
+		if filepath.Clean(tg.Dir) != want[i] {
+			t.Errorf("target %d = %s (%s); want %s", i, tg.Dir, tg.Scope, want[i])
+		}
+	}
+
+	if targets, _ := ResolveTargets([]string{"Codex"}, nil, "/work"); len(targets) != 1 || targets[0].Scope != ScopeProject {
+		t.Errorf("default scope: %+v", targets)
+	}
+	if _, err := ResolveTargets([]string{"cursor"}, []string{"system"}, "/work"); err == nil {
+		t.Error("unknown scope accepted")
+	}
+	if _, err := ResolveTargets([]string{"vim"}, nil, "/work"); err == nil {
+		t.Error("unknown product accepted")
+	}
+}
+
+func TestProjectRoot(t *testing.T) {
+	t.Setenv("HOME", "/home/dev")
+	custom := &Product{Name: "Tool", GlobalSkills: "~/.tool/skills/", ProjectSkills: "tools/agent/skills/"}
+	tests := []struct {
+		product *Product
+		dir     string
+		want    string
+		ok      bool
+	}{
+		{GetProductByName("Cursor"), "/work/.cursor/skills", "/work", true},
+		{GetProductByName("Cursor"), "/home/dev/.cursor/skills", "", false},
+		{GetProductByName("Cursor"), "/work/skills", "", false},
+		{custom, "/work/tools/agent/skills/", "/work", true},
+		{custom, "/home/dev/.tool/skills", "", false},
+	}
+	for _, tt := range tests {
+		root, ok := tt.product.ProjectRoot(filepath.FromSlash(tt.dir))
+		if ok != tt.ok || root != filepath.FromSlash(tt.want) {
+			t.Errorf("%s.ProjectRoot(%s) = %q, %v; want %q, %v", tt.product.Name, tt.dir, root, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ConfigFile)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	list, err := Load(writeConfig(t, `
+products:
+  - name: Team Agents
+    global: ~/.agents/skills
+    project: .agents/team
+    detect:
+      binaries: [agents]
+  - name: claude-code
+    global: ~/work/claude-skills
+  - name: Aider
+    hidden: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != len(Builtin) {
+		t.Errorf("got %d products; want one added and one hidden", len(list))
+	}
+	if p := findProduct(list, "aider"); p != nil {
+		t.Error("hidden product still listed")
+	}
+	claude := findProduct(list, "Claude Code")
+	if claude == nil || claude.GlobalSkills != "~/work/claude-skills" || claude.ProjectSkills != ".claude/skills/" {
+		t.Errorf("override = %+v; want a new global path only", claude)
+	}
+	team := findProduct(list, "team-agents")
+	if team == nil || team.ProjectSkills != ".agents/team" || len(team.Detect.Binaries) != 1 {
+		t.Errorf("added product = %+v", team)
+	}
+	if Builtin[0].GlobalSkills != "~/.claude/skills/" {
+		t.Error("Load changed the built-in products")
+	}
+
+	if list, err := Load(filepath.Join(t.TempDir(), ConfigFile)); err != nil || len(list) != len(Builtin) {
+		t.Errorf("missing file: %d products, %v", len(list), err)
+	}
+	for _, bad := range []string{
+		"products: [{global: ~/x}]",
+		"products: [{name: Vim, global: ~/.vim/skills}]",
+		"products: {",
+	} {
+		if _, err := Load(writeConfig(t, bad)); err == nil {
+			t.Errorf("Load accepted %q", bad)
+		}
+	}
+}
+
+func TestDetected(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PATH", t.TempDir())
+	project := t.TempDir()
+	p := Product{
+		Name:          "Team Agents",
+		GlobalSkills:  "~/.agents/skills",
+		ProjectSkills: ".agents/skills",
+		Detect:        Detect{Files: []string{"AGENTS.md"}, Binaries: []string{"agents"}},
+	}
+	if p.Detected(project) {
+		t.Fatal("detected with no signs of use")
+	}
+	if err := os.WriteFile(filepath.Join(project, "AGENTS.md"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Detected(project) {
+		t.Error("project marker file not detected")
+	}
+}
@@ -0,0 +1,178 @@
+package registry
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Diff describes how a registry changed between two versions. Sources are
+// identified by their key in Registry.Sources, skills by source key and name.
+type Diff struct {
+	AddedSources   []string
+	RemovedSources []string
+	ChangedSources []SourceChange
+	AddedSkills    []SkillID
+	RemovedSkills  []SkillID
+	ChangedSkills  []SkillChange
+}
+
+// SkillID identifies a skill within a registry
+type SkillID struct {
+	Source string
+	Name   string
+}
+
+// FieldChange is one field that differs between two versions
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// SourceChange lists the fields of a source that changed
+type SourceChange struct {
+	Source string
+	Fields []FieldChange
+}
+
+// SkillChange lists the fields of a skill that changed
+type SkillChange struct {
+	SkillID
+	Fields []FieldChange
+}
+
+// Empty reports whether the two registries are equivalent
+func (d Diff) Empty() bool {
+	return len(d.AddedSources) == 0 && len(d.RemovedSources) == 0 && len(d.ChangedSources) == 0 &&
+		len(d.AddedSkills) == 0 && len(d.RemovedSkills) == 0 && len(d.ChangedSkills) == 0
+}
+
+// DiffRegistries compares two registries. Either may be nil, which reads as
+// an empty registry. Results are sorted so the output is stable.
+func DiffRegistries(old, new *Registry) Diff {
+	var d Diff
+	oldSources, newSources := sourcesOf(old), sourcesOf(new)
+
+	for _, key := range sortedKeys(newSources) {
+		newSrc := newSources[key]
+		oldSrc, existed := oldSources[key]
+		if !existed {
+			d.AddedSources = append(d.AddedSources, key)
+			for _, sk := range newSrc.Skills {
+				d.AddedSkills = append(d.AddedSkills, SkillID{key, sk.Name})
+			}
+			continue
+		}
+		if fields := diffSourceFields(oldSrc, newSrc); len(fields) > 0 {
+			d.ChangedSources = append(d.ChangedSources, SourceChange{key, fields})
+		}
+
+		oldSkills := skillsByName(oldSrc)
+		for _, sk := range newSrc.Skills {
+			prev, ok := oldSkills[sk.Name]
+			if !ok {
+				d.AddedSkills = append(d.AddedSkills, SkillID{key, sk.Name})
+				continue
+			}
+			if fields := diffSkillFields(prev, &sk); len(fields) > 0 {
+				d.ChangedSkills = append(d.ChangedSkills, SkillChange{SkillID{key, sk.Name}, fields})
+			}
+		}
+		newSkills := skillsByName(newSrc)
+		for _, sk := range oldSrc.Skills {
+			if _, ok := newSkills[sk.Name]; !ok {
+				d.RemovedSkills = append(d.RemovedSkills, SkillID{key, sk.Name})
+			}
+		}
+	}
+
+	for _, key := range sortedKeys(oldSources) {
+		if _, ok := newSources[key]; ok {
+			continue
+		}
+		d.RemovedSources = append(d.RemovedSources, key)
+		for _, sk := range oldSources[key].Skills {
+			d.RemovedSkills = append(d.RemovedSkills, SkillID{key, sk.Name})
+		}
+	}
+
+	sortSkillIDs(d.AddedSkills)
+	sortSkillIDs(d.RemovedSkills)
+	sort.Slice(d.ChangedSkills, func(i, j int) bool {
+		return skillIDLess(d.ChangedSkills[i].SkillID, d.ChangedSkills[j].SkillID)
+	})
+	return d
+}
+
+func diffSourceFields(old, new *Source) []FieldChange {
+	var fields []FieldChange
+	fields = appendField(fields, "repo", old.Repo, new.Repo)
+	fields = appendField(fields, "branch", old.Branch, new.Branch)
+	fields = appendField(fields, "ref", old.Ref, new.Ref)
+	fields = appendField(fields, "license", old.License, new.License)
+	fields = appendField(fields, "transport", old.Transport, new.Transport)
+	fields = appendField(fields, "archive", old.Archive, new.Archive)
+	return fields
+}
+
+func diffSkillFields(old, new *Skill) []FieldChange {
+	var fields []FieldChange
+	fields = appendField(fields, "path", old.Path, new.Path)
+	fields = appendField(fields, "ref", old.Ref, new.Ref)
+	fields = appendField(fields, "tags", strings.Join(old.Tags, ","), strings.Join(new.Tags, ","))
+	fields = appendField(fields, "description", old.Description, new.Description)
+	fields = appendField(fields, "description_zh", old.DescriptionZh, new.DescriptionZh)
+	fields = appendField(fields, "version", old.Version, new.Version)
+	fields = appendField(fields, "authors", strings.Join(old.Authors, ","), strings.Join(new.Authors, ","))
+	fields = appendField(fields, "homepage", old.Homepage, new.Homepage)
+	fields = appendField(fields, "license", old.License, new.License)
+	fields = appendField(fields, "min_skills_x_version", old.MinVersion, new.MinVersion)
+	fields = appendField(fields, "products", strings.Join(old.Products, ","), strings.Join(new.Products, ","))
+	fields = appendField(fields, "deprecated", strconv.FormatBool(old.Deprecated), strconv.FormatBool(new.Deprecated))
+	fields = appendField(fields, "replaced_by", old.ReplacedBy, new.ReplacedBy)
+	fields = appendField(fields, "aliases", strings.Join(old.Aliases, ","), strings.Join(new.Aliases, ","))
+	return fields
+}
+
+func appendField(fields []FieldChange, name, old, new string) []FieldChange {
+	if old == new {
+		return fields
+	}
+	return append(fields, FieldChange{name, old, new})
+}
+
+func sourcesOf(r *Registry) map[string]*Source {
+	if r == nil {
+		return nil
+	}
+	return r.Sources
+}
+
+func skillsByName(src *Source) map[string]*Skill {
+	m := make(map[string]*Skill, len(src.Skills))
+	for i := range src.Skills {
+		m[src.Skills[i].Name] = &src.Skills[i]
+	}
+	return m
+}
+
+func sortedKeys(m map[string]*Source) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortSkillIDs(ids []SkillID) {
+	sort.Slice(ids, func(i, j int) bool { return skillIDLess(ids[i], ids[j]) })
+}
+
+func skillIDLess(a, b SkillID) bool {
+	if a.Source != b.Source {
+		return a.Source < b.Source
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,77 @@
+package registry
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffRegistries(t *testing.T) {
+	old, err := Parse([]byte(`
+kept:
+  repo: github.com/a/kept
+  ref: v1
+  skills:
+    - name: same
+      path: skills/same
+    - name: moved
+      path: skills/old-place
+      tags: [web]
+      description: old words
+    - name: dropped
+      path: skills/dropped
+gone:
+  repo: github.com/a/gone
+  skills:
+    - name: gone-skill
+      path: skills/gone
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	new, err := Parse([]byte(`
+kept:
+  repo: github.com/a/kept
+  ref: v2
+  skills:
+    - name: same
+      path: skills/same
+    - name: moved
+      path: skills/new-place
+      tags: [web, featured]
+      description: new words
+    - name: fresh
+      path: skills/fresh
+added:
+  repo: github.com/a/added
+  skills:
+    - name: added-skill
+      path: skills/added
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := DiffRegistries(old, new)
+	want := Diff{
+		AddedSources:   []string{"added"},
+		RemovedSources: []string{"gone"},
+		ChangedSources: []SourceChange{{"kept", []FieldChange{{"ref", "v1", "v2"}}}},
+		AddedSkills:    []SkillID{{"added", "added-skill"}, {"kept", "fresh"}},
+		RemovedSkills:  []SkillID{{"gone", "gone-skill"}, {"kept", "dropped"}},
+		ChangedSkills: []SkillChange{{SkillID{"kept", "moved"}, []FieldChange{
+			{"path", "skills/old-place", "skills/new-place"},
+			{"tags", "web", "web,featured"},
+			{"description", "old words", "new words"},
+		}}},
+	}
+	if !reflect.DeepEqual(d, want) {
+		t.Fatalf("DiffRegistries =\n%+v\nwant\n%+v", d, want)
+	}
+
+	if !DiffRegistries(new, new).Empty() {
+		t.Error("a registry compared with itself should have no changes")
+	}
+	if d := DiffRegistries(nil, new); len(d.AddedSkills) != 4 || len(d.AddedSources) != 2 {
+		t.Errorf("diff from nothing = %+v; want everything added", d)
+	}
+}
@@ -0,0 +1,443 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/castle-x/skills-x/pkg/githost"
+	"gopkg.in/yaml.v3"
+)
+
+// GeneratedSkill is a skill found in a source repository, as "registry
+// generate" turns it into a registry entry. Fields come from SKILL.md.
+type GeneratedSkill struct {
+	Name        string
+	Path        string // Relative path in the repository, "" for a root skill
+	Description string
+	License     string
+}
+
+// MergeReport describes what MergeGenerated changed in one source
+type MergeReport struct {
+	Source      string   // Source key in the file
+	NewSource   bool     // The source was appended to the file
+	Added       []string // Skills added to the source
+	Moved       []string // Existing skills whose path changed
+	Missing     []string // Skills in the file the repository no longer has
+	Unsupported string   // Why the source was left alone, if it was
+}
+
+// SourceKeyForRepo derives a source key from a repository
+// (github.com/Owner/Repo → owner-repo, https://gitlab.com/group/sub/repo →
+// group-sub-repo)
+func SourceKeyForRepo(repo string) string {
+	if loc, ok := githost.Parse(repo); ok {
+		repo = loc.Path
+	}
+	repo = strings.TrimSuffix(strings.TrimPrefix(repo, "github.com/"), ".git")
+	return strings.ToLower(strings.NewReplacer("/", "-", ".", "-", ":", "-").Replace(repo))
+}
+
+// MergeGenerated merges skills discovered in repo into registry file
+// content and returns the new content. The file is edited as text so that
+// comments, section banners and blank lines survive: a source not in the
+// file yet is appended with a banner; for an existing source (matched by
+// repo, then by key) new skills are appended to its list and changed paths
+// are rewritten. Hand-written fields such as tags and description_zh of
+// existing skills are never touched. New entries get tags inferred from reg
+// (see InferTags), which may be nil.
+func MergeGenerated(data []byte, key, repo string, skills []GeneratedSkill, reg *Registry) ([]byte, MergeReport, error) {
+	report := MergeReport{Source: key}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, report, fmt.Errorf("failed to parse registry file: %w", err)
+	}
+	schema, err := detectSchema(data)
+	if err != nil {
+		return nil, report, err
+	}
+	var sources *yaml.Node
+	if len(doc.Content) > 0 {
+		sources = doc.Content[0]
+		if schema == SchemaV2 {
+			sources = mappingValue(sources, "sources")
+		}
+	}
+	if sources != nil && sources.Kind != yaml.MappingNode {
+		return nil, report, fmt.Errorf("registry file has no sources mapping")
+	}
+
+	lines := splitFileLines(data)
+	entries := mappingEntries(sources)
+	idx := -1
+	for i, e := range entries {
+		if r := mappingValue(e.value, "repo"); r != nil && strings.EqualFold(r.Value, repo) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		for i, e := range entries {
+			if e.key.Value == key {
+				idx = i
+				break
+			}
+		}
+	}
+
+	if idx < 0 {
+		report.NewSource = true
+		indent := 0
+		if schema == SchemaV2 {
+			indent = 2
+			if len(entries) > 0 {
+				indent = entries[0].key.Column - 1
+			}
+		}
+		block := renderSource(key, repo, skills, reg, indent)
+		for _, sk := range skills {
+			report.Added = append(report.Added, sk.Name)
+		}
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		return joinFileLines(append(lines, block...)), report, nil
+	}
+
+	entry := entries[idx]
+	report.Source = entry.key.Value
+	skillsNode := mappingValue(entry.value, "skills")
+	if skillsNode != nil && skillsNode.Kind != yaml.SequenceNode {
+		return nil, report, fmt.Errorf("source %s: skills is not a list", report.Source)
+	}
+	if skillsNode != nil && skillsNode.Style&yaml.FlowStyle != 0 && len(skillsNode.Content) > 0 {
+		report.Unsupported = "skills is written as a flow list"
+		return data, report, nil
+	}
+
+	// Column of "- " for entries, and of the fields inside them
+	fieldIndent := entry.key.Column - 1 + 2
+	dashIndent := fieldIndent + 2
+	if skillsNode != nil && len(skillsNode.Content) > 0 {
+		dashIndent = skillsNode.Content[0].Column - 1 - 2
+	}
+
+	existing := make(map[string]*yaml.Node)
+	if skillsNode != nil {
+		for _, n := range skillsNode.Content {
+			if name := mappingValue(n, "name"); name != nil {
+				existing[name.Value] = n
+			}
+		}
+	}
+
+	type lineEdit struct {
+		line    int // 0-based line to replace, or to insert after when insert is set
+		insert  bool
+		content []string
+	}
+	var edits []lineEdit
+	found := make(map[string]bool)
+	var added []GeneratedSkill
+	for _, sk := range skills {
+		found[sk.Name] = true
+		n, ok := existing[sk.Name]
+		if !ok {
+			added = append(added, sk)
+			continue
+		}
+		pathNode := mappingValue(n, "path")
+		switch {
+		case pathNode != nil && pathNode.Value != sk.Path && sk.Path != "":
+			line := pathNode.Line - 1
+			edits = append(edits, lineEdit{line: line, content: []string{lines[line][:pathNode.Column-1] + yamlScalar(sk.Path)}})
+			report.Moved = append(report.Moved, sk.Name)
+		case pathNode == nil && sk.Path != "":
+			name := mappingValue(n, "name")
+			pad := strings.Repeat(" ", n.Column-1)
+			edits = append(edits, lineEdit{line: name.Line - 1, insert: true, content: []string{pad + "path: " + yamlScalar(sk.Path)}})
+			report.Moved = append(report.Moved, sk.Name)
+		}
+	}
+	if skillsNode != nil {
+		for _, n := range skillsNode.Content {
+			if name := mappingValue(n, "name"); name != nil && !found[name.Value] {
+				report.Missing = append(report.Missing, name.Value)
+			}
+		}
+	}
+
+	if len(added) > 0 {
+		var block []string
+		if skillsNode == nil || len(skillsNode.Content) == 0 {
+			if skillsNode != nil {
+				// "skills: []" — replace it with a block list
+				edits = append(edits, lineEdit{line: skillsNode.Line - 1, content: []string{strings.Repeat(" ", fieldIndent) + "skills:"}})
+			} else {
+				block = append(block, strings.Repeat(" ", fieldIndent)+"skills:")
+			}
+		}
+		for i, sk := range added {
+			if i > 0 || (skillsNode != nil && len(skillsNode.Content) > 0) {
+				block = append(block, "")
+			}
+			block = append(block, renderSkill(sk, "", reg, dashIndent)...)
+			report.Added = append(report.Added, sk.Name)
+		}
+		next := len(lines)
+		if idx+1 < len(entries) {
+			next = entries[idx+1].key.Line - 1
+		} else if schema == SchemaV2 {
+			next = sectionEnd(lines, entry.key.Line-1, entry.key.Column-1)
+		}
+		at := lastContentLine(lines, next)
+		if at < skillsLine(skillsNode, entry.key.Line-1) {
+			at = skillsLine(skillsNode, entry.key.Line-1)
+		}
+		edits = append(edits, lineEdit{line: at, insert: true, content: block})
+	}
+
+	// Apply bottom-up so line numbers stay valid
+	sort.SliceStable(edits, func(i, j int) bool { return edits[i].line > edits[j].line })
+	for _, e := range edits {
+		if e.insert {
+			rest := append(append([]string{}, e.content...), lines[e.line+1:]...)
+			lines = append(lines[:e.line+1], rest...)
+		} else {
+			rest := append(append([]string{}, e.content...), lines[e.line+1:]...)
+			lines = append(lines[:e.line], rest...)
+		}
+	}
+	return joinFileLines(lines), report, nil
+}
+
+// skillsLine is the 0-based line of the skills key, or fallback
+func skillsLine(skills *yaml.Node, fallback int) int {
+	if skills == nil {
+		return fallback
+	}
+	return skills.Line - 1
+}
+
+// lastContentLine returns the last line before end that is neither blank
+// nor a comment, so new entries go above the next section's banner
+func lastContentLine(lines []string, end int) int {
+	for i := end - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			return i
+		}
+	}
+	return 0
+}
+
+// sectionEnd returns the first line after start that is indented less than
+// indent (the end of a nested mapping entry), or len(lines)
+func sectionEnd(lines []string, start, indent int) int {
+	for i := start + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if len(lines[i])-len(strings.TrimLeft(lines[i], " ")) <= indent {
+			return i
+		}
+	}
+	return len(lines)
+}
+
+// renderSource renders a new source with a banner in the layout of the
+// official registry
+func renderSource(key, repo string, skills []GeneratedSkill, reg *Registry, indent int) []string {
+	license := commonLicense(skills)
+	title := repo + " Skills"
+	loc, isRemote := githost.Parse(repo)
+	if isRemote {
+		title = loc.ShortName() + " Skills"
+	}
+	if license != "" {
+		title += " (" + license + ")"
+	}
+	pad := strings.Repeat(" ", indent)
+	banner := pad + "# " + strings.Repeat("=", 77-indent)
+	out := []string{banner, pad + "# " + title}
+	if isRemote {
+		out = append(out, pad+"# "+loc.WebURL())
+	}
+	out = append(out, banner, pad+key+":", pad+"  repo: "+yamlScalar(repo))
+	if license != "" {
+		out = append(out, pad+"  license: "+yamlScalar(license))
+	}
+	out = append(out, pad+"  skills:")
+	for i, sk := range skills {
+		if i > 0 {
+			out = append(out, "")
+		}
+		out = append(out, renderSkill(sk, license, reg, indent+4)...)
+	}
+	return out
+}
+
+// renderSkill renders one skill entry with its "- " at column indent.
+// License is only written when it differs from the source license.
+func renderSkill(sk GeneratedSkill, sourceLicense string, reg *Registry, indent int) []string {
+	dash := strings.Repeat(" ", indent) + "- "
+	pad := strings.Repeat(" ", indent+2)
+	out := []string{dash + "name: " + yamlScalar(sk.Name)}
+	if sk.Path != "" {
+		out = append(out, pad+"path: "+yamlScalar(sk.Path))
+	}
+	if tags := reg.InferTags(sk.Name, sk.Description); len(tags) > 0 {
+		out = append(out, pad+"tags: ["+strings.Join(tags, ", ")+"]")
+	}
+	if desc := SummarizeDescription(sk.Description); desc != "" {
+		out = append(out, pad+"description: "+yamlScalar(desc))
+	}
+	if sk.License != "" && sk.License != sourceLicense {
+		out = append(out, pad+"license: "+yamlScalar(sk.License))
+	}
+	return out
+}
+
+// commonLicense returns the license most skills declare
+func commonLicense(skills []GeneratedSkill) string {
+	counts := make(map[string]int)
+	best := ""
+	for _, sk := range skills {
+		if sk.License == "" {
+			continue
+		}
+		counts[sk.License]++
+		if counts[sk.License] > counts[best] || (counts[sk.License] == counts[best] && sk.License < best) {
+			best = sk.License
+		}
+	}
+	return best
+}
+
+// SummarizeDescription shortens a SKILL.md description to the one-line
+// form the registry uses: whitespace collapsed, cut after the first
+// sentence and at about 120 characters.
+func SummarizeDescription(desc string) string {
+	desc = strings.Join(strings.Fields(desc), " ")
+	if i := strings.Index(desc, ". "); i >= 20 {
+		desc = desc[:i]
+	}
+	desc = strings.TrimSuffix(desc, ".")
+	const max = 120
+	if runes := []rune(desc); len(runes) > max {
+		cut := string(runes[:max])
+		if i := strings.LastIndex(cut, " "); i > max/2 {
+			cut = cut[:i]
+		}
+		desc = strings.TrimRight(cut, " ,;:") + "..."
+	}
+	return desc
+}
+
+// InferTags suggests tags for a new skill from the existing entries most
+// similar to it, comparing the words of names and descriptions. The
+// editorial "featured" tag is never suggested. A nil registry suggests nothing.
+func (r *Registry) InferTags(name, description string) []string {
+	if r == nil {
+		return nil
+	}
+	words := tagWords(name + " " + description)
+	if len(words) == 0 {
+		return nil
+	}
+
+	votes := make(map[string]float64)
+	for _, src := range r.Sources {
+		for _, sk := range src.Skills {
+			if len(sk.Tags) == 0 {
+				continue
+			}
+			other := tagWords(sk.Name + " " + sk.Description)
+			shared := 0
+			for w := range words {
+				if other[w] {
+					shared++
+				}
+			}
+			if shared == 0 {
+				continue
+			}
+			// Jaccard similarity of the two word sets
+			score := float64(shared) / float64(len(words)+len(other)-shared)
+			if sk.Name == name {
+				score += 1
+			}
+			for _, tag := range sk.Tags {
+				if tag != "featured" {
+					votes[tag] += score
+				}
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(votes))
+	for tag := range votes {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if votes[tags[i]] != votes[tags[j]] {
+			return votes[tags[i]] > votes[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	var out []string
+	for _, tag := range tags {
+		if len(out) == 2 || votes[tag] < votes[tags[0]]/2 || votes[tag] < 0.15 {
+			break
+		}
+		out = append(out, tag)
+	}
+	return out
+}
+
+// tagStopWords are too common in descriptions to say anything about tags
+var tagStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "for": true, "with": true,
+	"to": true, "of": true, "in": true, "on": true, "or": true, "by": true,
+	"use": true, "using": true, "when": true, "your": true, "from": true,
+	"skill": true, "skills": true, "this": true, "that": true, "it": true,
+	"is": true, "are": true, "be": true, "as": true, "into": true, "best": true,
+}
+
+func tagWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(w) > 1 && !tagStopWords[w] {
+			words[strings.TrimSuffix(w, "s")] = true
+		}
+	}
+	return words
+}
+
+// yamlScalar renders s as a single-line YAML scalar, quoted only when needed
+func yamlScalar(s string) string {
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Sprintf("%q", s)
+	}
+	return strings.TrimSuffix(string(out), "\n")
+}
+
+func splitFileLines(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func joinFileLines(lines []string) []byte {
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
@@ -0,0 +1,139 @@
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const generateBase = `# Header comment
+
+# =====
+# Team Skills
+# =====
+team:
+  repo: github.com/team/skills
+  license: MIT
+  skills:
+    - name: pdf-tools
+      path: skills/pdf
+      tags: [office]
+      description: Read and fill PDF forms
+      description_zh: 读取和填写 PDF 表单
+
+    - name: gone
+      path: skills/gone
+      description: Removed upstream
+
+# =====
+# Other Skills
+# =====
+other:
+  repo: github.com/other/skills
+  skills:
+    - name: react-patterns
+      tags: [web-frontend]
+      description: React component patterns and hooks
+`
+
+func TestMergeGeneratedExistingSource(t *testing.T) {
+	reg, err := Parse([]byte(generateBase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skills := []GeneratedSkill{
+		{Name: "pdf-tools", Path: "skills/pdf-tools", Description: "ignored for existing skills"},
+		{Name: "docx-tools", Path: "skills/docx-tools", Description: "Read and fill Word forms: tables, fields. Second sentence is dropped.", License: "Apache-2.0"},
+	}
+	out, report, err := MergeGenerated([]byte(generateBase), "ignored", "github.com/team/skills", skills, reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Replace(generateBase, "path: skills/pdf\n", "path: skills/pdf-tools\n", 1)
+	want = strings.Replace(want, "      description: Removed upstream\n", `      description: Removed upstream
+
+    - name: docx-tools
+      path: skills/docx-tools
+      tags: [office]
+      description: 'Read and fill Word forms: tables, fields'
+      license: Apache-2.0
+`, 1)
+	if string(out) != want {
+		t.Errorf("merged file:\n%s\nwant:\n%s", out, want)
+	}
+	if report.Source != "team" || report.NewSource ||
+		!reflect.DeepEqual(report.Added, []string{"docx-tools"}) ||
+		!reflect.DeepEqual(report.Moved, []string{"pdf-tools"}) ||
+		!reflect.DeepEqual(report.Missing, []string{"gone"}) {
+		t.Errorf("report = %+v", report)
+	}
+	if _, err := Parse(out); err != nil {
+		t.Errorf("merged file does not parse: %v", err)
+	}
+}
+
+func TestMergeGeneratedNewSource(t *testing.T) {
+	skills := []GeneratedSkill{
+		{Name: "a-skill", Path: "skills/a", Description: "First", License: "MIT"},
+		{Name: "root-skill", Description: "Second", License: "MIT"},
+	}
+	out, report, err := MergeGenerated([]byte(generateBase), "acme-tools", "github.com/Acme/tools", skills, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.NewSource || len(report.Added) != 2 {
+		t.Errorf("report = %+v", report)
+	}
+	if !strings.HasPrefix(string(out), generateBase) {
+		t.Fatalf("existing content changed:\n%s", out)
+	}
+	added := strings.TrimPrefix(string(out), generateBase)
+	for _, line := range []string{
+		"# Acme/tools Skills (MIT)",
+		"# https://github.com/Acme/tools",
+		"acme-tools:\n  repo: github.com/Acme/tools\n  license: MIT\n  skills:\n    - name: a-skill\n      path: skills/a\n      description: First\n\n    - name: root-skill\n      description: Second\n",
+	} {
+		if !strings.Contains(added, line) {
+			t.Errorf("appended block lacks %q:\n%s", line, added)
+		}
+	}
+	reg, err := Parse(out)
+	if err != nil || len(reg.GetSource("acme-tools").Skills) != 2 {
+		t.Fatalf("Parse(merged) = %v, %v", reg, err)
+	}
+}
+
+func TestMergeGeneratedSchemaV2(t *testing.T) {
+	data := []byte("schema: 2\nsources:\n  team:\n    repo: github.com/team/skills\n    skills:\n      - name: a\n        description: A\n")
+	out, _, err := MergeGenerated(data, "team", "github.com/team/skills", []GeneratedSkill{{Name: "a"}, {Name: "b", Path: "b", Description: "B"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg, err := Parse(out)
+	if err != nil {
+		t.Fatalf("Parse:\n%s\n%v", out, err)
+	}
+	if got := reg.GetSource("team").Skills; len(got) != 2 || got[1].Path != "b" {
+		t.Errorf("skills = %+v", got)
+	}
+}
+
+func TestInferTags(t *testing.T) {
+	reg, err := Parse([]byte(generateBase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reg.InferTags("react-hooks", "Patterns for React hooks"); !reflect.DeepEqual(got, []string{"web-frontend"}) {
+		t.Errorf("InferTags(react) = %v; want [web-frontend]", got)
+	}
+	if got := reg.InferTags("zzz", "unrelated words"); got != nil {
+		t.Errorf("InferTags(unrelated) = %v; want none", got)
+	}
+}
+
+func TestSourceKeyForRepo(t *testing.T) {
+	if got := SourceKeyForRepo("github.com/Shubhamsaboo/awesome-llm-apps"); got != "shubhamsaboo-awesome-llm-apps" {
+		t.Errorf("SourceKeyForRepo = %q", got)
+	}
+}
@@ -0,0 +1,357 @@
+package registry
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/castle-x/skills-x/pkg/gitauth"
+	"github.com/castle-x/skills-x/pkg/githost"
+	"github.com/castle-x/skills-x/pkg/products"
+	"github.com/castle-x/skills-x/pkg/versioncheck"
+	"gopkg.in/yaml.v3"
+)
+
+// KnownTags lists the tags the TUI can filter by. Other tags are allowed but
+// unreachable from the tag picker, so Lint warns about them.
+var KnownTags = []string{
+	"featured", "ai-efficiency", "planning",
+	"web-frontend", "mobile", "backend",
+	"testing", "code-review", "office",
+	"design", "writing", "media",
+	"skills-meta",
+}
+
+// Lint severities
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Finding is one problem Lint found in a registry file
+type Finding struct {
+	Line     int    `json:"line,omitempty"` // 1-based line in the file, 0 when unknown
+	Severity string `json:"severity"`       // SeverityError or SeverityWarning
+	Rule     string `json:"rule"`           // Short stable identifier, e.g. "missing-description"
+	Source   string `json:"source,omitempty"`
+	Skill    string `json:"skill,omitempty"`
+	Message  string `json:"message"`
+}
+
+// LintErrors counts the findings with SeverityError
+func LintErrors(findings []Finding) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// skillNamePattern is the naming rule skillvalidator applies to SKILL.md
+var skillNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var githubRepoPattern = regexp.MustCompile(`^github\.com/[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+)`)
+
+// Known keys of a source and a skill entry, to catch typos
+var (
+	sourceKeys = map[string]bool{
+		"repo": true, "branch": true, "ref": true, "license": true,
+		"skip_fetch": true, "transport": true, "archive": true, "skills": true,
+	}
+	skillKeys = map[string]bool{
+		"name": true, "path": true, "ref": true, "tags": true,
+		"description": true, "description_zh": true, "version": true,
+		"authors": true, "homepage": true, "license": true,
+		"min_skills_x_version": true, "products": true,
+		"deprecated": true, "replaced_by": true, "aliases": true,
+	}
+)
+
+// Lint runs offline checks on registry file content: duplicate skill names,
+// missing descriptions, tags the TUI cannot filter by, malformed repos and
+// paths, dangling replaced_by references and unknown keys. Findings follow
+// the order of the file.
+func Lint(data []byte) []Finding {
+	reg, err := Parse(data)
+	if err != nil {
+		f := Finding{Severity: SeverityError, Rule: "parse", Message: err.Error()}
+		if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			f.Line, _ = strconv.Atoi(m[1])
+		}
+		return []Finding{f}
+	}
+
+	var doc yaml.Node
+	_ = yaml.Unmarshal(data, &doc) // Parse succeeded, so this does too
+	l := &linter{reg: reg, names: make(map[string][]string)}
+	for _, src := range reg.Sources {
+		for _, sk := range src.Skills {
+			l.names[sk.Name] = append(l.names[sk.Name], src.Name)
+		}
+	}
+	for _, entry := range sourceNodes(&doc, reg.Schema) {
+		l.lintSource(entry.key, entry.value)
+	}
+	return l.findings
+}
+
+type linter struct {
+	reg      *Registry
+	names    map[string][]string // skill name → source keys
+	findings []Finding
+}
+
+type nodeEntry struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// sourceNodes returns the key/value nodes of every source in file order
+func sourceNodes(doc *yaml.Node, schema int) []nodeEntry {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	sources := doc.Content[0]
+	if schema == SchemaV2 {
+		sources = mappingValue(sources, "sources")
+	}
+	return mappingEntries(sources)
+}
+
+func mappingEntries(n *yaml.Node) []nodeEntry {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	entries := make([]nodeEntry, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		entries = append(entries, nodeEntry{n.Content[i], n.Content[i+1]})
+	}
+	return entries
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	for _, e := range mappingEntries(n) {
+		if e.key.Value == key {
+			return e.value
+		}
+	}
+	return nil
+}
+
+// lineOf returns the line of key inside mapping n, or the mapping's own line
+func lineOf(n *yaml.Node, key string) int {
+	for _, e := range mappingEntries(n) {
+		if e.key.Value == key {
+			return e.key.Line
+		}
+	}
+	return n.Line
+}
+
+func (l *linter) add(line int, severity, rule, source, skill, format string, args ...interface{}) {
+	l.findings = append(l.findings, Finding{
+		Line:     line,
+		Severity: severity,
+		Rule:     rule,
+		Source:   source,
+		Skill:    skill,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) lintSource(key, node *yaml.Node) {
+	name := key.Value
+	src := l.reg.Sources[name]
+	if src == nil {
+		return
+	}
+	for _, e := range mappingEntries(node) {
+		if !sourceKeys[e.key.Value] {
+			l.add(e.key.Line, SeverityWarning, "unknown-field", name, "", "unknown source field %q", e.key.Value)
+		}
+	}
+
+	switch {
+	case src.Repo == "":
+		l.add(key.Line, SeverityError, "missing-repo", name, "", "source has no repo")
+	case !validRepo(src.Repo):
+		l.add(lineOf(node, "repo"), SeverityError, "repo-format", name, "",
+			"repo %q should be github.com/<owner>/<repo> or a full https:// URL", src.Repo)
+	}
+	if src.UsesArchive() && src.ArchiveURL("HEAD") == "" {
+		l.add(lineOf(node, "transport"), SeverityError, "missing-archive", name, "",
+			"transport %s needs an archive URL for repos on unknown hosts", TransportArchive)
+	}
+	if src.Archive != "" && !src.UsesArchive() {
+		l.add(lineOf(node, "archive"), SeverityWarning, "unused-archive", name, "",
+			"archive is ignored unless transport is %s", TransportArchive)
+	}
+	if src.Archive != "" && !strings.Contains(src.Archive, "{ref}") {
+		l.add(lineOf(node, "archive"), SeverityWarning, "archive-ref", name, "",
+			"archive URL has no {ref} placeholder, so pinned refs are ignored")
+	}
+
+	skillNodes := mappingValue(node, "skills")
+	seen := make(map[string]bool)
+	for i := range src.Skills {
+		var skillNode *yaml.Node
+		if skillNodes != nil && i < len(skillNodes.Content) {
+			skillNode = skillNodes.Content[i]
+		} else {
+			skillNode = node
+		}
+		l.lintSkill(src, &src.Skills[i], skillNode, seen)
+	}
+}
+
+func (l *linter) lintSkill(src *Source, sk *Skill, node *yaml.Node, seen map[string]bool) {
+	source := src.Name
+	for _, e := range mappingEntries(node) {
+		if !skillKeys[e.key.Value] {
+			l.add(e.key.Line, SeverityWarning, "unknown-field", source, sk.Name, "unknown skill field %q", e.key.Value)
+		}
+	}
+
+	switch {
+	case sk.Name == "":
+		l.add(node.Line, SeverityError, "missing-name", source, "", "skill has no name")
+		return
+	case !skillNamePattern.MatchString(sk.Name):
+		l.add(lineOf(node, "name"), SeverityError, "name-format", source, sk.Name,
+			"skill name should use lowercase letters, digits and single hyphens")
+	}
+	if seen[sk.Name] {
+		l.add(lineOf(node, "name"), SeverityError, "duplicate-skill", source, sk.Name,
+			"skill %q is listed twice in this source", sk.Name)
+	} else if others := otherSources(l.names[sk.Name], source); len(others) > 0 {
+		l.add(lineOf(node, "name"), SeverityWarning, "duplicate-skill", source, sk.Name,
+			"skill %q is also in %s; installs will ask which one to use", sk.Name, strings.Join(others, ", "))
+	}
+	seen[sk.Name] = true
+
+	if sk.Path != "" {
+		clean := path.Clean(sk.Path)
+		if path.IsAbs(sk.Path) || clean == ".." || strings.HasPrefix(clean, "../") || strings.Contains(sk.Path, `\`) {
+			l.add(lineOf(node, "path"), SeverityError, "path-format", source, sk.Name,
+				"path %q must be a relative slash-separated path inside the repository", sk.Path)
+		}
+	}
+
+	if strings.TrimSpace(sk.Description) == "" {
+		l.add(node.Line, SeverityError, "missing-description", source, sk.Name, "description is empty")
+	}
+	if strings.TrimSpace(sk.DescriptionZh) == "" {
+		l.add(node.Line, SeverityWarning, "missing-description-zh", source, sk.Name, "description_zh is empty")
+	}
+
+	for _, tag := range sk.Tags {
+		if !isKnownTag(tag) {
+			l.add(lineOf(node, "tags"), SeverityWarning, "unknown-tag", source, sk.Name,
+				"tag %q cannot be selected in the TUI (known: %s)", tag, strings.Join(KnownTags, ", "))
+		}
+	}
+	for _, p := range sk.Products {
+		if !isKnownProduct(p) {
+			l.add(lineOf(node, "products"), SeverityWarning, "unknown-product", source, sk.Name, "unknown product %q", p)
+		}
+	}
+
+	if sk.MinVersion != "" && !validVersion(sk.MinVersion) {
+		l.add(lineOf(node, "min_skills_x_version"), SeverityError, "version-format", source, sk.Name,
+			"min_skills_x_version %q is not a version like 1.2.0", sk.MinVersion)
+	}
+
+	if sk.ReplacedBy != "" {
+		switch {
+		case sk.ReplacedBy == sk.Name:
+			l.add(lineOf(node, "replaced_by"), SeverityError, "replaced-by", source, sk.Name, "skill replaces itself")
+		case len(l.names[sk.ReplacedBy]) == 0:
+			l.add(lineOf(node, "replaced_by"), SeverityError, "replaced-by", source, sk.Name,
+				"replaced_by %q is not a skill in this registry", sk.ReplacedBy)
+		}
+		if !sk.Deprecated {
+			l.add(lineOf(node, "replaced_by"), SeverityWarning, "replaced-by", source, sk.Name,
+				"replaced_by is set but the skill is not deprecated")
+		}
+	}
+
+	for _, alias := range sk.Aliases {
+		switch {
+		case alias == sk.Name:
+			l.add(lineOf(node, "aliases"), SeverityWarning, "alias", source, sk.Name, "alias %q repeats the skill name", alias)
+		case len(l.names[alias]) > 0:
+			l.add(lineOf(node, "aliases"), SeverityWarning, "alias", source, sk.Name,
+				"alias %q is shadowed by a skill with that name", alias)
+		}
+	}
+}
+
+// otherSources returns the sources in keys other than source, sorted
+func otherSources(keys []string, source string) []string {
+	var others []string
+	for _, k := range keys {
+		if k != source {
+			others = append(others, k)
+		}
+	}
+	sort.Strings(others)
+	return others
+}
+
+func validRepo(repo string) bool {
+	if strings.HasPrefix(repo, "github.com/") {
+		return githubRepoPattern.MatchString(repo) && !strings.HasSuffix(repo, ".git")
+	}
+	if gitauth.IsSCP(repo) {
+		return true
+	}
+	u, err := url.Parse(repo)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http" && u.Scheme != "ssh") ||
+		u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return false
+	}
+	// A browser URL into the file tree is not a repository
+	loc, ok := githost.Parse(repo)
+	return u.Scheme == "ssh" || ok && loc.Ref == "" && loc.Dir == ""
+}
+
+func validVersion(v string) bool {
+	v = versioncheck.NormalizeVersion(v)
+	if v == "" {
+		return false
+	}
+	for _, part := range strings.Split(v, ".") {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isKnownTag(tag string) bool {
+	for _, t := range KnownTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// isKnownProduct checks against the built-in products only: a registry is
+// shared, so products added in one user's products.yaml do not count
+func isKnownProduct(name string) bool {
+	for _, p := range products.Builtin {
+		if SameProduct(p.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package registry
+
+import (
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	data := []byte(`team:
+  repo: github.com/team/skills.git
+  archive: https://example.com/team.tar.gz
+  skils: []
+  skills:
+    - name: pdf-tools
+      path: skills/pdf-tools
+      description: PDF helpers
+      description_zh: PDF 工具
+      tags: [office, pdfs]
+      products: [claude-code, notepad]
+      aliases: [pdf-tools]
+    - name: pdf-tools
+      path: ../outside
+      description: again
+      description_zh: 再次
+    - name: Old_PDF
+      descripton: typo
+      deprecated: false
+      replaced_by: missing
+      min_skills_x_version: next
+other:
+  repo: https://git.example.com/other/skills
+  skills:
+    - name: pdf-tools
+      description: elsewhere
+      description_zh: 别处
+`)
+	got := make(map[string][]Finding)
+	for _, f := range Lint(data) {
+		got[f.Rule] = append(got[f.Rule], f)
+	}
+
+	want := map[string]struct {
+		count    int
+		severity string
+		line     int
+	}{
+		"repo-format":            {1, SeverityError, 2},
+		"unused-archive":         {1, SeverityWarning, 3},
+		"archive-ref":            {1, SeverityWarning, 3},
+		"unknown-field":          {2, SeverityWarning, 4},
+		"unknown-tag":            {1, SeverityWarning, 10},
+		"unknown-product":        {1, SeverityWarning, 11},
+		"alias":                  {1, SeverityWarning, 12},
+		"path-format":            {1, SeverityError, 14},
+		"name-format":            {1, SeverityError, 17},
+		"missing-description":    {1, SeverityError, 17},
+		"missing-description-zh": {1, SeverityWarning, 17},
+		"version-format":         {1, SeverityError, 21},
+	}
+	for rule, w := range want {
+		fs := got[rule]
+		if len(fs) != w.count {
+			t.Errorf("%s: %d findings %+v; want %d", rule, len(fs), fs, w.count)
+			continue
+		}
+		if fs[0].Severity != w.severity || fs[0].Line != w.line {
+			t.Errorf("%s: %s at line %d; want %s at line %d", rule, fs[0].Severity, fs[0].Line, w.severity, w.line)
+		}
+	}
+
+	// pdf-tools twice in team is an error, once more in other a warning
+	var dupErrors, dupWarnings int
+	for _, f := range got["duplicate-skill"] {
+		if f.Severity == SeverityError {
+			dupErrors++
+		} else {
+			dupWarnings++
+		}
+	}
+	if dupErrors != 1 || dupWarnings != 2 {
+		t.Errorf("duplicate-skill: %d errors, %d warnings; want 1 and 2", dupErrors, dupWarnings)
+	}
+	// A dangling replaced_by on a skill that is not deprecated
+	if len(got["replaced-by"]) != 2 {
+		t.Errorf("replaced-by = %+v; want a dangling reference and a not-deprecated warning", got["replaced-by"])
+	}
+}
+
+func TestLintParseError(t *testing.T) {
+	findings := Lint([]byte("team:\n  repo: x\n  skills: [\n"))
+	if len(findings) != 1 || findings[0].Rule != "parse" || findings[0].Severity != SeverityError {
+		t.Fatalf("findings = %+v; want one parse error", findings)
+	}
+	if LintErrors(findings) != 1 {
+		t.Errorf("LintErrors = %d; want 1", LintErrors(findings))
+	}
+}
+
+func TestEmbeddedRegistryLintsClean(t *testing.T) {
+	data, err := EmbeddedData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range Lint(data) {
+		if f.Severity == SeverityError {
+			t.Errorf("registry.yaml:%d: [%s] %s/%s: %s", f.Line, f.Rule, f.Source, f.Skill, f.Message)
+		}
+	}
+}
@@ -3,11 +3,17 @@ package registry
 
 import (
 	"embed"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"unicode"
 
+	"github.com/castle-x/skills-x/pkg/gitauth"
+	"github.com/castle-x/skills-x/pkg/githost"
+	"github.com/castle-x/skills-x/pkg/versioncheck"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,22 +25,53 @@ type Source struct {
 	Name      string  // Source identifier (e.g., "anthropic", "vercel")
 	Repo      string  `yaml:"repo"`       // Repository URL (e.g., "github.com/anthropics/skills")
 	Branch    string  `yaml:"branch"`     // Branch to clone (empty = default branch)
+	Ref       string  `yaml:"ref"`        // Pinned tag or commit SHA for every skill (empty = branch tip)
 	License   string  `yaml:"license"`    // License type
 	SkipFetch bool    `yaml:"skip_fetch"` // Skip dynamic fetching (for large repos)
+	Transport string  `yaml:"transport"`  // How skills are fetched: TransportGit (default) or TransportArchive
+	Archive   string  `yaml:"archive"`    // Archive URL template for TransportArchive, with {ref} (empty = codeload for GitHub)
 	Skills    []Skill `yaml:"skills"`     // Skills in this source
 	IsUser    bool    // True when loaded from user-registry.yaml
+	Origin    string  // Where the source came from: OfficialRemote, another remote's name, or OriginUser
+	Priority  int     // Priority of the remote it came from (higher wins in FindSkill)
 }
 
+// Source transports
+const (
+	TransportGit     = "git"     // Clone the repository
+	TransportArchive = "archive" // Download a tarball or zip and extract only the skill path
+)
+
 // Skill represents a skill entry in the registry
 type Skill struct {
 	Name          string   `yaml:"name"`           // Skill name
 	Path          string   `yaml:"path"`           // Path in repository
+	Ref           string   `yaml:"ref"`            // Pinned tag or commit SHA (overrides the source ref)
 	Tags          []string `yaml:"tags"`           // Tags for filtering (e.g., featured, web-frontend)
 	Description   string   `yaml:"description"`    // Short description (English)
 	DescriptionZh string   `yaml:"description_zh"` // Short description (Chinese)
 	Version       string   `yaml:"version"`        // Version (optional)
+
+	// Schema v2 metadata; all optional and also accepted in v1 files
+	Authors    []string `yaml:"authors"`              // Skill authors
+	Homepage   string   `yaml:"homepage"`             // Documentation or project page
+	License    string   `yaml:"license"`              // License (overrides the source license)
+	MinVersion string   `yaml:"min_skills_x_version"` // Oldest skills-x that can install it
+	Products   []string `yaml:"products"`             // Products it works with (empty = all)
+	Deprecated bool     `yaml:"deprecated"`           // Kept for existing installs, not recommended
+	ReplacedBy string   `yaml:"replaced_by"`          // Skill to use instead of a deprecated one
+	Aliases    []string `yaml:"aliases"`              // Other names that resolve to this skill
 }
 
+// Registry schema versions understood by Parse
+const (
+	SchemaV1 = 1 // A map of sources at the top level
+	SchemaV2 = 2 // "schema: 2" with the sources under "sources"
+
+	// SchemaLatest is the newest schema this binary can read
+	SchemaLatest = SchemaV2
+)
+
 // GetDescription returns the description based on language
 // lang should be "zh" for Chinese, otherwise English
 func (s *Skill) GetDescription(lang string) string {
@@ -44,9 +81,65 @@ func (s *Skill) GetDescription(lang string) string {
 	return s.Description
 }
 
+// SupportsProduct reports whether the skill is meant for the named product.
+// Names are compared ignoring case, spaces, '-' and '_', so "claude-code"
+// matches "Claude Code". Skills without a products list support every product.
+func (s *Skill) SupportsProduct(product string) bool {
+	if len(s.Products) == 0 || product == "" {
+		return true
+	}
+	for _, p := range s.Products {
+		if SameProduct(p, product) {
+			return true
+		}
+	}
+	return false
+}
+
+// SameProduct compares product names the way SupportsProduct does
+func SameProduct(a, b string) bool {
+	return normalizeProduct(a) == normalizeProduct(b)
+}
+
+func normalizeProduct(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// IsCompatible reports whether the running skills-x satisfies the skill's
+// min_skills_x_version
+func (s *Skill) IsCompatible() bool {
+	return versioncheck.AtLeast(versioncheck.Running, s.MinVersion)
+}
+
+// HasAlias reports whether name is one of the skill's aliases
+func (s *Skill) HasAlias(name string) bool {
+	for _, a := range s.Aliases {
+		if strings.EqualFold(a, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// FetchPaths returns the repository paths an archive download needs for
+// this skill; nil (everything) when the skill has to be discovered
+func (s *Skill) FetchPaths() []string {
+	if s.Path == "" {
+		return nil
+	}
+	return []string{s.Path}
+}
+
 // Registry holds all sources from registry.yaml
 type Registry struct {
 	Sources map[string]*Source
+	Schema  int // Schema version the file was written in
 }
 
 // IsUserSource returns true when a Source was added from the user registry.
@@ -56,47 +149,134 @@ func (s *Source) IsUserSource() bool {
 
 // registryYAML is the raw YAML structure
 type registryYAML map[string]struct {
-	Repo      string `yaml:"repo"`
-	Branch    string `yaml:"branch"`
-	License   string `yaml:"license"`
-	SkipFetch bool   `yaml:"skip_fetch"`
-	Skills    []struct {
-		Name          string   `yaml:"name"`
-		Path          string   `yaml:"path"`
-		Tags          []string `yaml:"tags"`
-		Description   string   `yaml:"description"`
-		DescriptionZh string   `yaml:"description_zh"`
-		Version       string   `yaml:"version"`
-	} `yaml:"skills"`
+	Repo      string  `yaml:"repo"`
+	Branch    string  `yaml:"branch"`
+	Ref       string  `yaml:"ref"`
+	License   string  `yaml:"license"`
+	SkipFetch bool    `yaml:"skip_fetch"`
+	Transport string  `yaml:"transport"`
+	Archive   string  `yaml:"archive"`
+	Skills    []Skill `yaml:"skills"`
+}
+
+// registryV2YAML is the raw YAML structure of a schema v2 file
+type registryV2YAML struct {
+	Schema  int          `yaml:"schema"`
+	Sources registryYAML `yaml:"sources"`
 }
 
 // Load loads the registry. It prefers a locally cached registry (written by
 // "skills-x registry update") over the embedded one, so users can get new
 // skills without upgrading the binary.
+//
+// Sources from other enabled remotes (see LoadRemotes) are merged on top,
+// named "<remote>:<source>" so they never replace official entries; their
+// Origin and Priority record where they came from.
+//
+// Caches of signed remotes are verified again on every load; one that no
+// longer matches its signature is ignored (LoadWithUser reports it).
 func Load() (*Registry, error) {
-	// Try cached registry first.
-	if data, err := loadCachedRegistry(); err == nil {
-		if reg, err := Parse(data); err == nil {
-			return reg, nil
-		}
-		// Cached file is corrupt — fall through to embedded.
+	reg, _, err := load()
+	return reg, err
+}
+
+// load is Load, also returning a warning for every cached registry that was
+// skipped because its signature did not verify
+func load() (*Registry, []string, error) {
+	remotes, err := LoadRemotes()
+	if err != nil {
+		// A broken remotes.yaml should not hide the official registry.
+		remotes = []Remote{defaultOfficial()}
 	}
 
-	// Fall back to embedded registry.yaml.
-	data, err := registryFS.ReadFile("registry.yaml")
+	var warnings []string
+	reg, err := loadOfficial(remotes[findRemote(remotes, OfficialRemote)], &warnings)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read registry.yaml: %w", err)
+		return nil, nil, err
 	}
-	return Parse(data)
+
+	for _, remote := range remotes {
+		if remote.Name == OfficialRemote || !remote.Enabled {
+			continue
+		}
+		remoteReg, err := loadRemoteCache(remote)
+		if err != nil {
+			// Not fetched yet or corrupt — "registry update" will fix it.
+			warnings = appendSignatureWarning(warnings, err)
+			continue
+		}
+		for name, src := range remoteReg.Sources {
+			key := remote.Name + ":" + name
+			src.Name = key
+			src.Origin = remote.Name
+			src.Priority = remote.Priority
+			reg.Sources[key] = src
+		}
+	}
+	return reg, warnings, nil
 }
 
-// loadCachedRegistry reads the locally cached registry file if it exists.
-func loadCachedRegistry() ([]byte, error) {
-	path, err := CachedRegistryPath()
+// EmbeddedData returns the registry.yaml built into the binary
+func EmbeddedData() ([]byte, error) {
+	return registryFS.ReadFile("registry.yaml")
+}
+
+// loadOfficial loads the official remote's cached registry, falling back to
+// the embedded registry.yaml when it is disabled, missing, corrupt or fails
+// verification.
+func loadOfficial(official Remote, warnings *[]string) (*Registry, error) {
+	var reg *Registry
+	if official.Enabled {
+		var err error
+		if reg, err = loadRemoteCache(official); err != nil {
+			*warnings = appendSignatureWarning(*warnings, err)
+		}
+	}
+	if reg == nil {
+		// Fall back to embedded registry.yaml.
+		data, err := EmbeddedData()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read registry.yaml: %w", err)
+		}
+		if reg, err = Parse(data); err != nil {
+			return nil, err
+		}
+	}
+	for _, src := range reg.Sources {
+		src.Origin = OfficialRemote
+		src.Priority = official.Priority
+	}
+	return reg, nil
+}
+
+// loadRemoteCache parses the registry "registry update" stored for remote,
+// after checking it against the signature stored next to it
+func loadRemoteCache(remote Remote) (*Registry, error) {
+	path, err := remote.CachePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
-	return os.ReadFile(path)
+	sig, err := readCachedSignature(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := remote.Verify(data, sig); err != nil {
+		return nil, fmt.Errorf("ignoring cached %s: %w", path, err)
+	}
+	return Parse(data)
+}
+
+// appendSignatureWarning records err when a cache was skipped because of its
+// signature; other errors (no cache yet, corrupt YAML) stay silent
+func appendSignatureWarning(warnings []string, err error) []string {
+	if errors.Is(err, ErrBadSignature) || errors.Is(err, ErrUnsigned) {
+		return append(warnings, err.Error())
+	}
+	return warnings
 }
 
 // CachedRegistryPath returns the path where "registry update" stores the
@@ -110,38 +290,52 @@ func CachedRegistryPath() (string, error) {
 	return filepath.Join(configDir, "skills-x", "registry.yaml"), nil
 }
 
-// Parse parses registry.yaml content
+// Parse parses registry.yaml content in schema v1 or v2
 func Parse(data []byte) (*Registry, error) {
+	schema, err := detectSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse registry.yaml: %w", err)
+	}
+
 	var raw registryYAML
-	if err := yaml.Unmarshal(data, &raw); err != nil {
+	switch schema {
+	case SchemaV1:
+		err = yaml.Unmarshal(data, &raw)
+	case SchemaV2:
+		var v2 registryV2YAML
+		err = yaml.Unmarshal(data, &v2)
+		raw = v2.Sources
+	default:
+		return nil, fmt.Errorf("registry schema %d is newer than this skills-x supports (%d); please upgrade", schema, SchemaLatest)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse registry.yaml: %w", err)
 	}
 
 	registry := &Registry{
 		Sources: make(map[string]*Source),
+		Schema:  schema,
 	}
 
 	for name, src := range raw {
+		switch src.Transport {
+		case "", TransportGit, TransportArchive:
+		default:
+			return nil, fmt.Errorf("source %s: unknown transport %q (want %s or %s)", name, src.Transport, TransportGit, TransportArchive)
+		}
 		source := &Source{
 			Name:      name,
 			Repo:      src.Repo,
 			Branch:    src.Branch,
+			Ref:       src.Ref,
 			License:   src.License,
 			SkipFetch: src.SkipFetch,
+			Transport: src.Transport,
+			Archive:   src.Archive,
 			Skills:    make([]Skill, 0, len(src.Skills)),
 		}
 
-		for _, s := range src.Skills {
-			skill := Skill{
-				Name:          s.Name,
-				Path:          s.Path,
-				Tags:          s.Tags,
-				Description:   s.Description,
-				DescriptionZh: s.DescriptionZh,
-				Version:       s.Version,
-			}
-			source.Skills = append(source.Skills, skill)
-		}
+		source.Skills = append(source.Skills, src.Skills...)
 
 		registry.Sources[name] = source
 	}
@@ -149,6 +343,31 @@ func Parse(data []byte) (*Registry, error) {
 	return registry, nil
 }
 
+// detectSchema returns the schema version of a registry file: the value of a
+// top-level "schema" key, or v1 when there is none. A v1 source cannot be
+// named "schema" because its value would be a scalar.
+func detectSchema(data []byte) (int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return 0, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return SchemaV1, nil
+	}
+	top := doc.Content[0]
+	for i := 0; i+1 < len(top.Content); i += 2 {
+		if top.Content[i].Value != "schema" || top.Content[i+1].Kind != yaml.ScalarNode {
+			continue
+		}
+		var schema int
+		if err := top.Content[i+1].Decode(&schema); err != nil || schema < SchemaV1 {
+			return 0, fmt.Errorf("invalid schema version %q", top.Content[i+1].Value)
+		}
+		return schema, nil
+	}
+	return SchemaV1, nil
+}
+
 // GetAllSources returns all sources in the registry
 func (r *Registry) GetAllSources() []*Source {
 	sources := make([]*Source, 0, len(r.Sources))
@@ -164,17 +383,17 @@ func (r *Registry) GetSource(name string) *Source {
 }
 
 // FindSkill finds a skill by name across all sources
-// Returns the skill and its source, or nil if not found
+// Returns the skill and its source, or nil if not found.
+// User registry entries win, then sources from higher-priority remotes;
+// ties are broken by source key so the result is stable. A name that no
+// skill has is then looked up among aliases in the same order.
 func (r *Registry) FindSkill(skillName string) (*Skill, *Source) {
-	skillNameLower := strings.ToLower(skillName)
-	// User registry entries should override built-ins for direct lookups.
-	for _, userFirst := range []bool{true, false} {
-		for _, src := range r.Sources {
-			if src.IsUser != userFirst {
-				continue
-			}
+	keys := r.sourceKeysByPrecedence()
+	for _, byAlias := range []bool{false, true} {
+		for _, key := range keys {
+			src := r.Sources[key]
 			for i := range src.Skills {
-				if strings.ToLower(src.Skills[i].Name) == skillNameLower {
+				if src.Skills[i].matches(skillName, byAlias) {
 					return &src.Skills[i], src
 				}
 			}
@@ -183,7 +402,39 @@ func (r *Registry) FindSkill(skillName string) (*Skill, *Source) {
 	return nil, nil
 }
 
-// FindSkillsWithConflict finds all skills matching a name (for conflict detection)
+// matches compares name against the skill name, or against its aliases
+func (s *Skill) matches(name string, byAlias bool) bool {
+	if byAlias {
+		return s.HasAlias(name)
+	}
+	return strings.EqualFold(s.Name, name)
+}
+
+// sourceKeysByPrecedence returns the source keys in lookup order
+func (r *Registry) sourceKeysByPrecedence() []string {
+	keys := make([]string, 0, len(r.Sources))
+	for key := range r.Sources {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := r.Sources[keys[i]], r.Sources[keys[j]]
+		if a.IsUser != b.IsUser {
+			return a.IsUser
+		}
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		// Official sources before other remotes at the same priority
+		if (a.Origin == OfficialRemote) != (b.Origin == OfficialRemote) {
+			return a.Origin == OfficialRemote
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// FindSkillsWithConflict finds all skills matching a name (for conflict detection).
+// Aliases are only considered when no skill has that name.
 func (r *Registry) FindSkillsWithConflict(skillName string) []struct {
 	Skill  *Skill
 	Source *Source
@@ -193,16 +444,20 @@ func (r *Registry) FindSkillsWithConflict(skillName string) []struct {
 		Source *Source
 	}
 
-	skillNameLower := strings.ToLower(skillName)
-	for _, src := range r.Sources {
-		for i := range src.Skills {
-			if strings.ToLower(src.Skills[i].Name) == skillNameLower {
-				matches = append(matches, struct {
-					Skill  *Skill
-					Source *Source
-				}{&src.Skills[i], src})
+	for _, byAlias := range []bool{false, true} {
+		for _, src := range r.Sources {
+			for i := range src.Skills {
+				if src.Skills[i].matches(skillName, byAlias) {
+					matches = append(matches, struct {
+						Skill  *Skill
+						Source *Source
+					}{&src.Skills[i], src})
+				}
 			}
 		}
+		if len(matches) > 0 {
+			break
+		}
 	}
 	return matches
 }
@@ -216,20 +471,68 @@ func (r *Registry) TotalSkillCount() int {
 	return count
 }
 
-// GetGitURL returns the git clone URL for a source
+// GetGitURL returns the git clone URL for a source. Full URLs are cloned
+// as written; host/path forms such as github.com/owner/repo get the host's
+// https clone URL.
 func (s *Source) GetGitURL() string {
-	// Convert github.com/owner/repo to https://github.com/owner/repo.git
-	if strings.HasPrefix(s.Repo, "github.com/") {
-		return "https://" + s.Repo + ".git"
+	if strings.Contains(s.Repo, "://") || gitauth.IsSSH(s.Repo) {
+		return s.Repo
+	}
+	if loc, ok := githost.Parse(s.Repo); ok {
+		return loc.CloneURL()
 	}
 	return s.Repo
 }
 
+// SkillRef returns the ref a skill is pinned to: the skill's own ref if set,
+// otherwise the source ref. Empty means the tip of the source branch.
+func (s *Source) SkillRef(skill *Skill) string {
+	if skill != nil && skill.Ref != "" {
+		return skill.Ref
+	}
+	return s.Ref
+}
+
+// UsesArchive reports whether skills are downloaded as archives instead of cloned
+func (s *Source) UsesArchive() bool {
+	return s.Transport == TransportArchive
+}
+
+// ArchiveRef returns the ref to download for a skill pinned to ref: ref
+// itself, otherwise the source branch, otherwise HEAD (the default branch)
+func (s *Source) ArchiveRef(ref string) string {
+	switch {
+	case ref != "":
+		return ref
+	case s.Branch != "":
+		return s.Branch
+	}
+	return "HEAD"
+}
+
+// ArchiveURL returns the archive to download at ref: the source's archive
+// template with {ref} substituted, the codeload tarball for GitHub
+// repositories, or the host's own archive URL on GitLab, Gitea, Forgejo
+// and Bitbucket. It returns "" when none applies.
+func (s *Source) ArchiveURL(ref string) string {
+	if s.Archive != "" {
+		return strings.ReplaceAll(s.Archive, "{ref}", ref)
+	}
+	loc, ok := githost.Parse(s.Repo)
+	if !ok {
+		return ""
+	}
+	if loc.Kind == githost.GitHub {
+		return "https://codeload.github.com/" + loc.Path + "/tar.gz/" + ref
+	}
+	return loc.ArchiveURL(ref)
+}
+
 // GetRepoShortName returns a short display name for the repo
 func (s *Source) GetRepoShortName() string {
-	// github.com/owner/repo -> owner/repo
-	if strings.HasPrefix(s.Repo, "github.com/") {
-		return strings.TrimPrefix(s.Repo, "github.com/")
+	// github.com/owner/repo -> owner/repo; other hosts keep the host name
+	if loc, ok := githost.Parse(s.Repo); ok {
+		return loc.ShortName()
 	}
 	return s.Repo
 }
@@ -243,10 +546,11 @@ func (s *Source) GetRepoShortName() string {
 //   - Source keys from the user registry are prefixed with "user:" to avoid
 //     collisions with built-in source names.
 //
-// The returned ConflictWarnings slice (one entry per conflict) is intended for
+// The returned warnings (one entry per conflict, plus one per cached remote
+// registry skipped because its signature did not verify) are intended for
 // both CLI and TUI callers to surface to the user.
 func LoadWithUser() (*Registry, []string, error) {
-	reg, err := Load()
+	reg, warnings, err := load()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -256,7 +560,7 @@ func LoadWithUser() (*Registry, []string, error) {
 	userPath := userRegistryFilePath()
 	data, err := os.ReadFile(userPath)
 	if os.IsNotExist(err) {
-		return reg, nil, nil
+		return reg, warnings, nil
 	}
 	if err != nil {
 		return nil, nil, fmt.Errorf("reading user registry: %w", err)
@@ -275,9 +579,9 @@ func LoadWithUser() (*Registry, []string, error) {
 		}
 	}
 
-	var warnings []string
 	for srcKey, src := range userReg.Sources {
 		src.IsUser = true
+		src.Origin = OriginUser
 		userKey := "user:" + srcKey
 		for _, sk := range src.Skills {
 			if builtinSrc, conflict := builtinNames[strings.ToLower(sk.Name)]; conflict {
@@ -0,0 +1,90 @@
+package registry
+
+import "testing"
+
+func TestParseRefAndSkillRef(t *testing.T) {
+	data := []byte(`
+pinned:
+  repo: github.com/example/skills
+  ref: v1.2.0
+  skills:
+    - name: follows-source
+      path: skills/a
+    - name: own-pin
+      path: skills/b
+      ref: abc1234
+unpinned:
+  repo: github.com/example/other
+  skills:
+    - name: tip
+`)
+	reg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	pinned := reg.GetSource("pinned")
+	if pinned.Ref != "v1.2.0" {
+		t.Fatalf("source ref = %q; want v1.2.0", pinned.Ref)
+	}
+	if got := pinned.SkillRef(&pinned.Skills[0]); got != "v1.2.0" {
+		t.Errorf("SkillRef(follows-source) = %q; want v1.2.0", got)
+	}
+	if got := pinned.SkillRef(&pinned.Skills[1]); got != "abc1234" {
+		t.Errorf("SkillRef(own-pin) = %q; want abc1234", got)
+	}
+
+	unpinned := reg.GetSource("unpinned")
+	if got := unpinned.SkillRef(&unpinned.Skills[0]); got != "" {
+		t.Errorf("SkillRef(tip) = %q; want empty", got)
+	}
+}
+
+func TestParseTransport(t *testing.T) {
+	data := []byte(`
+codeload:
+  repo: github.com/example/skills
+  transport: archive
+  skills:
+    - name: a
+      path: skills/a
+mirror:
+  repo: https://git.example.com/team/skills.git
+  branch: stable
+  transport: archive
+  archive: https://git.example.com/team/skills/archive/{ref}.zip
+  skills:
+    - name: b
+      path: b
+plain:
+  repo: github.com/example/other
+  skills:
+    - name: c
+`)
+	reg, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	codeload := reg.GetSource("codeload")
+	if !codeload.UsesArchive() {
+		t.Fatal("codeload should use the archive transport")
+	}
+	if got := codeload.ArchiveURL(codeload.ArchiveRef("")); got != "https://codeload.github.com/example/skills/tar.gz/HEAD" {
+		t.Errorf("codeload archive = %q", got)
+	}
+	mirror := reg.GetSource("mirror")
+	if got := mirror.ArchiveURL(mirror.ArchiveRef("")); got != "https://git.example.com/team/skills/archive/stable.zip" {
+		t.Errorf("mirror archive = %q", got)
+	}
+	if got := mirror.ArchiveRef("v2"); got != "v2" {
+		t.Errorf("ArchiveRef(v2) = %q", got)
+	}
+	if reg.GetSource("plain").UsesArchive() {
+		t.Error("sources clone by default")
+	}
+
+	if _, err := Parse([]byte("bad:\n  repo: x\n  transport: rsync\n")); err == nil {
+		t.Error("unknown transport should be rejected")
+	}
+}
@@ -0,0 +1,276 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OfficialRemote is the remote refreshing the built-in registry. Its
+// sources keep their plain names; sources from other remotes are keyed
+// "<remote>:<source>".
+const OfficialRemote = "official"
+
+// OfficialRegistryURL is where the official remote is published
+const OfficialRegistryURL = "https://raw.githubusercontent.com/castle-x/skills-x/main/pkg/registry/registry.yaml"
+
+// OriginUser marks sources loaded from user-registry.yaml
+const OriginUser = "user"
+
+// Remote is a registry.yaml published at a URL and merged into the registry.
+// When several remotes have a skill with the same name, the one with the
+// higher priority wins; user registry entries win over every remote.
+type Remote struct {
+	Name     string   `yaml:"name"`
+	URL      string   `yaml:"url"`
+	Priority int      `yaml:"priority"`
+	Enabled  bool     `yaml:"enabled"`
+	Keys     []string `yaml:"keys,omitempty"` // Base64 ed25519 public keys its registry must be signed with
+}
+
+var remoteNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// ValidateRemoteName checks that name can be used as a remote and cache file name
+func ValidateRemoteName(name string) error {
+	if !remoteNamePattern.MatchString(name) || name == OriginUser {
+		return fmt.Errorf("invalid remote name %q (use lowercase letters, digits, '.', '-' or '_')", name)
+	}
+	return nil
+}
+
+// RemotesPath returns the path of the remotes list
+// (~/.config/skills-x/remotes.yaml)
+func RemotesPath() string {
+	return filepath.Join(filepath.Dir(userRegistryFilePath()), "remotes.yaml")
+}
+
+// CachePath returns where "registry update" stores the remote's registry.
+// The official remote keeps using CachedRegistryPath(); other remotes are
+// cached in a registries/ directory next to it.
+func (r Remote) CachePath() (string, error) {
+	cached, err := CachedRegistryPath()
+	if err != nil {
+		return "", err
+	}
+	if r.Name == OfficialRemote {
+		return cached, nil
+	}
+	return filepath.Join(filepath.Dir(cached), "registries", r.Name+".yaml"), nil
+}
+
+// LoadCached returns the registry the remote currently contributes: its
+// verified cache or, for the official remote, the embedded registry when
+// there is none. Other remotes without a usable cache yield an empty
+// registry.
+func (r Remote) LoadCached() (*Registry, error) {
+	if r.Name == OfficialRemote {
+		var ignored []string
+		return loadOfficial(r, &ignored)
+	}
+	reg, err := loadRemoteCache(r)
+	if err != nil {
+		return &Registry{Sources: map[string]*Source{}}, nil
+	}
+	return reg, nil
+}
+
+// CacheValidators are the HTTP validators of a cached registry. "registry
+// update" sends them back so an unchanged registry is not downloaded again.
+type CacheValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// validatorsSuffix is appended to a cache path to get its validators file
+const validatorsSuffix = ".http.json"
+
+// LoadValidators returns the validators saved with the remote's cache. They
+// are only returned while the cache itself exists.
+func (r Remote) LoadValidators() CacheValidators {
+	var v CacheValidators
+	path, err := r.CachePath()
+	if err != nil {
+		return v
+	}
+	if _, err := os.Stat(path); err != nil {
+		return v
+	}
+	if data, err := os.ReadFile(path + validatorsSuffix); err == nil {
+		_ = json.Unmarshal(data, &v)
+	}
+	return v
+}
+
+// SaveValidators stores the validators of a freshly written cache; empty
+// validators remove the file
+func (r Remote) SaveValidators(v CacheValidators) error {
+	path, err := r.CachePath()
+	if err != nil {
+		return err
+	}
+	path += validatorsSuffix
+	if v == (CacheValidators{}) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// remotesFile is the on-disk form of the remotes list. Enabled is a pointer
+// so that hand-written entries without it default to enabled.
+type remotesFile struct {
+	Remotes []struct {
+		Name     string   `yaml:"name"`
+		URL      string   `yaml:"url"`
+		Priority int      `yaml:"priority"`
+		Enabled  *bool    `yaml:"enabled"`
+		Keys     []string `yaml:"keys"`
+	} `yaml:"remotes"`
+}
+
+// defaultOfficial is the official remote as configured out of the box
+func defaultOfficial() Remote {
+	return Remote{Name: OfficialRemote, URL: OfficialRegistryURL, Enabled: true}
+}
+
+// LoadRemotes reads the configured remotes, sorted by descending priority.
+// The official remote is always present, with its defaults when the file
+// does not mention it.
+func LoadRemotes() ([]Remote, error) {
+	var remotes []Remote
+	data, err := os.ReadFile(RemotesPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading remotes: %w", err)
+	}
+	if err == nil {
+		var file remotesFile
+		if err := yaml.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("parsing remotes: %w", err)
+		}
+		seen := make(map[string]bool)
+		for _, r := range file.Remotes {
+			if err := ValidateRemoteName(r.Name); err != nil {
+				return nil, err
+			}
+			if seen[r.Name] {
+				return nil, fmt.Errorf("duplicate remote %q", r.Name)
+			}
+			seen[r.Name] = true
+			remote := Remote{Name: r.Name, URL: r.URL, Priority: r.Priority, Enabled: r.Enabled == nil || *r.Enabled, Keys: r.Keys}
+			if remote.Name == OfficialRemote && remote.URL == "" {
+				remote.URL = OfficialRegistryURL
+			}
+			remotes = append(remotes, remote)
+		}
+	}
+	if findRemote(remotes, OfficialRemote) < 0 {
+		remotes = append(remotes, defaultOfficial())
+	}
+	sortRemotes(remotes)
+	return remotes, nil
+}
+
+// SaveRemotes writes the remotes list
+func SaveRemotes(remotes []Remote) error {
+	sortRemotes(remotes)
+	data, err := yaml.Marshal(struct {
+		Remotes []Remote `yaml:"remotes"`
+	}{remotes})
+	if err != nil {
+		return err
+	}
+	path := RemotesPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// AddRemote adds a remote; the name must not be taken yet
+func AddRemote(r Remote) error {
+	if err := ValidateRemoteName(r.Name); err != nil {
+		return err
+	}
+	if r.URL == "" {
+		return fmt.Errorf("remote %q needs a URL", r.Name)
+	}
+	if _, err := r.TrustedKeys(); err != nil {
+		return err
+	}
+	remotes, err := LoadRemotes()
+	if err != nil {
+		return err
+	}
+	if findRemote(remotes, r.Name) >= 0 {
+		return fmt.Errorf("remote %q already exists", r.Name)
+	}
+	return SaveRemotes(append(remotes, r))
+}
+
+// RemoveRemote deletes a remote and everything cached for it. The official
+// remote cannot be removed, only disabled.
+func RemoveRemote(name string) error {
+	if name == OfficialRemote {
+		return fmt.Errorf("the %s remote cannot be removed; disable it instead", OfficialRemote)
+	}
+	remotes, err := LoadRemotes()
+	if err != nil {
+		return err
+	}
+	i := findRemote(remotes, name)
+	if i < 0 {
+		return fmt.Errorf("remote %q not found", name)
+	}
+	if cache, err := remotes[i].CachePath(); err == nil {
+		os.Remove(cache)
+		os.Remove(cache + SignatureSuffix)
+		os.Remove(cache + validatorsSuffix)
+	}
+	return SaveRemotes(append(remotes[:i], remotes[i+1:]...))
+}
+
+// SetRemoteEnabled enables or disables a remote. A disabled remote is
+// neither updated nor merged; disabling the official remote falls back to
+// the registry built into the binary.
+func SetRemoteEnabled(name string, enabled bool) error {
+	remotes, err := LoadRemotes()
+	if err != nil {
+		return err
+	}
+	i := findRemote(remotes, name)
+	if i < 0 {
+		return fmt.Errorf("remote %q not found", name)
+	}
+	remotes[i].Enabled = enabled
+	return SaveRemotes(remotes)
+}
+
+func findRemote(remotes []Remote, name string) int {
+	for i, r := range remotes {
+		if r.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortRemotes orders remotes by descending priority, then by name
+func sortRemotes(remotes []Remote) {
+	sort.SliceStable(remotes, func(i, j int) bool {
+		if remotes[i].Priority != remotes[j].Priority {
+			return remotes[i].Priority > remotes[j].Priority
+		}
+		return remotes[i].Name < remotes[j].Name
+	})
+}
@@ -0,0 +1,178 @@
+package registry
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeRemoteCache stores a cached registry for remote, signed with key
+// unless key is nil
+func writeRemoteCache(t *testing.T, remote Remote, content string, key ed25519.PrivateKey) {
+	t.Helper()
+	path, err := remote.CachePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if key != nil {
+		if err := os.WriteFile(path+SignatureSuffix, Sign([]byte(content), key), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// testKey returns a fresh signing key and its base64 public key
+func testKey(t *testing.T) (ed25519.PrivateKey, string) {
+	t.Helper()
+	pub, priv, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ParsePrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pub
+}
+
+func TestLoadRemotesDefaultsToOfficial(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	remotes, err := LoadRemotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remotes) != 1 || remotes[0].Name != OfficialRemote || !remotes[0].Enabled || remotes[0].URL != OfficialRegistryURL {
+		t.Fatalf("remotes = %+v; want only the enabled official remote", remotes)
+	}
+	official, _ := remotes[0].CachePath()
+	cached, _ := CachedRegistryPath()
+	if official != cached {
+		t.Errorf("official cache = %s; want %s", official, cached)
+	}
+}
+
+func TestAddRemoveRemote(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if err := AddRemote(Remote{Name: "team", URL: "https://example.com/registry.yaml", Priority: 5, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddRemote(Remote{Name: "team", URL: "https://example.com/other.yaml"}); err == nil {
+		t.Error("adding a duplicate remote should fail")
+	}
+	if err := AddRemote(Remote{Name: "Bad Name", URL: "https://example.com"}); err == nil {
+		t.Error("invalid names should be rejected")
+	}
+	if err := RemoveRemote(OfficialRemote); err == nil {
+		t.Error("the official remote should not be removable")
+	}
+
+	remotes, err := LoadRemotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remotes) != 2 || remotes[0].Name != "team" {
+		t.Fatalf("remotes = %+v; want team first (higher priority) then official", remotes)
+	}
+
+	team := remotes[0]
+	writeRemoteCache(t, team, "x:\n  repo: github.com/x/y\n", nil)
+	if err := RemoveRemote("team"); err != nil {
+		t.Fatal(err)
+	}
+	cache, _ := team.CachePath()
+	if _, err := os.Stat(cache); err == nil {
+		t.Error("removing a remote should delete its cache")
+	}
+	if remotes, _ := LoadRemotes(); len(remotes) != 1 {
+		t.Errorf("remotes after remove = %+v", remotes)
+	}
+}
+
+func TestLoadMergesRemotesByPriority(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// The official cache has to verify, so trust a test key for it
+	key, pub := testKey(t)
+	if err := SaveRemotes([]Remote{{Name: OfficialRemote, URL: OfficialRegistryURL, Enabled: true, Keys: []string{pub}}}); err != nil {
+		t.Fatal(err)
+	}
+	low := Remote{Name: "low", URL: "https://example.com/low.yaml", Priority: -1, Enabled: true}
+	high := Remote{Name: "high", URL: "https://example.com/high.yaml", Priority: 10, Enabled: true}
+	off := Remote{Name: "off", URL: "https://example.com/off.yaml", Priority: 100, Enabled: false}
+	for _, r := range []Remote{low, high, off} {
+		if err := AddRemote(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRemoteCache(t, Remote{Name: OfficialRemote}, `
+official-src:
+  repo: github.com/official/skills
+  skills:
+    - name: shared
+      path: skills/shared
+    - name: only-official
+      path: skills/only-official
+`, key)
+	for _, r := range []Remote{low, high, off} {
+		writeRemoteCache(t, r, `
+team:
+  repo: github.com/`+r.Name+`/skills
+  skills:
+    - name: shared
+      path: skills/shared
+    - name: only-`+r.Name+`
+      path: skills/only
+`, nil)
+	}
+
+	reg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := reg.GetSource("high:team")
+	if src == nil || src.Name != "high:team" || src.Origin != "high" || src.Priority != 10 {
+		t.Fatalf("high:team = %+v; want provenance from the high remote", src)
+	}
+	if reg.GetSource("off:team") != nil {
+		t.Error("disabled remotes should not be merged")
+	}
+	if s := reg.GetSource("official-src"); s == nil || s.Origin != OfficialRemote {
+		t.Fatalf("official-src = %+v; want the official cache", s)
+	}
+
+	tests := map[string]string{
+		"shared":        "high:team",
+		"only-official": "official-src",
+		"only-low":      "low:team",
+	}
+	for skill, want := range tests {
+		if _, got := reg.FindSkill(skill); got == nil || got.Name != want {
+			t.Errorf("FindSkill(%s) = %+v; want source %s", skill, got, want)
+		}
+	}
+
+	// Disabling the official remote falls back to the embedded registry
+	if err := SetRemoteEnabled(OfficialRemote, false); err != nil {
+		t.Fatal(err)
+	}
+	reg, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.GetSource("official-src") != nil {
+		t.Error("disabled official remote should not use its cache")
+	}
+	if _, got := reg.FindSkill("shared"); got == nil || got.Name != "high:team" {
+		t.Errorf("FindSkill(shared) = %+v; want high:team", got)
+	}
+}
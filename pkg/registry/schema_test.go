@@ -0,0 +1,125 @@
+package registry
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/versioncheck"
+)
+
+const v2Registry = `
+schema: 2
+sources:
+  team:
+    repo: github.com/team/skills
+    license: MIT
+    skills:
+      - name: pdf-tools
+        path: skills/pdf-tools
+        authors: [Ada, Grace]
+        homepage: https://example.com/pdf-tools
+        license: Apache-2.0
+        min_skills_x_version: "0.9.0"
+        products: [claude-code, Cursor]
+        aliases: [pdf]
+      - name: old-pdf
+        path: skills/old-pdf
+        deprecated: true
+        replaced_by: pdf-tools
+`
+
+func TestParseSchemaV2(t *testing.T) {
+	reg, err := Parse([]byte(v2Registry))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if reg.Schema != SchemaV2 {
+		t.Errorf("Schema = %d; want 2", reg.Schema)
+	}
+	src := reg.GetSource("team")
+	if src == nil || len(src.Skills) != 2 {
+		t.Fatalf("team = %+v", src)
+	}
+	got := src.Skills[0]
+	want := Skill{
+		Name:       "pdf-tools",
+		Path:       "skills/pdf-tools",
+		Authors:    []string{"Ada", "Grace"},
+		Homepage:   "https://example.com/pdf-tools",
+		License:    "Apache-2.0",
+		MinVersion: "0.9.0",
+		Products:   []string{"claude-code", "Cursor"},
+		Aliases:    []string{"pdf"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("skill = %+v\nwant %+v", got, want)
+	}
+	if old := src.Skills[1]; !old.Deprecated || old.ReplacedBy != "pdf-tools" {
+		t.Errorf("old-pdf = %+v; want deprecated in favour of pdf-tools", old)
+	}
+
+	// v1 files keep working and may use the new fields too
+	v1, err := Parse([]byte("team:\n  repo: github.com/team/skills\n  skills:\n    - name: a\n      aliases: [b]\n"))
+	if err != nil || v1.Schema != SchemaV1 || !v1.GetSource("team").Skills[0].HasAlias("B") {
+		t.Fatalf("v1 Parse = %+v, %v", v1, err)
+	}
+
+	if _, err := Parse([]byte("schema: 3\nsources: {}\n")); err == nil || !strings.Contains(err.Error(), "upgrade") {
+		t.Errorf("schema 3 error = %v; want an upgrade hint", err)
+	}
+	if _, err := Parse([]byte("schema: two\n")); err == nil {
+		t.Error("a non-numeric schema should be rejected")
+	}
+}
+
+func TestFindSkillResolvesAliases(t *testing.T) {
+	reg, err := Parse([]byte(v2Registry + `
+  other:
+    repo: github.com/other/skills
+    skills:
+      - name: pdf
+        path: skills/pdf
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skill, _ := reg.FindSkill("pdf"); skill == nil || skill.Name != "pdf" {
+		t.Errorf("FindSkill(pdf) = %+v; a real name wins over an alias", skill)
+	}
+	delete(reg.Sources, "other")
+	if skill, src := reg.FindSkill("PDF"); skill == nil || skill.Name != "pdf-tools" || src.Name != "team" {
+		t.Errorf("FindSkill(PDF) = %+v; want pdf-tools through its alias", skill)
+	}
+	if matches := reg.FindSkillsWithConflict("pdf"); len(matches) != 1 || matches[0].Skill.Name != "pdf-tools" {
+		t.Errorf("FindSkillsWithConflict(pdf) = %+v; want pdf-tools", matches)
+	}
+}
+
+func TestSkillProductsAndVersion(t *testing.T) {
+	skill := Skill{Products: []string{"claude-code", "Roo Code"}, MinVersion: "1.2.0"}
+	for product, want := range map[string]bool{
+		"Claude Code": true,
+		"roo_code":    true,
+		"Cursor":      false,
+		"":            true,
+	} {
+		if got := skill.SupportsProduct(product); got != want {
+			t.Errorf("SupportsProduct(%q) = %v; want %v", product, got, want)
+		}
+	}
+	if !(&Skill{}).SupportsProduct("Cursor") {
+		t.Error("skills without products support every product")
+	}
+
+	orig := versioncheck.Running
+	defer func() { versioncheck.Running = orig }()
+	versioncheck.Running = "1.1.9"
+	if skill.IsCompatible() {
+		t.Error("1.1.9 should not satisfy min_skills_x_version 1.2.0")
+	}
+	versioncheck.Running = "v1.2.0"
+	if !skill.IsCompatible() {
+		t.Error("1.2.0 should satisfy min_skills_x_version 1.2.0")
+	}
+}
@@ -0,0 +1,164 @@
+package registry
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	_ "embed"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// trustedKeys lists the public keys the official registry is signed with,
+// one base64 ed25519 key per line; '#' starts a comment.
+//
+//go:embed trusted_keys.txt
+var trustedKeys string
+
+// SignatureSuffix is appended to a registry URL or cache path to get its
+// detached signature (registry.yaml → registry.yaml.sig)
+const SignatureSuffix = ".sig"
+
+var (
+	// ErrUnsigned means a registry that must be signed has no signature
+	ErrUnsigned = errors.New("registry signature missing")
+	// ErrBadSignature means no trusted key verifies the signature
+	ErrBadSignature = errors.New("registry signature does not match any trusted key")
+)
+
+// EmbeddedKeys returns the public keys built into the binary
+func EmbeddedKeys() []ed25519.PublicKey {
+	var keys []ed25519.PublicKey
+	scanner := bufio.NewScanner(strings.NewReader(trustedKeys))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+		// The file is covered by tests, so a bad line is a build mistake
+		if key, err := ParsePublicKey(line); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// ParsePublicKey decodes a base64 ed25519 public key
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key %q: want %d base64-encoded bytes", s, ed25519.PublicKeySize)
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// ParsePrivateKey decodes a base64 ed25519 private key as written by
+// GenerateKey
+func ParsePrivateKey(s string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key: want %d base64-encoded bytes", ed25519.PrivateKeySize)
+	}
+	return ed25519.PrivateKey(raw), nil
+}
+
+// GenerateKey creates a signing key pair, both base64-encoded
+func GenerateKey() (publicKey, privateKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv), nil
+}
+
+// Sign returns the detached signature of a registry file, ready to be
+// written to registry.yaml.sig
+func Sign(data []byte, key ed25519.PrivateKey) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(key, data)) + "\n")
+}
+
+// VerifySignature checks a detached signature against the trusted keys
+func VerifySignature(data, sig []byte, keys []ed25519.PublicKey) error {
+	if len(strings.TrimSpace(string(sig))) == 0 {
+		return ErrUnsigned
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || len(raw) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: malformed signature", ErrBadSignature)
+	}
+	for _, key := range keys {
+		if ed25519.Verify(key, data, raw) {
+			return nil
+		}
+	}
+	return ErrBadSignature
+}
+
+// TrustedKeys returns the keys the remote's registry must be signed with.
+// The official remote trusts the embedded keys plus any configured ones;
+// other remotes trust only their configured keys. No keys means the remote
+// is unsigned and not verified.
+func (r Remote) TrustedKeys() ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	if r.Name == OfficialRemote {
+		keys = EmbeddedKeys()
+	}
+	for _, k := range r.Keys {
+		key, err := ParsePublicKey(k)
+		if err != nil {
+			return nil, fmt.Errorf("remote %s: %w", r.Name, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Verify checks registry data downloaded from or cached for the remote
+// against its detached signature. Unsigned remotes always pass.
+func (r Remote) Verify(data, sig []byte) error {
+	keys, err := r.TrustedKeys()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := VerifySignature(data, sig, keys); err != nil {
+		return fmt.Errorf("remote %s: %w", r.Name, err)
+	}
+	return nil
+}
+
+// VerifyCache checks the remote's cached registry against the signature
+// stored next to it, as loading the cache would
+func (r Remote) VerifyCache() error {
+	path, err := r.CachePath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	sig, err := readCachedSignature(path)
+	if err != nil {
+		return err
+	}
+	return r.Verify(data, sig)
+}
+
+// readCachedSignature reads the signature stored next to a cached registry;
+// a missing file reads as empty so that Verify reports ErrUnsigned
+func readCachedSignature(cachePath string) ([]byte, error) {
+	sig, err := os.ReadFile(cachePath + SignatureSuffix)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return sig, err
+}
@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedKeys(t *testing.T) {
+	lines := 0
+	scanner := bufio.NewScanner(strings.NewReader(trustedKeys))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		lines++
+		if _, err := ParsePublicKey(line); err != nil {
+			t.Errorf("trusted_keys.txt: %v", err)
+		}
+	}
+	if len(EmbeddedKeys()) != lines {
+		t.Fatalf("EmbeddedKeys() = %d keys; want %d", len(EmbeddedKeys()), lines)
+	}
+}
+
+// withReleaseKey trusts pub for the official registry, as a release build
+// does, until the test ends
+func withReleaseKey(t *testing.T, pub string) {
+	t.Helper()
+	old := trustedKeys
+	trustedKeys = pub + "  # release\n"
+	t.Cleanup(func() { trustedKeys = old })
+}
+
+func TestVerifySignature(t *testing.T) {
+	key, pub := testKey(t)
+	_, otherPub := testKey(t)
+	trusted, _ := ParsePublicKey(pub)
+	other, _ := ParsePublicKey(otherPub)
+
+	data := []byte("src:\n  repo: github.com/a/b\n")
+	sig := Sign(data, key)
+
+	tests := []struct {
+		name string
+		data []byte
+		sig  []byte
+		want error
+	}{
+		{"valid", data, sig, nil},
+		{"tampered", []byte("src:\n  repo: github.com/evil/b\n"), sig, ErrBadSignature},
+		{"missing", data, nil, ErrUnsigned},
+		{"malformed", data, []byte("not-base64!"), ErrBadSignature},
+	}
+	for _, tt := range tests {
+		err := VerifySignature(tt.data, tt.sig, []ed25519.PublicKey{other, trusted})
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("%s: error = %v; want %v", tt.name, err, tt.want)
+		}
+	}
+	if err := VerifySignature(data, sig, []ed25519.PublicKey{other}); !errors.Is(err, ErrBadSignature) {
+		t.Errorf("untrusted key: error = %v; want ErrBadSignature", err)
+	}
+}
+
+func TestLoadIgnoresUnverifiedCaches(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	key, pub := testKey(t)
+	team := Remote{Name: "team", URL: "https://example.com/registry.yaml", Enabled: true, Keys: []string{pub}}
+	if err := AddRemote(team); err != nil {
+		t.Fatal(err)
+	}
+	content := "team-src:\n  repo: github.com/team/skills\n  skills:\n    - name: team-skill\n      path: skills/team-skill\n"
+	writeRemoteCache(t, team, content, key)
+	_, releasePub := testKey(t)
+	withReleaseKey(t, releasePub)
+	// An official cache without a signature must not replace the embedded registry
+	writeRemoteCache(t, Remote{Name: OfficialRemote}, "evil:\n  repo: github.com/evil/skills\n", nil)
+
+	reg, warnings, err := LoadWithUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.GetSource("team:team-src") == nil {
+		t.Fatal("signed remote cache should be loaded")
+	}
+	if reg.GetSource("evil") != nil || reg.GetSource("anthropic") == nil {
+		t.Fatal("unsigned official cache should fall back to the embedded registry")
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "signature missing") {
+		t.Fatalf("warnings = %q; want one about the unsigned official cache", warnings)
+	}
+
+	// Tamper with the cached file after it was verified and stored
+	path, _ := team.CachePath()
+	if err := os.WriteFile(path, []byte(strings.ReplaceAll(content, "team/skills", "evil/skills")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reg, warnings, err = LoadWithUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.GetSource("team:team-src") != nil {
+		t.Fatal("tampered cache should be ignored")
+	}
+	if len(warnings) != 2 || !strings.Contains(warnings[1], filepath.Base(path)) {
+		t.Fatalf("warnings = %q; want one naming the tampered cache", warnings)
+	}
+}
@@ -0,0 +1,174 @@
+package search
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/castle-x/skills-x/pkg/gitutil"
+	"github.com/castle-x/skills-x/pkg/registry"
+	"github.com/castle-x/skills-x/pkg/skilldiff"
+)
+
+// Limits on what is read from a skill directory
+const (
+	maxFileSize    = 256 << 10
+	maxContentSize = 1 << 20
+)
+
+// contentExts are the reference files indexed next to SKILL.md
+var contentExts = map[string]bool{".md": true, ".mdx": true, ".txt": true}
+
+// Update returns an index over every skill in reg. Content comes from the
+// git cache: a skill whose repository has been cloned or downloaded before
+// is indexed with its SKILL.md and reference files, others with registry
+// metadata only. Documents from prev are reused while their files are
+// unchanged. changed reports whether the result differs from prev.
+func Update(reg *registry.Registry, prev *Index) (ix *Index, changed bool) {
+	reused := make(map[string]*Document)
+	if prev != nil {
+		for i := range prev.Docs {
+			reused[prev.Docs[i].ID] = &prev.Docs[i]
+		}
+	}
+
+	var docs []Document
+	for _, src := range reg.Sources {
+		checkouts := gitutil.CachedCheckouts(src.Repo)
+		for _, sk := range src.Skills {
+			doc := Document{
+				ID:            src.Name + "/" + sk.Name,
+				Name:          sk.Name,
+				Source:        src.Name,
+				Description:   sk.Description,
+				DescriptionZh: sk.DescriptionZh,
+				Tags:          sk.Tags,
+			}
+			if dir := findSkillDir(checkouts, &sk); dir != "" {
+				doc.Dir, doc.Signature = dir, signature(dir)
+				if old := reused[doc.ID]; old != nil && old.Dir == doc.Dir && old.Signature == doc.Signature {
+					doc.Content = old.Content
+				} else {
+					doc.Content = readContent(dir)
+				}
+			}
+			if old := reused[doc.ID]; old == nil || !sameDocument(old, &doc) {
+				changed = true
+			}
+			docs = append(docs, doc)
+		}
+	}
+	if prev == nil || len(docs) != len(prev.Docs) {
+		changed = true
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+	return New(docs), changed
+}
+
+// Open loads the stored index, brings it up to date with reg and saves it
+// when anything changed
+func Open(reg *registry.Registry) (*Index, error) {
+	ix, changed := Update(reg, Load())
+	if changed {
+		if err := ix.Save(); err != nil {
+			return ix, err
+		}
+	}
+	return ix, nil
+}
+
+// Unindexed returns the documents that have no cached content
+func (ix *Index) Unindexed() []*Document {
+	var out []*Document
+	for i := range ix.Docs {
+		if !ix.Docs[i].Indexed() {
+			out = append(out, &ix.Docs[i])
+		}
+	}
+	return out
+}
+
+func sameDocument(a, b *Document) bool {
+	return a.Name == b.Name && a.Source == b.Source && a.Description == b.Description &&
+		a.DescriptionZh == b.DescriptionZh && strings.Join(a.Tags, ",") == strings.Join(b.Tags, ",") &&
+		a.Dir == b.Dir && a.Signature == b.Signature
+}
+
+// findSkillDir returns the first checkout directory holding the skill.
+// Skills without a path are looked for where discovery usually finds them.
+func findSkillDir(checkouts []string, sk *registry.Skill) string {
+	candidates := []string{sk.Path}
+	if sk.Path == "" {
+		candidates = []string{"skills/" + sk.Name, sk.Name, ""}
+	}
+	for _, checkout := range checkouts {
+		for _, rel := range candidates {
+			dir := filepath.Join(checkout, filepath.FromSlash(rel))
+			if _, err := os.Stat(filepath.Join(dir, "SKILL.md")); err == nil {
+				return dir
+			}
+		}
+	}
+	return ""
+}
+
+// signature summarizes the sizes and modification times of the indexed files
+func signature(dir string) string {
+	var count, size, mtime int64
+	walkContent(dir, func(path string, info fs.FileInfo) {
+		count++
+		size += info.Size()
+		mtime += info.ModTime().UnixNano()
+	})
+	return fmt.Sprintf("%d-%d-%x", count, size, mtime)
+}
+
+// readContent concatenates SKILL.md and the reference files of a skill
+func readContent(dir string) string {
+	var b strings.Builder
+	walkContent(dir, func(path string, info fs.FileInfo) {
+		if b.Len() >= maxContentSize {
+			return
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || skilldiff.IsBinary(data) {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.Write(data)
+	})
+	return b.String()
+}
+
+// walkContent calls fn for SKILL.md and then every text file under dir,
+// skipping hidden directories and oversized files
+func walkContent(dir string, fn func(path string, info fs.FileInfo)) {
+	if info, err := os.Stat(filepath.Join(dir, "SKILL.md")); err == nil {
+		fn(filepath.Join(dir, "SKILL.md"), info)
+	}
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path == filepath.Join(dir, "SKILL.md") || !contentExts[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxFileSize {
+			return nil
+		}
+		fn(path, info)
+		return nil
+	})
+}
@@ -0,0 +1,324 @@
+// Package search provides a local full-text index over registry skills,
+// ranked with BM25
+package search
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Field weights: a query term in a skill's name says more than one in
+// its description, which says more than one in its SKILL.md body
+const (
+	nameWeight        = 3
+	descriptionWeight = 2
+	contentWeight     = 1
+)
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// snippetLength is the approximate length of a snippet in bytes
+const snippetLength = 160
+
+// Document is one registry skill in the index
+type Document struct {
+	ID            string   `json:"id"` // "<source>/<skill>", the FullName the TUI uses
+	Name          string   `json:"name"`
+	Source        string   `json:"source"`
+	Description   string   `json:"description"`
+	DescriptionZh string   `json:"description_zh,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
+	Dir           string   `json:"dir,omitempty"`       // Cached skill directory the content was read from
+	Signature     string   `json:"signature,omitempty"` // Changes when files in Dir change
+	Content       string   `json:"content,omitempty"`   // SKILL.md and reference files; empty when not cached
+}
+
+// Indexed reports whether the document has SKILL.md content, as opposed to
+// registry metadata only
+func (d *Document) Indexed() bool {
+	return d.Content != ""
+}
+
+// Index is a BM25 index over documents
+type Index struct {
+	Docs []Document `json:"docs"`
+
+	terms   []map[string]int // Weighted term frequencies per document
+	lengths []int
+	df      map[string]int
+	avgLen  float64
+}
+
+// Result is one search hit
+type Result struct {
+	Doc        *Document
+	Score      float64
+	Snippet    string   // Excerpt of the content around the matches; "" when only metadata matched
+	Highlights [][2]int // Byte ranges of matched words in Snippet
+}
+
+// HighlightSnippet returns the snippet with every match passed through mark
+func (r Result) HighlightSnippet(mark func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, h := range r.Highlights {
+		if h[0] < last || h[1] > len(r.Snippet) {
+			continue
+		}
+		b.WriteString(r.Snippet[last:h[0]])
+		b.WriteString(mark(r.Snippet[h[0]:h[1]]))
+		last = h[1]
+	}
+	b.WriteString(r.Snippet[last:])
+	return b.String()
+}
+
+// New builds an index over docs
+func New(docs []Document) *Index {
+	ix := &Index{Docs: docs}
+	ix.prepare()
+	return ix
+}
+
+// prepare computes term statistics from Docs
+func (ix *Index) prepare() {
+	ix.terms = make([]map[string]int, len(ix.Docs))
+	ix.lengths = make([]int, len(ix.Docs))
+	ix.df = make(map[string]int)
+	total := 0
+	for i := range ix.Docs {
+		d := &ix.Docs[i]
+		tf := make(map[string]int)
+		add := func(text string, weight int) {
+			for _, t := range tokenize(text) {
+				tf[t.term] += weight
+				ix.lengths[i] += weight
+			}
+		}
+		add(d.Name, nameWeight)
+		add(d.Description, descriptionWeight)
+		add(d.DescriptionZh, descriptionWeight)
+		add(strings.Join(d.Tags, " "), descriptionWeight)
+		add(d.Content, contentWeight)
+		ix.terms[i] = tf
+		for term := range tf {
+			ix.df[term]++
+		}
+		total += ix.lengths[i]
+	}
+	if len(ix.Docs) > 0 {
+		ix.avgLen = float64(total) / float64(len(ix.Docs))
+	}
+}
+
+// Search returns the documents matching any query word, best first, at
+// most limit of them (all when limit <= 0). A query word that is not in
+// the index matches the words it is a prefix of, so results keep up while
+// the query is typed.
+func (ix *Index) Search(query string, limit int) []Result {
+	terms := ix.expand(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	n := float64(len(ix.Docs))
+	var results []Result
+	for i := range ix.Docs {
+		score := 0.0
+		for term, weight := range terms {
+			tf := float64(ix.terms[i][term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(ix.df[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(ix.lengths[i])/ix.avgLen)
+			score += weight * idf * tf * (bm25K1 + 1) / (tf + norm)
+		}
+		if score > 0 {
+			results = append(results, Result{Doc: &ix.Docs[i], Score: score})
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Doc.ID < results[j].Doc.ID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	for i := range results {
+		results[i].Snippet, results[i].Highlights = snippet(results[i].Doc.Content, terms)
+	}
+	return results
+}
+
+// expand turns a query into index terms with their weights. Words in the
+// index count fully; other words match the terms they prefix at a lower
+// weight.
+func (ix *Index) expand(query string) map[string]float64 {
+	terms := make(map[string]float64)
+	for _, t := range tokenize(query) {
+		if _, ok := ix.df[t.term]; ok {
+			terms[t.term] = 1
+			continue
+		}
+		if len(t.term) < 2 {
+			continue
+		}
+		for term := range ix.df {
+			if strings.HasPrefix(term, t.term) && terms[term] < 0.8 {
+				terms[term] = 0.8
+			}
+		}
+	}
+	return terms
+}
+
+// snippet cuts the window of content with the most matched words and
+// returns it on one line, with the byte ranges of the matches
+func snippet(content string, terms map[string]float64) (string, [][2]int) {
+	tokens := tokenize(content)
+	var hits []token
+	for _, t := range tokens {
+		if _, ok := terms[t.term]; ok {
+			hits = append(hits, t)
+		}
+	}
+	if len(hits) == 0 {
+		return "", nil
+	}
+
+	// Window starting a little before the hit that sees the most distinct terms
+	best, bestCount := 0, 0
+	for i := range hits {
+		seen := make(map[string]bool)
+		for j := i; j < len(hits) && hits[j].end-hits[i].start <= snippetLength-40; j++ {
+			seen[hits[j].term] = true
+		}
+		if len(seen) > bestCount {
+			best, bestCount = i, len(seen)
+		}
+	}
+	start := hits[best].start - 40
+	if start < 0 {
+		start = 0
+	}
+	end := start + snippetLength
+	if end > len(content) {
+		end = len(content)
+	}
+	for start > 0 && !utf8.RuneStart(content[start]) {
+		start--
+	}
+	for end < len(content) && !utf8.RuneStart(content[end]) {
+		end++
+	}
+
+	// Collapse whitespace and markdown line markup, then find the matches again
+	text := strings.Join(strings.Fields(content[start:end]), " ")
+	if start > 0 {
+		text = "…" + text
+	}
+	if end < len(content) {
+		text += "…"
+	}
+	var ranges [][2]int
+	for _, t := range tokenize(text) {
+		if _, ok := terms[t.term]; ok {
+			ranges = append(ranges, [2]int{t.start, t.end})
+		}
+	}
+	return text, ranges
+}
+
+// token is a normalized word and its byte range in the text
+type token struct {
+	term       string
+	start, end int
+}
+
+// tokenize splits text into lowercase words. Han characters are words of
+// their own, since Chinese has no spaces. A plural "s" is dropped so
+// "hooks" matches "hook".
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, token{normalize(text[start:end]), start, end})
+			start = -1
+		}
+	}
+	for i, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush(i)
+			size := utf8.RuneLen(r)
+			tokens = append(tokens, token{string(r), i, i + size})
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if start < 0 {
+				start = i
+			}
+		default:
+			flush(i)
+		}
+	}
+	flush(len(text))
+	return tokens
+}
+
+func normalize(word string) string {
+	word = strings.ToLower(word)
+	if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+// Path returns where the index is stored (~/.cache/skills-x/search-index.json)
+func Path() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "skills-x", "search-index.json")
+}
+
+// Load reads the stored index; a missing or unreadable file gives an empty index
+func Load() *Index {
+	ix := &Index{}
+	if data, err := os.ReadFile(Path()); err == nil {
+		_ = json.Unmarshal(data, ix)
+	}
+	ix.prepare()
+	return ix
+}
+
+// Save stores the index
+func (ix *Index) Save() error {
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,93 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/registry"
+)
+
+func TestSearchRanksAndHighlights(t *testing.T) {
+	ix := New([]Document{
+		{ID: "a/pocketbase", Name: "pocketbase", Description: "Go backend", Content: "Register hooks with app.OnRecordCreate.\nHooks run in order."},
+		{ID: "a/react", Name: "react", Description: "React patterns with hooks", Content: "Components and state."},
+		{ID: "b/pdf", Name: "pdf", Description: "PDF forms", Content: "Fill PDF forms."},
+	})
+
+	results := ix.Search("pocketbase hooks", 0)
+	if len(results) != 2 || results[0].Doc.ID != "a/pocketbase" {
+		t.Fatalf("results = %+v; want pocketbase first, then react", results)
+	}
+	r := results[0]
+	got := r.HighlightSnippet(func(w string) string { return "[" + w + "]" })
+	if got != "Register [hooks] with app.OnRecordCreate. [Hooks] run in order." {
+		t.Errorf("snippet = %q", got)
+	}
+	if results[1].Snippet != "" {
+		t.Errorf("react matched in its description only, snippet = %q", results[1].Snippet)
+	}
+
+	// Prefixes match while typing; limits apply
+	if results := ix.Search("pocketb", 1); len(results) != 1 || results[0].Doc.ID != "a/pocketbase" {
+		t.Errorf("prefix search = %+v", results)
+	}
+	if results := ix.Search("zzz", 0); results != nil {
+		t.Errorf("unknown word matched %+v", results)
+	}
+}
+
+func TestTokenizeChinese(t *testing.T) {
+	ix := New([]Document{{ID: "a/x", Name: "x", DescriptionZh: "生成演示文稿"}})
+	if results := ix.Search("演示", 0); len(results) != 1 {
+		t.Errorf("Chinese search = %+v", results)
+	}
+}
+
+func TestUpdateReadsGitCache(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	skillDir := filepath.Join(os.TempDir(), "skills-github-com-team-skills-0123abcd", "skills", "pb")
+	if err := os.MkdirAll(filepath.Join(skillDir, "references"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# PocketBase\nUse record hooks.\n"), 0644)
+	os.WriteFile(filepath.Join(skillDir, "references", "api.md"), []byte("OnBootstrap reference\n"), 0644)
+	os.WriteFile(filepath.Join(skillDir, "logo.png"), []byte("\x89PNG\x00OnBootstrap"), 0644)
+	// A repository whose name only starts the same is not this one
+	other := filepath.Join(os.TempDir(), "skills-github-com-team-skills-extra-0123abcd", "skills", "pb")
+	os.MkdirAll(other, 0755)
+	os.WriteFile(filepath.Join(other, "SKILL.md"), []byte("wrong repo\n"), 0644)
+
+	reg, err := registry.Parse([]byte("team:\n  repo: github.com/team/skills\n  skills:\n    - name: pb\n      path: skills/pb\n      description: PocketBase\n    - name: uncached\n      path: skills/uncached\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ix, err := Open(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := ix.Docs[0]
+	if doc.ID != "team/pb" || !strings.Contains(doc.Content, "record hooks") || !strings.Contains(doc.Content, "OnBootstrap reference") {
+		t.Fatalf("doc = %+v; want SKILL.md and references", doc)
+	}
+	if strings.Contains(doc.Content, "PNG") || strings.Contains(doc.Content, "wrong repo") {
+		t.Errorf("indexed content from the wrong files: %q", doc.Content)
+	}
+	if missing := ix.Unindexed(); len(missing) != 1 || missing[0].ID != "team/uncached" {
+		t.Errorf("Unindexed = %+v", missing)
+	}
+
+	// The stored index is reused while nothing changes
+	if _, changed := Update(reg, Load()); changed {
+		t.Error("Update reported a change for an unchanged cache")
+	}
+	os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte("# PocketBase\nUse record hooks and migrations.\n"), 0644)
+	ix, changed := Update(reg, Load())
+	if !changed || len(ix.Search("migrations", 0)) != 1 {
+		t.Errorf("edited SKILL.md was not reindexed (changed = %v)", changed)
+	}
+}
@@ -0,0 +1,135 @@
+package skilldiff
+
+import "strings"
+
+// Conflict markers written by Merge3 around regions both sides changed
+const (
+	MarkerLocal    = "<<<<<<< local"
+	MarkerSep      = "======="
+	MarkerUpstream = ">>>>>>> upstream"
+)
+
+// change replaces base[start:end] with lines
+type change struct {
+	start, end int
+	lines      []string
+}
+
+// Merge3 merges the edits that local and upstream made to base. Regions only
+// one side touched take that side's version; regions both sides changed in
+// different ways are written between conflict markers, and conflict is true.
+func Merge3(base string, local string, upstream string) (merged string, conflict bool) {
+	b := splitLines(base)
+	l := splitLines(local)
+	u := splitLines(upstream)
+	lc := changes(diffLines(b, l))
+	uc := changes(diffLines(b, u))
+
+	var out []string
+	pos := 0
+	i, j := 0, 0
+	for i < len(lc) || j < len(uc) {
+		// Start a group at the earliest remaining change
+		start := 0
+		if j >= len(uc) || (i < len(lc) && lc[i].start <= uc[j].start) {
+			start = lc[i].start
+		} else {
+			start = uc[j].start
+		}
+		end := start
+		li, ui := i, j
+		for {
+			if i < len(lc) && lc[i].start <= end {
+				end = max(end, lc[i].end)
+				i++
+			} else if j < len(uc) && uc[j].start <= end {
+				end = max(end, uc[j].end)
+				j++
+			} else {
+				break
+			}
+		}
+
+		out = append(out, b[pos:start]...)
+		lv := apply(b, start, end, lc[li:i])
+		uv := apply(b, start, end, uc[ui:j])
+		switch {
+		case ui == j:
+			out = append(out, lv...)
+		case li == i:
+			out = append(out, uv...)
+		case equalLines(lv, uv):
+			out = append(out, lv...)
+		default:
+			conflict = true
+			out = append(out, MarkerLocal)
+			out = append(out, lv...)
+			out = append(out, MarkerSep)
+			out = append(out, uv...)
+			out = append(out, MarkerUpstream)
+		}
+		pos = end
+	}
+	out = append(out, b[pos:]...)
+
+	if len(out) == 0 {
+		return "", conflict
+	}
+	return strings.Join(out, "\n") + "\n", conflict
+}
+
+// changes groups an edit script into replacements of base line ranges
+func changes(edits []edit) []change {
+	var result []change
+	pos := 0
+	var cur *change
+	for _, e := range edits {
+		switch e.kind {
+		case editEqual:
+			if cur != nil {
+				result = append(result, *cur)
+				cur = nil
+			}
+			pos++
+		case editDelete:
+			if cur == nil {
+				cur = &change{start: pos, end: pos}
+			}
+			pos++
+			cur.end = pos
+		case editInsert:
+			if cur == nil {
+				cur = &change{start: pos, end: pos}
+			}
+			cur.lines = append(cur.lines, e.line)
+		}
+	}
+	if cur != nil {
+		result = append(result, *cur)
+	}
+	return result
+}
+
+// apply returns base[start:end] with one side's changes in that range applied
+func apply(base []string, start int, end int, cs []change) []string {
+	var out []string
+	pos := start
+	for _, c := range cs {
+		out = append(out, base[pos:c.start]...)
+		out = append(out, c.lines...)
+		pos = c.end
+	}
+	return append(out, base[pos:end]...)
+}
+
+func equalLines(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
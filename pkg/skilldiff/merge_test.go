@@ -0,0 +1,38 @@
+package skilldiff
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMerge3_NonOverlappingChanges(t *testing.T) {
+	base := "a\nb\nc\nd\ne\n"
+	local := "A\nb\nc\nd\ne\n"
+	upstream := "a\nb\nc\nd\ne\nf\n"
+
+	got, conflict := Merge3(base, local, upstream)
+	if conflict {
+		t.Fatalf("unexpected conflict:\n%s", got)
+	}
+	if want := "A\nb\nc\nd\ne\nf\n"; got != want {
+		t.Fatalf("Merge3 = %q; want %q", got, want)
+	}
+}
+
+func TestMerge3_SameChangeOnBothSides(t *testing.T) {
+	got, conflict := Merge3("a\nb\n", "a\nB\n", "a\nB\n")
+	if conflict || got != "a\nB\n" {
+		t.Fatalf("Merge3 = %q, conflict=%v", got, conflict)
+	}
+}
+
+func TestMerge3_Conflict(t *testing.T) {
+	got, conflict := Merge3("a\nb\nc\n", "a\nlocal\nc\n", "a\nupstream\nc\n")
+	if !conflict {
+		t.Fatal("expected a conflict")
+	}
+	want := strings.Join([]string{"a", MarkerLocal, "local", MarkerSep, "upstream", MarkerUpstream, "c", ""}, "\n")
+	if got != want {
+		t.Fatalf("Merge3 =\n%s\nwant:\n%s", got, want)
+	}
+}
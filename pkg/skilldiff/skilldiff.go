@@ -0,0 +1,383 @@
+// Package skilldiff compares an installed skill directory with an upstream
+// copy and renders unified diffs of the text files that differ.
+package skilldiff
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultContext is the number of unchanged lines shown around each change
+const DefaultContext = 3
+
+// maxEditDistance bounds the line diff; beyond it a file is shown as fully
+// replaced instead of spending quadratic memory on the edit script.
+const maxEditDistance = 2000
+
+// ignoredFiles are never compared (skills-x bookkeeping, VCS metadata)
+var ignoredFiles = map[string]bool{
+	".skills-x-meta.json": true,
+	".git":                true,
+}
+
+// Status describes how a file differs between the two directories
+type Status int
+
+const (
+	Added    Status = iota // Only upstream has the file
+	Removed                // Only the installed copy has the file
+	Modified               // Both have the file with different content
+)
+
+// Symbol returns the one-letter marker used in file lists (A/D/M)
+func (s Status) Symbol() string {
+	switch s {
+	case Added:
+		return "A"
+	case Removed:
+		return "D"
+	default:
+		return "M"
+	}
+}
+
+// FileChange is one file that differs
+type FileChange struct {
+	Path   string // Slash-separated path relative to the skill root
+	Status Status
+	Binary bool   // True when either side is not UTF-8 text
+	Diff   string // Unified diff for text files ("" for binary files)
+}
+
+// Compare lists the files that differ between an installed skill (localDir)
+// and its upstream copy (remoteDir), sorted by path. Text files carry a
+// unified diff from the installed to the upstream content.
+func Compare(localDir string, remoteDir string) ([]FileChange, error) {
+	local, err := listFiles(localDir)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := listFiles(remoteDir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]bool, len(local)+len(remote))
+	for p := range local {
+		paths[p] = true
+	}
+	for p := range remote {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	var changes []FileChange
+	for _, p := range sorted {
+		var oldData, newData []byte
+		change := FileChange{Path: p}
+
+		_, inLocal := local[p]
+		_, inRemote := remote[p]
+		switch {
+		case inLocal && !inRemote:
+			change.Status = Removed
+		case !inLocal && inRemote:
+			change.Status = Added
+		default:
+			change.Status = Modified
+		}
+
+		if inLocal {
+			if oldData, err = os.ReadFile(local[p]); err != nil {
+				return nil, err
+			}
+		}
+		if inRemote {
+			if newData, err = os.ReadFile(remote[p]); err != nil {
+				return nil, err
+			}
+		}
+		if change.Status == Modified && bytes.Equal(oldData, newData) {
+			continue
+		}
+
+		if isBinary(oldData) || isBinary(newData) {
+			change.Binary = true
+		} else {
+			oldName, newName := "a/"+p, "b/"+p
+			if !inLocal {
+				oldName = "/dev/null"
+			}
+			if !inRemote {
+				newName = "/dev/null"
+			}
+			change.Diff = Unified(oldName, newName, splitLines(string(oldData)), splitLines(string(newData)), DefaultContext)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// HashFiles returns the SHA-256 of every file in a skill directory, keyed by
+// slash-separated relative path. Bookkeeping files are skipped, as in Compare.
+func HashFiles(dir string) (map[string]string, error) {
+	files, err := listFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]string, len(files))
+	for rel, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		hashes[rel] = HashBytes(data)
+	}
+	return hashes, nil
+}
+
+// HashBytes returns the hex SHA-256 of data, in the form used by HashFiles
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// IsBinary reports whether data looks like a binary file
+func IsBinary(data []byte) bool {
+	return isBinary(data)
+}
+
+// listFiles maps slash-separated relative paths to absolute paths for every
+// regular file under dir. Symlinks are followed, matching how skills are copied.
+func listFiles(dir string) (map[string]string, error) {
+	files := make(map[string]string)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return files, nil
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && ignoredFiles[d.Name()] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = path
+		return nil
+	})
+	return files, err
+}
+
+// isBinary reports whether data looks like a binary file
+func isBinary(data []byte) bool {
+	head := data
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	return bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(data)
+}
+
+// splitLines splits text into lines without their trailing newline
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// editKind is one step of a line edit script
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+type edit struct {
+	kind editKind
+	line string
+}
+
+// Unified renders a unified diff between two line slices. It returns "" when
+// the inputs are equal.
+func Unified(oldName string, newName string, a []string, b []string, context int) string {
+	edits := diffLines(a, b)
+
+	// Indices of changed edits, used to cut hunks with context around them
+	var changed []int
+	for i, e := range edits {
+		if e.kind != editEqual {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+
+	// Line positions before each edit
+	aPos := make([]int, len(edits)+1)
+	bPos := make([]int, len(edits)+1)
+	for i, e := range edits {
+		aPos[i+1], bPos[i+1] = aPos[i], bPos[i]
+		if e.kind != editInsert {
+			aPos[i+1]++
+		}
+		if e.kind != editDelete {
+			bPos[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	for i := 0; i < len(changed); {
+		start := max(changed[i]-context, 0)
+		end := changed[i] + context
+		j := i + 1
+		for j < len(changed) && changed[j]-context <= end+1 {
+			end = changed[j] + context
+			j++
+		}
+		end = min(end, len(edits)-1)
+
+		aCount := aPos[end+1] - aPos[start]
+		bCount := bPos[end+1] - bPos[start]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aPos[start], aCount), hunkRange(bPos[start], bCount))
+		for _, e := range edits[start : end+1] {
+			switch e.kind {
+			case editEqual:
+				sb.WriteString(" ")
+			case editDelete:
+				sb.WriteString("-")
+			case editInsert:
+				sb.WriteString("+")
+			}
+			sb.WriteString(e.line)
+			sb.WriteString("\n")
+		}
+		i = j
+	}
+	return sb.String()
+}
+
+// hunkRange formats a unified diff range ("start,count", 1-based)
+func hunkRange(pos int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", pos)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", pos+1)
+	}
+	return fmt.Sprintf("%d,%d", pos+1, count)
+}
+
+// diffLines computes a shortest edit script with Myers' O(ND) algorithm.
+func diffLines(a []string, b []string) []edit {
+	n, m := len(a), len(b)
+	maxD := min(n+m, maxEditDistance)
+
+	// v[k+off] holds the furthest x reached on diagonal k. trace[d] is the
+	// window of v (diagonals -d-1..d+1) at the start of round d.
+	off := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, append([]int(nil), v[off-d-1:off+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return backtrack(trace, a, b)
+			}
+		}
+	}
+
+	// Too many differences: show the file as fully replaced
+	edits := make([]edit, 0, n+m)
+	for _, line := range a {
+		edits = append(edits, edit{editDelete, line})
+	}
+	for _, line := range b {
+		edits = append(edits, edit{editInsert, line})
+	}
+	return edits
+}
+
+// backtrack walks the Myers trace from the end to recover the edit script
+func backtrack(trace [][]int, a []string, b []string) []edit {
+	x, y := len(a), len(b)
+	var edits []edit
+
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{editEqual, a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				edits = append(edits, edit{editInsert, b[y]})
+			} else {
+				x--
+				edits = append(edits, edit{editDelete, a[x]})
+			}
+		}
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
@@ -0,0 +1,107 @@
+package skilldiff
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+func TestUnified_SingleHunk(t *testing.T) {
+	a := []string{"one", "two", "three", "four", "five"}
+	b := []string{"one", "two", "THREE", "four", "five", "six"}
+
+	got := Unified("a/f", "b/f", a, b, 1)
+	want := strings.Join([]string{
+		"--- a/f",
+		"+++ b/f",
+		"@@ -2,4 +2,5 @@",
+		" two",
+		"-three",
+		"+THREE",
+		" four",
+		" five",
+		"+six",
+		"",
+	}, "\n")
+	if got != want {
+		t.Fatalf("Unified mismatch\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestUnified_SeparateHunksAndEqual(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		line := strings.Repeat("x", i+1)
+		a = append(a, line)
+		b = append(b, line)
+	}
+	b[1] = "changed-early"
+	b[18] = "changed-late"
+
+	got := Unified("a/f", "b/f", a, b, DefaultContext)
+	if n := strings.Count(got, "@@ -"); n != 2 {
+		t.Fatalf("expected 2 hunks, got %d:\n%s", n, got)
+	}
+	if Unified("a/f", "b/f", a, a, DefaultContext) != "" {
+		t.Fatal("equal inputs should produce an empty diff")
+	}
+}
+
+func TestUnified_NewFile(t *testing.T) {
+	got := Unified("/dev/null", "b/f", nil, []string{"hello"}, DefaultContext)
+	if !strings.Contains(got, "@@ -0,0 +1 @@\n+hello\n") {
+		t.Fatalf("unexpected new-file diff:\n%s", got)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	local := t.TempDir()
+	remote := t.TempDir()
+
+	writeFile(t, filepath.Join(local, "SKILL.md"), "# pdf\nold line\n")
+	writeFile(t, filepath.Join(remote, "SKILL.md"), "# pdf\nnew line\n")
+	writeFile(t, filepath.Join(local, "same.txt"), "same\n")
+	writeFile(t, filepath.Join(remote, "same.txt"), "same\n")
+	writeFile(t, filepath.Join(local, "old.txt"), "bye\n")
+	writeFile(t, filepath.Join(remote, "scripts", "new.py"), "print('hi')\n")
+	writeFile(t, filepath.Join(remote, "logo.png"), "\x89PNG\x00\x01")
+	writeFile(t, filepath.Join(local, ".skills-x-meta.json"), "{}")
+
+	changes, err := Compare(local, remote)
+	if err != nil {
+		t.Fatalf("Compare: %v", err)
+	}
+
+	got := make(map[string]FileChange)
+	var order []string
+	for _, c := range changes {
+		got[c.Path] = c
+		order = append(order, c.Status.Symbol()+" "+c.Path)
+	}
+	// Sorted by path
+	want := []string{"M SKILL.md", "A logo.png", "D old.txt", "A scripts/new.py"}
+	if strings.Join(order, ",") != strings.Join(want, ",") {
+		t.Fatalf("changes = %v; want %v", order, want)
+	}
+
+	if !got["logo.png"].Binary || got["logo.png"].Diff != "" {
+		t.Errorf("logo.png should be binary without a diff: %+v", got["logo.png"])
+	}
+	if d := got["SKILL.md"].Diff; !strings.Contains(d, "-old line\n+new line\n") {
+		t.Errorf("SKILL.md diff missing change:\n%s", d)
+	}
+	if d := got["old.txt"].Diff; !strings.Contains(d, "+++ /dev/null") {
+		t.Errorf("removed file should diff against /dev/null:\n%s", d)
+	}
+}
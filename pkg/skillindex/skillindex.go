@@ -0,0 +1,181 @@
+// Package skillindex keeps a list of a project's installed skills in its
+// AGENTS.md or CLAUDE.md, for agents that do not discover skill folders on
+// their own.
+//
+// The list lives in a block between two markers; the rest of the file is
+// never touched, and writing the same skills again leaves the file as it
+// is.
+package skillindex
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/castle-x/skills-x/pkg/adapter"
+	"github.com/castle-x/skills-x/pkg/products"
+)
+
+// AutoEnv names the files to refresh after every install, update or
+// uninstall, comma-separated (e.g. "AGENTS.md,CLAUDE.md"); unset turns
+// the refresh off
+const AutoEnv = "SKILLS_X_INDEX"
+
+// DefaultFile is the file index writes when none is given
+const DefaultFile = "AGENTS.md"
+
+const (
+	beginMarker = "<!-- skills-x:index:begin -->"
+	endMarker   = "<!-- skills-x:index:end -->"
+)
+
+// AutoFiles returns the files named by SKILLS_X_INDEX
+func AutoFiles() []string {
+	var files []string
+	for _, f := range strings.Split(os.Getenv(AutoEnv), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// Entry is one installed skill
+type Entry struct {
+	Name        string
+	Description string
+	Path        string // Skill directory, relative to the project
+}
+
+// Collect lists the skills installed in the project skills directories of
+// every product under projectDir, sorted by name. A skill installed for
+// several products is listed once, at its first directory. A skill whose
+// SKILL.md cannot be read is left out; the returned warnings name each one.
+func Collect(projectDir string) ([]Entry, []string, error) {
+	var entries []Entry
+	var warnings []string
+	seenDir := make(map[string]bool)
+	seenSkill := make(map[string]bool)
+	for _, p := range products.All() {
+		dir := p.Dir(products.ScopeProject, projectDir)
+		if seenDir[dir] {
+			continue
+		}
+		seenDir[dir] = true
+
+		dirents, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, d := range dirents {
+			skillDir := filepath.Join(dir, d.Name())
+			if !d.IsDir() || seenSkill[d.Name()] {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(skillDir, "SKILL.md")); err != nil {
+				continue
+			}
+			s, err := adapter.Load(skillDir)
+			if err != nil {
+				warnings = append(warnings, err.Error())
+				continue
+			}
+			rel, err := filepath.Rel(projectDir, skillDir)
+			if err != nil {
+				rel = skillDir
+			}
+			seenSkill[d.Name()] = true
+			entries = append(entries, Entry{
+				Name:        s.Name,
+				Description: strings.Join(strings.Fields(s.Description), " "),
+				Path:        filepath.ToSlash(rel),
+			})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].Name) < strings.ToLower(entries[j].Name)
+	})
+	return entries, warnings, nil
+}
+
+// Render returns the managed block listing entries, markers included
+func Render(entries []Entry) string {
+	var b strings.Builder
+	b.WriteString(beginMarker + "\n")
+	b.WriteString("<!-- Generated by skills-x index; changes inside this block are overwritten -->\n")
+	b.WriteString("## Skills\n\n")
+	b.WriteString("The skills below are installed in this project. When a task matches a skill's description, read the SKILL.md in its folder and follow it.\n\n")
+	for _, e := range entries {
+		fmt.Fprintf(&b, "- **%s** (`%s`)", e.Name, e.Path)
+		if e.Description != "" {
+			b.WriteString(": " + e.Description)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(endMarker)
+	return b.String()
+}
+
+// Update writes the block listing entries into the file at path, replacing
+// the block already there or appending one. With no entries the block is
+// removed, and the file too when nothing else is left in it. It reports
+// whether the file changed.
+func Update(path string, entries []Entry) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	old := string(data)
+
+	block := ""
+	if len(entries) > 0 {
+		block = Render(entries)
+	}
+	text, found := replaceBlock(old, block)
+	switch {
+	case !found && block == "":
+		return false, nil
+	case !found:
+		text = strings.TrimRight(old, "\n")
+		if text != "" {
+			text += "\n\n"
+		}
+		text += block + "\n"
+	case block == "":
+		// Collapse the blank lines the block leaves behind
+		for strings.Contains(text, "\n\n\n") {
+			text = strings.ReplaceAll(text, "\n\n\n", "\n\n")
+		}
+		if strings.TrimSpace(text) == "" {
+			return true, os.Remove(path)
+		}
+		text = strings.Trim(text, "\n") + "\n"
+	}
+
+	if text == old {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, []byte(text), 0644)
+}
+
+// replaceBlock replaces the managed block of text, markers included
+func replaceBlock(text string, block string) (string, bool) {
+	start := strings.Index(text, beginMarker)
+	if start < 0 {
+		return text, false
+	}
+	end := strings.Index(text[start:], endMarker)
+	if end < 0 {
+		return text, false
+	}
+	end += start + len(endMarker)
+	return text[:start] + block + text[end:], true
+}
@@ -0,0 +1,103 @@
+package skillindex
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	project := t.TempDir()
+	writeFile(t, filepath.Join(project, ".claude", "skills", "pdf", "SKILL.md"), "---\nname: pdf\ndescription: >\n  Read and\n  fill PDFs\n---\n")
+	writeFile(t, filepath.Join(project, ".cursor", "skills", "pdf", "SKILL.md"), "---\nname: pdf\n---\n")
+	writeFile(t, filepath.Join(project, ".cursor", "skills", "docx", "SKILL.md"), "---\nname: docx\ndescription: Word files\n---\n")
+	writeFile(t, filepath.Join(project, ".cursor", "skills", "notes", "README.md"), "not a skill\n")
+	writeFile(t, filepath.Join(project, ".cursor", "skills", "broken", "SKILL.md"), "---\nname: [broken\n---\n")
+
+	entries, warnings, err := Collect(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "broken") {
+		t.Errorf("warnings = %q; want one for the broken skill", warnings)
+	}
+	want := []Entry{
+		{Name: "docx", Description: "Word files", Path: ".cursor/skills/docx"},
+		{Name: "pdf", Description: "Read and fill PDFs", Path: ".claude/skills/pdf"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("Collect = %+v; want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v; want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "CLAUDE.md")
+	user := "# Project\n\nUse tabs.\n"
+	writeFile(t, path, user)
+	entries := []Entry{{Name: "pdf", Description: "Read PDFs", Path: ".claude/skills/pdf"}}
+
+	changed, err := Update(path, entries)
+	if err != nil || !changed {
+		t.Fatalf("Update = %v, %v; want a change", changed, err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), user+"\n"+beginMarker) || !strings.Contains(string(data), "- **pdf** (`.claude/skills/pdf`): Read PDFs\n") {
+		t.Errorf("CLAUDE.md =\n%s", data)
+	}
+
+	// Same skills again: nothing to do
+	if changed, err := Update(path, entries); err != nil || changed {
+		t.Errorf("second Update = %v, %v; want no change", changed, err)
+	}
+
+	// Text after the block survives a refresh
+	writeFile(t, path, string(data)+"\nFooter.\n")
+	entries = append(entries, Entry{Name: "docx", Path: ".claude/skills/docx"})
+	if _, err := Update(path, entries); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Count(string(data), beginMarker) != 1 || !strings.Contains(string(data), "- **docx** (`.claude/skills/docx`)\n") || !strings.HasSuffix(string(data), "\nFooter.\n") {
+		t.Errorf("refreshed CLAUDE.md =\n%s", data)
+	}
+
+	// No skills left: the block goes, the user's text stays
+	if _, err := Update(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ = os.ReadFile(path); string(data) != user+"\nFooter.\n" {
+		t.Errorf("CLAUDE.md without skills = %q", data)
+	}
+}
+
+func TestUpdate_OwnFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "AGENTS.md")
+	if changed, err := Update(path, nil); err != nil || changed {
+		t.Errorf("Update(no skills, no file) = %v, %v", changed, err)
+	}
+	if _, err := Update(path, []Entry{{Name: "pdf", Path: ".claude/skills/pdf"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Update(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("AGENTS.md holding only the index was not removed")
+	}
+}
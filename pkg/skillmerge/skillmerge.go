@@ -0,0 +1,188 @@
+// Package skillmerge replaces an installed skill with a new upstream copy
+// while protecting files the user edited after installation.
+package skillmerge
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/castle-x/skills-x/pkg/fsutil"
+	"github.com/castle-x/skills-x/pkg/skilldiff"
+)
+
+// Policy decides what happens to locally modified files on update
+type Policy string
+
+const (
+	Refuse       Policy = "refuse"        // Leave the skill untouched and report the modified files
+	KeepLocal    Policy = "keep-local"    // Install upstream but keep every locally modified file
+	TakeUpstream Policy = "take-upstream" // Overwrite local modifications
+	Merge        Policy = "merge"         // Three-way merge against the installed commit
+)
+
+// Policies lists the accepted policy names, in help order
+var Policies = []Policy{Refuse, KeepLocal, TakeUpstream, Merge}
+
+// ErrLocalChanges is returned by Apply under Refuse when files were modified
+var ErrLocalChanges = errors.New("skill has local modifications")
+
+// ParsePolicy validates a policy name; an empty name means Refuse
+func ParsePolicy(s string) (Policy, error) {
+	if s == "" {
+		return Refuse, nil
+	}
+	for _, p := range Policies {
+		if string(p) == s {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("invalid conflict policy %q (want refuse, keep-local, take-upstream or merge)", s)
+}
+
+// Options configures Apply
+type Options struct {
+	Policy Policy
+	// Baseline maps relative paths to the hashes recorded at install time.
+	// Without a baseline local modifications cannot be detected and the
+	// skill is simply replaced.
+	Baseline map[string]string
+	// Base returns a file as it was at the installed commit; used by Merge
+	Base func(path string) ([]byte, error)
+}
+
+// Result reports what Apply found and did. Paths are slash-separated and
+// relative to the skill directory.
+type Result struct {
+	Modified []string // Files changed, added or deleted locally since install
+	Kept     []string // Local versions kept as they were; every one under KeepLocal
+	Merged   []string // Files merged without conflicts
+	// Conflicts are files Merge could not merge: written with conflict
+	// markers, or kept local when binary or deleted upstream
+	Conflicts []string
+}
+
+// LocalChanges lists the files in dir that differ from the install baseline,
+// including files added or deleted since. It returns nil without a baseline.
+func LocalChanges(dir string, baseline map[string]string) ([]string, error) {
+	if baseline == nil {
+		return nil, nil
+	}
+	current, err := skilldiff.HashFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var modified []string
+	for p, h := range current {
+		if baseline[p] != h {
+			modified = append(modified, p)
+		}
+	}
+	for p := range baseline {
+		if _, ok := current[p]; !ok {
+			modified = append(modified, p)
+		}
+	}
+	sort.Strings(modified)
+	return modified, nil
+}
+
+// Apply writes the updated skill to outDir: the contents of upstreamDir,
+// with the files modified in the installed copy localDir handled according
+// to the policy. localDir is only read, so outDir can be a staging directory
+// that is swapped in afterwards. Under Refuse a skill with local
+// modifications is not written and ErrLocalChanges is returned together
+// with the modified files.
+func Apply(localDir string, upstreamDir string, outDir string, opts Options) (*Result, error) {
+	modified, err := LocalChanges(localDir, opts.Baseline)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Modified: modified}
+
+	if len(modified) > 0 && opts.Policy == Refuse {
+		return res, ErrLocalChanges
+	}
+
+	// Read local versions first, in case outDir is localDir
+	local := make(map[string][]byte)
+	if opts.Policy == KeepLocal || opts.Policy == Merge {
+		for _, p := range modified {
+			data, err := os.ReadFile(filepath.Join(localDir, filepath.FromSlash(p)))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			local[p] = data
+		}
+	}
+
+	if err := fsutil.CopyDir(upstreamDir, outDir); err != nil {
+		return nil, err
+	}
+	if opts.Policy != KeepLocal && opts.Policy != Merge {
+		return res, nil
+	}
+
+	for _, p := range modified {
+		dst := filepath.Join(outDir, filepath.FromSlash(p))
+		data, existsLocally := local[p]
+		upstream, err := os.ReadFile(dst)
+		existsUpstream := err == nil
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		baseHash, inBaseline := opts.Baseline[p]
+		upstreamChanged := existsUpstream
+		if inBaseline {
+			upstreamChanged = !existsUpstream || skilldiff.HashBytes(upstream) != baseHash
+		}
+
+		if !existsLocally {
+			// Deleted locally: stay deleted unless upstream changed the file
+			if opts.Policy == KeepLocal || !upstreamChanged {
+				os.Remove(dst)
+				res.Kept = append(res.Kept, p)
+			} else if existsUpstream {
+				res.Conflicts = append(res.Conflicts, p)
+			}
+			continue
+		}
+
+		content := data
+		switch {
+		case opts.Policy == KeepLocal || !upstreamChanged:
+			res.Kept = append(res.Kept, p)
+		case !existsUpstream || skilldiff.IsBinary(data) || skilldiff.IsBinary(upstream):
+			// Deleted upstream, or binary on either side: keep the local copy
+			res.Conflicts = append(res.Conflicts, p)
+		default:
+			var base []byte
+			if inBaseline && opts.Base != nil {
+				if base, err = opts.Base(p); err != nil {
+					base = nil
+				}
+			}
+			merged, conflict := skilldiff.Merge3(string(base), string(data), string(upstream))
+			content = []byte(merged)
+			if conflict {
+				res.Conflicts = append(res.Conflicts, p)
+			} else {
+				res.Merged = append(res.Merged, p)
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(dst, content, 0644); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
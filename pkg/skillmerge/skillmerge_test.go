@@ -0,0 +1,152 @@
+package skillmerge
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/skilldiff"
+)
+
+const baseSkill = "# pdf\none\ntwo\nthree\n"
+
+// setup installs baseSkill, records its hashes and writes an upstream copy
+// that appended a line. It returns the installed dir, upstream dir and options.
+func setup(t *testing.T, policy Policy) (string, string, Options) {
+	t.Helper()
+	dst := filepath.Join(t.TempDir(), "pdf")
+	up := t.TempDir()
+	write(t, filepath.Join(dst, "SKILL.md"), baseSkill)
+	write(t, filepath.Join(dst, "notes.txt"), "keep me\n")
+	write(t, filepath.Join(up, "SKILL.md"), baseSkill+"four\n")
+	write(t, filepath.Join(up, "notes.txt"), "keep me\n")
+
+	baseline, err := skilldiff.HashFiles(dst)
+	if err != nil {
+		t.Fatalf("HashFiles: %v", err)
+	}
+	return dst, up, Options{
+		Policy:   policy,
+		Baseline: baseline,
+		Base: func(p string) ([]byte, error) {
+			if p == "SKILL.md" {
+				return []byte(baseSkill), nil
+			}
+			return []byte("keep me\n"), nil
+		},
+	}
+}
+
+func write(t *testing.T, path string, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func read(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return string(data)
+}
+
+func TestApply_UnmodifiedIsReplaced(t *testing.T) {
+	dst, up, opts := setup(t, Refuse)
+	res, err := Apply(dst, up, dst, opts)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if len(res.Modified) != 0 || !strings.HasSuffix(read(t, filepath.Join(dst, "SKILL.md")), "four\n") {
+		t.Fatalf("clean skill should take upstream: %+v", res)
+	}
+}
+
+func TestApply_RefuseLeavesSkillUntouched(t *testing.T) {
+	dst, up, opts := setup(t, Refuse)
+	write(t, filepath.Join(dst, "SKILL.md"), "# pdf\nONE\ntwo\nthree\n")
+
+	res, err := Apply(dst, up, dst, opts)
+	if !errors.Is(err, ErrLocalChanges) {
+		t.Fatalf("err = %v; want ErrLocalChanges", err)
+	}
+	if !reflect.DeepEqual(res.Modified, []string{"SKILL.md"}) {
+		t.Fatalf("Modified = %v", res.Modified)
+	}
+	if got := read(t, filepath.Join(dst, "SKILL.md")); got != "# pdf\nONE\ntwo\nthree\n" {
+		t.Fatalf("local file changed: %q", got)
+	}
+}
+
+func TestApply_Policies(t *testing.T) {
+	tests := []struct {
+		policy    Policy
+		want      string
+		merged    int
+		conflicts int
+	}{
+		{KeepLocal, "# pdf\nONE\ntwo\nthree\n", 0, 0},
+		{TakeUpstream, baseSkill + "four\n", 0, 0},
+		{Merge, "# pdf\nONE\ntwo\nthree\nfour\n", 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			dst, up, opts := setup(t, tt.policy)
+			write(t, filepath.Join(dst, "SKILL.md"), "# pdf\nONE\ntwo\nthree\n")
+			write(t, filepath.Join(dst, "mine.md"), "local only\n")
+
+			res, err := Apply(dst, up, dst, opts)
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if got := read(t, filepath.Join(dst, "SKILL.md")); got != tt.want {
+				t.Fatalf("SKILL.md = %q; want %q", got, tt.want)
+			}
+			if len(res.Merged) != tt.merged || len(res.Conflicts) != tt.conflicts {
+				t.Fatalf("result = %+v", res)
+			}
+			_, err = os.Stat(filepath.Join(dst, "mine.md"))
+			if tt.policy == TakeUpstream {
+				if !os.IsNotExist(err) {
+					t.Fatalf("take-upstream should drop local-only files, stat err = %v", err)
+				}
+			} else if err != nil {
+				t.Fatalf("local-only file should be kept: %v", err)
+			}
+		})
+	}
+}
+
+func TestApply_MergeConflictWritesMarkers(t *testing.T) {
+	dst, up, opts := setup(t, Merge)
+	write(t, filepath.Join(dst, "SKILL.md"), baseSkill+"local four\n")
+
+	res, err := Apply(dst, up, dst, opts)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if !reflect.DeepEqual(res.Conflicts, []string{"SKILL.md"}) {
+		t.Fatalf("Conflicts = %v", res.Conflicts)
+	}
+	got := read(t, filepath.Join(dst, "SKILL.md"))
+	if !strings.Contains(got, skilldiff.MarkerLocal+"\nlocal four\n"+skilldiff.MarkerSep+"\nfour\n"+skilldiff.MarkerUpstream) {
+		t.Fatalf("missing conflict markers:\n%s", got)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	if p, err := ParsePolicy("merge"); err != nil || p != Merge {
+		t.Fatalf("ParsePolicy(merge) = %v, %v", p, err)
+	}
+	if _, err := ParsePolicy("theirs"); err == nil {
+		t.Fatal("expected an error for an unknown policy")
+	}
+}
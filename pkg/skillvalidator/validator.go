@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/castle-x/skills-x/pkg/githost"
 	"github.com/castle-x/skills-x/pkg/gitutil"
 	"gopkg.in/yaml.v3"
 )
@@ -19,6 +20,7 @@ type SourceType string
 
 const (
 	SourceTypeGitHub SourceType = "github"
+	SourceTypeGit    SourceType = "git" // Any other git host
 	SourceTypeLocal  SourceType = "local"
 )
 
@@ -26,6 +28,8 @@ const (
 type ValidateRequest struct {
 	// Repo is one of:
 	//   - "github.com/owner/repo"  → GitHub sparse clone
+	//   - "https://host/path"      → clone from any other git host
+	//   - "git@host:org/repo.git"  → SSH clone (also ssh://)
 	//   - "/abs/path"              → local absolute path to skill dir
 	//   - "./rel/path"             → local relative path (resolved against cwd)
 	Repo string
@@ -72,7 +76,7 @@ const (
 // ParsedInput is the result of ParseInput.
 type ParsedInput struct {
 	Kind      InputKind
-	Repo      string // "github.com/owner/repo"
+	Repo      string // "github.com/owner/repo", a full URL on other hosts, or an SSH URL as given
 	SkillHint string // skill name to search for (empty in RepoScan mode)
 	// For local paths, Repo is the path itself and SkillHint is empty.
 }
@@ -81,30 +85,36 @@ type ParsedInput struct {
 //
 // Supported formats:
 //
-//	owner/repo               → RepoScan
+//	owner/repo               → RepoScan on GitHub
 //	owner/repo/skill-name    → SingleSkill (search by name in repo)
 //	github.com/owner/repo    → RepoScan (compat)
 //	github.com/owner/repo/x  → SingleSkill (compat)
-//	https://github.com/...   → strip prefix, then parse
+//	https://host/path...     → any host githost understands; a browser URL
+//	                           into the file tree is a SingleSkill
+//	git@host:org/repo.git    → RepoScan over SSH (also ssh://)
 //	/abs/path                → Local
 //	./rel/path               → Local
 //	~/path                   → Local
 func ParseInput(input string) ParsedInput {
 	s := strings.TrimSpace(input)
 
-	// Strip URL prefixes.
-	s = strings.TrimPrefix(s, "https://")
-	s = strings.TrimPrefix(s, "http://")
-
 	// Local paths.
 	if strings.HasPrefix(s, "/") || strings.HasPrefix(s, "./") || strings.HasPrefix(s, "~/") {
 		return ParsedInput{Kind: InputKindLocal, Repo: s}
 	}
 
-	// Strip "github.com/" if already present.
-	withoutGH := strings.TrimPrefix(s, "github.com/")
+	// URLs and host/path on any host, including browser URLs.
+	if loc, ok := githost.Parse(s); ok {
+		skillHint := strings.TrimSuffix(loc.Dir, "SKILL.md")
+		skillHint = strings.TrimRight(skillHint, "/")
+		if skillHint == "" {
+			return ParsedInput{Kind: InputKindRepoScan, Repo: loc.Canonical()}
+		}
+		return ParsedInput{Kind: InputKindSingleSkill, Repo: loc.Canonical(), SkillHint: skillHint}
+	}
 
-	parts := strings.SplitN(withoutGH, "/", 3)
+	// owner/repo shorthand for GitHub.
+	parts := strings.SplitN(s, "/", 3)
 	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
 		// Fallback: treat as local path.
 		return ParsedInput{Kind: InputKindLocal, Repo: s}
@@ -112,19 +122,11 @@ func ParseInput(input string) ParsedInput {
 
 	repo := "github.com/" + parts[0] + "/" + parts[1]
 
-	if len(parts) == 2 {
-		return ParsedInput{Kind: InputKindRepoScan, Repo: repo}
-	}
-
 	// 3+ segments → the remainder is the skill hint.
-	skillHint := parts[2]
-	// Remove leading "tree/main/" or "tree/master/" from GitHub URLs.
-	for _, prefix := range []string{"tree/main/", "tree/master/", "blob/main/", "blob/master/"} {
-		skillHint = strings.TrimPrefix(skillHint, prefix)
+	skillHint := ""
+	if len(parts) == 3 {
+		skillHint = strings.TrimRight(parts[2], "/")
 	}
-	// Remove trailing slashes.
-	skillHint = strings.TrimRight(skillHint, "/")
-
 	if skillHint == "" {
 		return ParsedInput{Kind: InputKindRepoScan, Repo: repo}
 	}
@@ -144,8 +146,7 @@ type DiscoveredSkill struct {
 // Discover clones a GitHub repo and finds all directories containing SKILL.md.
 // Returns the list of skills found, sorted by path.
 func Discover(repo string) ([]DiscoveredSkill, error) {
-	gitURL := "https://" + repo + ".git"
-	repoName := strings.TrimPrefix(repo, "github.com/")
+	gitURL, repoName := remoteURL(repo)
 
 	cloneResult, err := gitutil.CloneRepo(gitURL, repoName, "")
 	if err != nil {
@@ -203,8 +204,7 @@ func Discover(repo string) ([]DiscoveredSkill, error) {
 // It tries common locations first, then falls back to a full walk.
 // Returns the discovered skill and its relative path, or nil if not found.
 func FindSkill(repo, skillHint string) (*DiscoveredSkill, error) {
-	gitURL := "https://" + repo + ".git"
-	repoName := strings.TrimPrefix(repo, "github.com/")
+	gitURL, repoName := remoteURL(repo)
 
 	cloneResult, err := gitutil.CloneRepo(gitURL, repoName, "")
 	if err != nil {
@@ -363,18 +363,32 @@ func Validate(req ValidateRequest) (*ValidateResult, error) {
 func resolve(req ValidateRequest) (string, SourceType, error) {
 	repo := strings.TrimSpace(req.Repo)
 
-	// GitHub
-	if strings.HasPrefix(repo, "github.com/") {
-		return resolveGitHub(repo, req.Path)
+	// A repository on any git host
+	if _, ok := githost.Parse(repo); ok {
+		return resolveRemote(repo, req.Path)
 	}
 
 	// Local path (absolute, relative, or ~/)
 	return resolveLocal(repo, req.Path)
 }
 
-func resolveGitHub(repo, skillPath string) (string, SourceType, error) {
-	gitURL := "https://" + repo + ".git"
-	repoName := strings.TrimPrefix(repo, "github.com/")
+// remoteURL returns the clone URL of a remote repo and the name its
+// checkout is cached under
+func remoteURL(repo string) (gitURL, repoName string) {
+	loc, ok := githost.Parse(repo)
+	if !ok {
+		// owner/repo shorthand
+		return "https://github.com/" + repo + ".git", repo
+	}
+	return loc.CloneURL(), loc.ShortName()
+}
+
+func resolveRemote(repo, skillPath string) (string, SourceType, error) {
+	gitURL, repoName := remoteURL(repo)
+	sourceType := SourceTypeGit
+	if loc, _ := githost.Parse(repo); loc.Kind == githost.GitHub {
+		sourceType = SourceTypeGitHub
+	}
 
 	var cloneResult *gitutil.CloneResult
 	var err error
@@ -390,7 +404,7 @@ func resolveGitHub(repo, skillPath string) (string, SourceType, error) {
 		cloneResult, err = gitutil.CloneRepo(gitURL, repoName, "")
 	}
 	if err != nil {
-		return "", SourceTypeGitHub, fmt.Errorf("failed to clone %s: %w", repo, err)
+		return "", sourceType, fmt.Errorf("failed to clone %s: %w", repo, err)
 	}
 
 	skillDir := cloneResult.TempDir
@@ -398,11 +412,11 @@ func resolveGitHub(repo, skillPath string) (string, SourceType, error) {
 		skillDir = filepath.Join(cloneResult.TempDir, normalizedPath)
 	}
 	if _, err := os.Stat(skillDir); os.IsNotExist(err) {
-		return "", SourceTypeGitHub,
+		return "", sourceType,
 			fmt.Errorf("skill path %q not found in repository %s", skillPath, repo)
 	}
 
-	return skillDir, SourceTypeGitHub, nil
+	return skillDir, sourceType, nil
 }
 
 func resolveLocal(rawPath, subPath string) (string, SourceType, error) {
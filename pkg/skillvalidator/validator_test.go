@@ -31,6 +31,45 @@ func TestParseInput(t *testing.T) {
 			wantRepo: "github.com/affaan-m/everything-claude-code",
 			wantHint: "golang-testing",
 		},
+		{
+			name:     "GitLab browser URL with nested groups",
+			input:    "https://gitlab.com/acme/platform/skills/-/tree/main/skills/pdf",
+			wantKind: InputKindSingleSkill,
+			wantRepo: "https://gitlab.com/acme/platform/skills",
+			wantHint: "skills/pdf",
+		},
+		{
+			name:     "Gitea browser URL",
+			input:    "https://codeberg.org/acme/skills/src/branch/main/pdf",
+			wantKind: InputKindSingleSkill,
+			wantRepo: "https://codeberg.org/acme/skills",
+			wantHint: "pdf",
+		},
+		{
+			name:     "Bitbucket browser URL to SKILL.md",
+			input:    "https://bitbucket.org/acme/skills/src/main/pdf/SKILL.md",
+			wantKind: InputKindSingleSkill,
+			wantRepo: "https://bitbucket.org/acme/skills",
+			wantHint: "pdf",
+		},
+		{
+			name:     "self-hosted clone URL → repo scan",
+			input:    "https://git.example.com/scm/team/skills.git",
+			wantKind: InputKindRepoScan,
+			wantRepo: "https://git.example.com/scm/team/skills.git",
+		},
+		{
+			name:     "scp-like SSH URL → repo scan as given",
+			input:    "git@git.example.com:team/private-skills.git",
+			wantKind: InputKindRepoScan,
+			wantRepo: "git@git.example.com:team/private-skills.git",
+		},
+		{
+			name:     "ssh:// URL → repo scan as given",
+			input:    "ssh://git@git.example.com:2222/team/private-skills.git/",
+			wantKind: InputKindRepoScan,
+			wantRepo: "ssh://git@git.example.com:2222/team/private-skills.git",
+		},
 		{
 			name:     "github.com prefix compat",
 			input:    "github.com/affaan-m/everything-claude-code",
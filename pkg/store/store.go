@@ -0,0 +1,452 @@
+// Package store keeps one shared copy of each installed skill.
+//
+// Entries live in ~/.local/share/skills-x/store/<sha> (under
+// $XDG_DATA_HOME when set), keyed by a hash of the skill's file contents.
+// With a link mode, the files of an installed skill are symlinks or hard
+// links to its entry, so every product directory holding the same version
+// of a skill shares one copy instead of drifting apart. The skill directory
+// itself stays a real directory: its .skills-x-meta.json and the backups
+// kept for rollback belong to that one install.
+//
+// Entry files are read-only, so an edit through a link fails rather than
+// reaching every install sharing the file. An editor that saves by
+// replacing the file gives that install its own copy. A file written in
+// place anyway (after a chmod, or by an editor keeping hard links) changes
+// the entry and every install linked to it; update reports the change as a
+// local modification in each of them. Put checks an entry against its hash
+// before reusing it and restores changed files, so new installs get the
+// original content.
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/castle-x/skills-x/pkg/fsutil"
+	"github.com/castle-x/skills-x/pkg/installtx"
+	"github.com/castle-x/skills-x/pkg/skilldiff"
+)
+
+// Mode is how an installed skill's files refer to the store
+type Mode string
+
+const (
+	Copy     Mode = "copy"     // Plain copies; the store is not used
+	Symlink  Mode = "symlink"  // Symbolic links to the entry's files
+	Hardlink Mode = "hardlink" // Hard links to the entry's files
+)
+
+// ModeEnv sets the default mode for every command
+const ModeEnv = "SKILLS_X_LINK"
+
+// refsDirName holds, per entry, the install directories linked to it
+const refsDirName = ".refs"
+
+// tmpPrefix marks entries still being written
+const tmpPrefix = ".tmp-"
+
+// ParseMode reads a --link value; "" means Copy
+func ParseMode(s string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(s))) {
+	case "", Copy:
+		return Copy, nil
+	case Symlink:
+		return Symlink, nil
+	case Hardlink:
+		return Hardlink, nil
+	}
+	return "", fmt.Errorf("unknown link mode %q (want copy, symlink or hardlink)", s)
+}
+
+// DefaultMode returns the mode set by SKILLS_X_LINK, or Copy
+func DefaultMode() Mode {
+	mode, err := ParseMode(os.Getenv(ModeEnv))
+	if err != nil {
+		return Copy
+	}
+	return mode
+}
+
+// Dir returns the store's root directory
+func Dir() string {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = os.Getenv("HOME")
+		}
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataDir, "skills-x", "store")
+}
+
+// Hash returns the content hash of the skill in dir: a SHA-256 over its
+// sorted file paths and file hashes, skills-x bookkeeping excluded
+func Hash(dir string) (string, error) {
+	files, err := skilldiff.HashFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	h := sha256.New()
+	for _, p := range paths {
+		fmt.Fprintf(h, "%s\x00%s\n", p, files[p])
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Put adds the skill in dir to the store, unless an entry with the same
+// content is there already, and returns the entry's hash and directory
+func Put(dir string) (hash string, entry string, err error) {
+	hash, err = Hash(dir)
+	if err != nil {
+		return "", "", err
+	}
+	root := Dir()
+	entry = filepath.Join(root, hash)
+	if fsutil.DirExists(entry) {
+		if err := repair(entry, dir); err != nil {
+			return "", "", err
+		}
+		return hash, entry, nil
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return "", "", err
+	}
+
+	tmp, err := os.MkdirTemp(root, tmpPrefix)
+	if err != nil {
+		return "", "", err
+	}
+	files, err := skilldiff.HashFiles(dir)
+	if err == nil {
+		for p := range files {
+			rel := filepath.FromSlash(p)
+			if err = fsutil.CopyFile(filepath.Join(dir, rel), filepath.Join(tmp, rel)); err != nil {
+				break
+			}
+			if err = os.Chmod(filepath.Join(tmp, rel), 0444); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		os.RemoveAll(tmp)
+		return "", "", err
+	}
+	if err := os.Rename(tmp, entry); err != nil {
+		os.RemoveAll(tmp)
+		// Another install stored the same content first
+		if fsutil.DirExists(entry) {
+			return hash, entry, nil
+		}
+		return "", "", err
+	}
+	return hash, entry, nil
+}
+
+// repair restores the files of entry that no longer match the skill in src,
+// which has the entry's hash. Restored files are new files renamed into
+// place, so an install hard-linked to an edited file keeps its edit as a
+// local modification instead of sharing the repaired copy.
+func repair(entry string, src string) error {
+	want, err := skilldiff.HashFiles(src)
+	if err != nil {
+		return err
+	}
+	got, err := skilldiff.HashFiles(entry)
+	if err != nil {
+		return err
+	}
+	for p, h := range want {
+		if got[p] == h {
+			continue
+		}
+		dst := filepath.Join(entry, filepath.FromSlash(p))
+		tmp := filepath.Join(filepath.Dir(dst), tmpPrefix+filepath.Base(dst))
+		if err := fsutil.CopyFile(filepath.Join(src, filepath.FromSlash(p)), tmp); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+		if err := os.Chmod(tmp, 0444); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+		if err := os.Rename(tmp, dst); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	for p := range got {
+		if _, ok := want[p]; !ok {
+			if err := os.Remove(filepath.Join(entry, filepath.FromSlash(p))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Install replaces dst with the skill in src. Under a link mode the files
+// are linked to the skill's store entry; see Share.
+func Install(src string, dst string, mode Mode) (Mode, error) {
+	if err := fsutil.CopyDir(src, dst); err != nil {
+		return Copy, err
+	}
+	return Share(dst, dst, mode)
+}
+
+// Share stores the skill in dir and replaces its files with links to the
+// entry. installDir is where dir ends up: the same path, or the final
+// location of a staging directory. It is recorded so that Prune knows the
+// entry is in use. When the filesystem refuses the link, for instance a
+// hard link across devices or a symlink on a filesystem without them, the
+// remaining files are left as copies and Copy is returned. Files already
+// linked by then hold the same content, so the install is complete either
+// way.
+func Share(dir string, installDir string, mode Mode) (Mode, error) {
+	if mode == Copy || mode == "" {
+		return Copy, nil
+	}
+	hash, entry, err := Put(dir)
+	if err != nil {
+		return Copy, err
+	}
+	if err := addRef(hash, installDir); err != nil {
+		return Copy, err
+	}
+
+	files, err := skilldiff.HashFiles(dir)
+	if err != nil {
+		return Copy, err
+	}
+	for p := range files {
+		rel := filepath.FromSlash(p)
+		if err := link(filepath.Join(entry, rel), filepath.Join(dir, rel), mode); err != nil {
+			return Copy, nil
+		}
+	}
+	return mode, nil
+}
+
+// link replaces dst with a link to target, leaving dst alone on failure
+func link(target string, dst string, mode Mode) error {
+	tmp := filepath.Join(filepath.Dir(dst), ".skills-x-link-"+filepath.Base(dst))
+	os.Remove(tmp)
+	var err error
+	if mode == Hardlink {
+		err = os.Link(target, tmp)
+	} else {
+		err = os.Symlink(target, tmp)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// refsPath returns the file listing the install directories of an entry
+func refsPath(hash string) string {
+	return filepath.Join(Dir(), refsDirName, hash)
+}
+
+// addRef records that installDir links to the entry hash
+func addRef(hash string, installDir string) error {
+	if abs, err := filepath.Abs(installDir); err == nil {
+		installDir = abs
+	}
+	if err := os.MkdirAll(filepath.Dir(refsPath(hash)), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(refsPath(hash), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(installDir + "\n")
+	return err
+}
+
+// readRefs returns the distinct install directories recorded for an entry
+func readRefs(hash string) []string {
+	data, err := os.ReadFile(refsPath(hash))
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var refs []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" && !seen[line] {
+			seen[line] = true
+			refs = append(refs, line)
+		}
+	}
+	return refs
+}
+
+// Entry is one skill in the store
+type Entry struct {
+	Hash  string
+	Path  string
+	Size  int64    // Total size of the entry's files
+	Users []string // Install directories (or their backups) linked to it
+}
+
+// List returns every entry with the installs still linked to it, sorted
+// by hash
+func List() ([]Entry, error) {
+	dirents, err := os.ReadDir(Dir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, d := range dirents {
+		if !d.IsDir() || strings.HasPrefix(d.Name(), ".") {
+			continue
+		}
+		e := Entry{Hash: d.Name(), Path: filepath.Join(Dir(), d.Name())}
+		e.Size = dirSize(e.Path)
+		for _, dir := range readRefs(e.Hash) {
+			for _, candidate := range []string{dir, installtx.BackupPath(dir)} {
+				if linksTo(candidate, e.Path) {
+					e.Users = append(e.Users, candidate)
+				}
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// linksTo reports whether any file of the skill in dir is a link to a file
+// of entry
+func linksTo(dir string, entry string) bool {
+	found := false
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || found || d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil
+		}
+		if d.Type()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(path)
+			found = err == nil && target == filepath.Join(entry, rel)
+			return nil
+		}
+		info, err1 := os.Stat(path)
+		stored, err2 := os.Stat(filepath.Join(entry, rel))
+		found = err1 == nil && err2 == nil && os.SameFile(info, stored)
+		return nil
+	})
+	return found
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// staleAfter is how old an unfinished entry must be before Prune removes
+// it, so a concurrent install is never disturbed
+const staleAfter = time.Hour
+
+// Prune removes the entries no install links to any more, along with
+// entries left unfinished by an interrupted install. With dryRun nothing
+// is deleted. It returns the removed entries.
+func Prune(dryRun bool) ([]Entry, error) {
+	entries, err := List()
+	if err != nil {
+		return nil, err
+	}
+	var removed []Entry
+	for _, e := range entries {
+		if len(e.Users) > 0 {
+			if !dryRun {
+				writeRefs(e)
+			}
+			continue
+		}
+		removed = append(removed, e)
+		if dryRun {
+			continue
+		}
+		if err := removeEntry(e.Path); err != nil {
+			return removed, err
+		}
+		os.Remove(refsPath(e.Hash))
+	}
+
+	dirents, _ := os.ReadDir(Dir())
+	for _, d := range dirents {
+		if !strings.HasPrefix(d.Name(), tmpPrefix) {
+			continue
+		}
+		info, err := d.Info()
+		if err != nil || time.Since(info.ModTime()) < staleAfter {
+			continue
+		}
+		e := Entry{Path: filepath.Join(Dir(), d.Name())}
+		e.Size = dirSize(e.Path)
+		removed = append(removed, e)
+		if !dryRun {
+			if err := removeEntry(e.Path); err != nil {
+				return removed, err
+			}
+		}
+	}
+	return removed, nil
+}
+
+// writeRefs rewrites an entry's refs file with the installs still linked,
+// dropping backups and installs that went away
+func writeRefs(e Entry) {
+	var b strings.Builder
+	for _, dir := range readRefs(e.Hash) {
+		for _, user := range e.Users {
+			if user == dir || user == installtx.BackupPath(dir) {
+				b.WriteString(dir + "\n")
+				break
+			}
+		}
+	}
+	_ = os.WriteFile(refsPath(e.Hash), []byte(b.String()), 0644)
+}
+
+// removeEntry deletes an entry; its read-only files need no chmod on
+// Unix, but directories may have been made read-only by hand
+func removeEntry(path string) error {
+	filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			os.Chmod(p, 0755)
+		}
+		return nil
+	})
+	return os.RemoveAll(path)
+}
@@ -0,0 +1,194 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/castle-x/skills-x/pkg/installtx"
+)
+
+func writeSkill(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for in, want := range map[string]Mode{"": Copy, "copy": Copy, "Symlink": Symlink, "hardlink": Hardlink} {
+		if got, err := ParseMode(in); err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseMode("reflink"); err == nil {
+		t.Error("ParseMode accepted an unknown mode")
+	}
+}
+
+func TestHash_IgnoresBookkeeping(t *testing.T) {
+	a, b := t.TempDir(), t.TempDir()
+	writeSkill(t, a, map[string]string{"SKILL.md": "# pdf\n", "scripts/run.sh": "echo\n"})
+	writeSkill(t, b, map[string]string{"SKILL.md": "# pdf\n", "scripts/run.sh": "echo\n", ".skills-x-meta.json": "{}"})
+	ha, _ := Hash(a)
+	hb, _ := Hash(b)
+	if ha != hb {
+		t.Errorf("meta file changed the hash: %s != %s", ha, hb)
+	}
+	writeSkill(t, b, map[string]string{"scripts/run.sh": "echo changed\n"})
+	if hb, _ = Hash(b); ha == hb {
+		t.Error("content change did not change the hash")
+	}
+}
+
+func TestInstall_SharesOneCopy(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	src := t.TempDir()
+	writeSkill(t, src, map[string]string{"SKILL.md": "# pdf\n", "ref/guide.md": "guide\n"})
+
+	for _, mode := range []Mode{Symlink, Hardlink} {
+		claude := filepath.Join(t.TempDir(), "pdf")
+		cursor := filepath.Join(t.TempDir(), "pdf")
+		for _, dst := range []string{claude, cursor} {
+			got, err := Install(src, dst, mode)
+			if err != nil || got != mode {
+				t.Fatalf("Install(%s) = %s, %v", mode, got, err)
+			}
+			if err := os.WriteFile(filepath.Join(dst, ".skills-x-meta.json"), []byte("{}"), 0644); err != nil {
+				t.Fatalf("meta file not writable in a linked install: %v", err)
+			}
+		}
+
+		a, _ := os.Stat(filepath.Join(claude, "ref", "guide.md"))
+		b, _ := os.Stat(filepath.Join(cursor, "ref", "guide.md"))
+		if !os.SameFile(a, b) {
+			t.Errorf("%s: installs do not share the stored file", mode)
+		}
+		info, _ := os.Lstat(filepath.Join(claude, "SKILL.md"))
+		if isLink := info.Mode()&os.ModeSymlink != 0; isLink != (mode == Symlink) {
+			t.Errorf("%s: SKILL.md mode = %v", mode, info.Mode())
+		}
+		if data, _ := os.ReadFile(filepath.Join(cursor, "SKILL.md")); string(data) != "# pdf\n" {
+			t.Errorf("%s: content = %q", mode, data)
+		}
+	}
+
+	entries, err := List()
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("List = %d entries, %v; want one shared entry", len(entries), err)
+	}
+	if len(entries[0].Users) != 4 {
+		t.Errorf("entry users = %v; want the four installs", entries[0].Users)
+	}
+}
+
+func TestPut_RepairsEditedEntry(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	src := t.TempDir()
+	writeSkill(t, src, map[string]string{"SKILL.md": "# pdf\n"})
+
+	edited := filepath.Join(t.TempDir(), "pdf")
+	if _, err := Install(src, edited, Hardlink); err != nil {
+		t.Fatal(err)
+	}
+	// An in-place write through the hard link reaches the entry
+	file := filepath.Join(edited, "SKILL.md")
+	if err := os.Chmod(file, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, []byte("# my notes\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fresh := filepath.Join(t.TempDir(), "pdf")
+	if _, err := Install(src, fresh, Hardlink); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(fresh, "SKILL.md")); string(data) != "# pdf\n" {
+		t.Errorf("new install got the edited content %q", data)
+	}
+	if data, _ := os.ReadFile(file); string(data) != "# my notes\n" {
+		t.Errorf("edited install lost its edit: %q", data)
+	}
+	hash, _ := Hash(src)
+	if got, _ := Hash(filepath.Join(Dir(), hash)); got != hash {
+		t.Error("entry content does not match its hash after repair")
+	}
+}
+
+func TestInstall_CopyLeavesStoreAlone(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	src := t.TempDir()
+	writeSkill(t, src, map[string]string{"SKILL.md": "# pdf\n"})
+	if mode, err := Install(src, filepath.Join(t.TempDir(), "pdf"), Copy); err != nil || mode != Copy {
+		t.Fatalf("Install = %s, %v", mode, err)
+	}
+	if _, err := os.Stat(Dir()); !os.IsNotExist(err) {
+		t.Error("copy mode created the store")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	target := t.TempDir()
+	v1, v2, other := t.TempDir(), t.TempDir(), t.TempDir()
+	writeSkill(t, v1, map[string]string{"SKILL.md": "v1\n"})
+	writeSkill(t, v2, map[string]string{"SKILL.md": "v2\n"})
+	writeSkill(t, other, map[string]string{"SKILL.md": "other\n"})
+
+	pdf := filepath.Join(target, "pdf")
+	docx := filepath.Join(target, "docx")
+	for _, step := range []struct {
+		src, dst string
+	}{{v1, pdf}, {other, docx}} {
+		if _, err := Install(step.src, step.dst, Symlink); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Updating pdf moves v1 to the backup; rollback still needs it
+	tx := installtx.New()
+	if err := tx.Install(pdf, func(stage string) error {
+		if _, err := Install(v2, stage, Copy); err != nil {
+			return err
+		}
+		_, err := Share(stage, pdf, Symlink)
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tx.Commit()
+	// Uninstalling docx leaves its entry unused
+	if err := os.RemoveAll(docx); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := Prune(true)
+	if err != nil || len(removed) != 1 {
+		t.Fatalf("Prune(dry run) = %d, %v; want 1", len(removed), err)
+	}
+	if entries, _ := List(); len(entries) != 3 {
+		t.Errorf("dry run removed entries: %d left", len(entries))
+	}
+
+	if removed, err = Prune(false); err != nil || len(removed) != 1 {
+		t.Fatalf("Prune = %d, %v; want 1", len(removed), err)
+	}
+	otherHash, _ := Hash(other)
+	if removed[0].Hash != otherHash {
+		t.Errorf("removed %s; want the uninstalled skill's entry", removed[0].Hash)
+	}
+	for _, dir := range []string{pdf, installtx.BackupPath(pdf)} {
+		if data, err := os.ReadFile(filepath.Join(dir, "SKILL.md")); err != nil {
+			t.Errorf("%s lost its entry: %v", dir, err)
+		} else if dir == pdf && string(data) != "v2\n" {
+			t.Errorf("pdf = %q; want v2", data)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/castle-x/skills-x/pkg/githost"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,6 +20,7 @@ import (
 type SkillEntry struct {
 	Name          string   `yaml:"name"`
 	Path          string   `yaml:"path"`
+	Ref           string   `yaml:"ref,omitempty"`
 	Tags          []string `yaml:"tags,omitempty"`
 	Description   string   `yaml:"description"`
 	DescriptionZh string   `yaml:"description_zh,omitempty"`
@@ -27,9 +29,12 @@ type SkillEntry struct {
 
 // SourceEntry is a source (repository or local dir) containing skills.
 type SourceEntry struct {
-	Repo    string       `yaml:"repo"`
-	License string       `yaml:"license,omitempty"`
-	Skills  []SkillEntry `yaml:"skills"`
+	Repo      string       `yaml:"repo"`
+	Ref       string       `yaml:"ref,omitempty"`
+	License   string       `yaml:"license,omitempty"`
+	Transport string       `yaml:"transport,omitempty"` // "archive" to download archives instead of cloning
+	Archive   string       `yaml:"archive,omitempty"`   // Archive URL template with {ref}
+	Skills    []SkillEntry `yaml:"skills"`
 }
 
 // UserRegistry holds the contents of user-registry.yaml.
@@ -232,12 +237,13 @@ func (ur *UserRegistry) TotalSkillCount() int {
 
 // deriveSourceName creates a short, filesystem-safe source key from a repo string.
 func deriveSourceName(repo string) string {
-	// github.com/owner/repo-name → owner-repo-name
-	if strings.HasPrefix(repo, "github.com/") {
-		parts := strings.SplitN(strings.TrimPrefix(repo, "github.com/"), "/", 2)
-		if len(parts) == 2 {
-			return strings.ReplaceAll(parts[0]+"-"+parts[1], "/", "-")
+	// github.com/owner/repo-name or git@github.com:owner/repo-name.git →
+	// owner-repo-name; other hosts keep the host name, without the scheme
+	if loc, ok := githost.Parse(repo); ok {
+		if loc.Kind == githost.GitHub {
+			return strings.ReplaceAll(loc.Path, "/", "-")
 		}
+		repo = loc.ShortName()
 	}
 	// local path → "local"
 	if strings.HasPrefix(repo, "/") || strings.HasPrefix(repo, "./") || strings.HasPrefix(repo, "~/") {
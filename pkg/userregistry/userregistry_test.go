@@ -382,6 +382,8 @@ func TestDeriveSourceName(t *testing.T) {
 		{"./my-skills", "local"},
 		{"~/my-skills", "local"},
 		{"custom.gitlab.com/foo/bar", "custom-gitlab-com-foo-bar"},
+		{"https://gitlab.com/group/sub/bar.git", "gitlab-com-group-sub-bar"},
+		{"git@github.com:acme/private-skills.git", "acme-private-skills"},
 	}
 
 	for _, tt := range tests {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -39,6 +40,39 @@ func ShouldNotify(currentVersion, latestVersion string) bool {
 	return current != latest
 }
 
+// Running is the version of the running binary; main sets it from its
+// build info so that packages can check version requirements.
+var Running = "dev"
+
+// AtLeast reports whether current satisfies the minimum version min.
+// Development builds and empty requirements always satisfy it.
+func AtLeast(current, min string) bool {
+	c, m := NormalizeVersion(current), NormalizeVersion(min)
+	if m == "" || c == "" || c == "dev" || c == "unknown" {
+		return true
+	}
+	cp, mp := strings.Split(c, "."), strings.Split(m, ".")
+	for i := 0; i < len(cp) || i < len(mp); i++ {
+		cn, mn := versionPart(cp, i), versionPart(mp, i)
+		if cn != mn {
+			return cn > mn
+		}
+	}
+	return true
+}
+
+// versionPart returns the i-th numeric component, 0 when missing or invalid
+func versionPart(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, err := strconv.Atoi(parts[i])
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
 // LatestFromNpmJSON parses npm registry JSON and returns dist-tags.latest.
 func LatestFromNpmJSON(data []byte) (string, error) {
 	var payload struct {
@@ -43,3 +43,23 @@ func TestShouldNotify(t *testing.T) {
 		t.Fatal("did not expect prompt for dev version")
 	}
 }
+
+func TestAtLeast(t *testing.T) {
+	tests := []struct {
+		current, min string
+		want         bool
+	}{
+		{"0.2.10", "0.2.9", true},
+		{"0.2.9", "0.2.10", false},
+		{"v1.0.0", "1.0", true},
+		{"1.0", "1.0.1", false},
+		{"0.3.0-5-gabc1234", "0.3.0", true},
+		{"dev", "9.9.9", true},
+		{"0.1.0", "", true},
+	}
+	for _, tt := range tests {
+		if got := AtLeast(tt.current, tt.min); got != tt.want {
+			t.Errorf("AtLeast(%q, %q) = %v; want %v", tt.current, tt.min, got, tt.want)
+		}
+	}
+}
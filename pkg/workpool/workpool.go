@@ -0,0 +1,36 @@
+// Package workpool runs independent jobs on a bounded number of goroutines
+package workpool
+
+import "sync"
+
+// DefaultWorkers is the number of parallel jobs used when none is configured.
+// Jobs are mostly git clones, so this bounds concurrent network connections.
+const DefaultWorkers = 4
+
+// Run calls fn(i) for every i in [0, n) on at most workers goroutines and
+// returns when all calls have finished. Jobs are started in index order.
+func Run(n int, workers int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,41 @@
+package workpool
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun_BoundsConcurrencyAndRunsEveryJob(t *testing.T) {
+	var inFlight, peak int32
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+
+	Run(20, 3, func(i int) {
+		n := atomic.AddInt32(&inFlight, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+
+		mu.Lock()
+		seen[i] = true
+		mu.Unlock()
+	})
+
+	if len(seen) != 20 {
+		t.Fatalf("ran %d jobs; want 20", len(seen))
+	}
+	if peak > 3 {
+		t.Fatalf("peak concurrency %d exceeds 3 workers", peak)
+	}
+}
+
+func TestRun_NoJobs(t *testing.T) {
+	Run(0, DefaultWorkers, func(int) { t.Fatal("fn should not be called") })
+}